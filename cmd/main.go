@@ -10,26 +10,34 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/config"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/auth"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/balances"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/basic"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/categories"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/exchangerates"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/expenses"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/exports"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/households"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/invitations"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/core/reports"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/hash/passwordhash"
 	m "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/router"
 	database "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/db"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/dbstore"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/filestore"
 )
 
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	r := chi.NewRouter()
 
 	cfg := config.MustLoadConfig()
 
+	if len(cfg.AdminUserIDs) == 0 {
+		logger.Warn("No administrator configured: exchange rates cannot be added or imported, so expenses in foreign currencies fail until ADMIN_USER_IDS is set")
+	}
+
 	db := database.MustOpen(cfg.DatabaseName)
 
 	passwordhash := passwordhash.NewPasswordHash()
@@ -59,6 +67,12 @@ func main() {
 		},
 	)
 
+	invitationStore := dbstore.NewInvitationStore(
+		dbstore.NewInvitationStoreParams{
+			DB: db,
+		},
+	)
+
 	expenseStore := dbstore.NewExpenseStore(
 		dbstore.NewExpenseStoreParams{
 			DB: db,
@@ -71,108 +85,331 @@ func main() {
 		},
 	)
 
+	exchangeRateStore := dbstore.NewExchangeRateStore(
+		dbstore.NewExchangeRateStoreParams{
+			DB: db,
+		},
+	)
+
+	paymentStore := dbstore.NewPaymentStore(
+		dbstore.NewPaymentStoreParams{
+			DB: db,
+		},
+	)
+
+	recurringExpenseStore := dbstore.NewRecurringExpenseStore(
+		dbstore.NewRecurringExpenseStoreParams{
+			DB: db,
+		},
+	)
+
+	budgetStore := dbstore.NewBudgetStore(
+		dbstore.NewBudgetStoreParams{
+			DB: db,
+		},
+	)
+
+	categoryStore := dbstore.NewCategoryStore(
+		dbstore.NewCategoryStoreParams{
+			DB: db,
+		},
+	)
+
 	reportStore := dbstore.NewReportStore(
 		dbstore.NewReportStoreParams{
 			DB: db,
 		},
 	)
 
-	fileServer := http.FileServer(http.Dir("./web/static"))
+	reportScheduleStore := dbstore.NewReportScheduleStore(
+		dbstore.NewReportScheduleStoreParams{
+			DB: db,
+		},
+	)
+
+	reportFileStore := filestore.NewLocalStore(
+		filestore.NewLocalStoreParams{
+			Dir: cfg.ReportDir,
+		},
+	)
 
-	r.Get("/static/*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Cache-Control", "public, max-age=31536000")
-		http.StripPrefix("/static/", fileServer).ServeHTTP(w, r)
-	}))
+	reportQueue := reports.NewReportQueue(reports.ReportQueueParams{
+		ReportStore:       reportStore,
+		ExpenseShareStore: expenseShareStore,
+		PaymentStore:      paymentStore,
+		HouseholdStore:    householdStore,
+		FileStore:         reportFileStore,
+		Workers:           cfg.ReportWorkers,
+		Interval:          cfg.ReportInterval,
+		Retention:         cfg.ReportRetention,
+	})
 
-	authMiddleware := m.NewAuthMiddleware(sessionStore, cfg.SessionCookieName)
+	getBasicHandler := basic.NewGetBasicHandler()
 
-	r.Group(func(r chi.Router) {
-		r.Use(
-			middleware.Logger,
-			authMiddleware.AddUserToContext,
-		)
+	getAuthHandler := auth.NewGetAuthHandler()
 
-		//BASIC
-		r.Get("/", basic.NewGetBasicHandler().GetIndex)
+	membershipHandler := households.NewPostMembershipHandler(households.PostMembershipHandlerParams{
+		MembershipStore:   membershipStore,
+		InvitationStore:   invitationStore,
+		UserStore:         userStore,
+		ExpenseShareStore: expenseShareStore,
+	})
 
-		r.Get("/home", basic.NewGetBasicHandler().GetHome)
+	getInvitationsHandler := invitations.NewGetInvitationsHandler(invitations.GetInvitationsHandlerParams{
+		InvitationStore: invitationStore,
+	})
 
-		//AUTH
-		r.Get("/register", auth.NewGetAuthHandler().GetRegister)
+	invitationHandler := invitations.NewPostInvitationHandler(invitations.PostInvitationHandlerParams{
+		InvitationStore: invitationStore,
+	})
 
-		r.Post("/register", auth.NewPostRegisterHandler(auth.PostRegisterHandlerParams{
-			UserStore: userStore,
-		}).PostRegister)
+	editExpenseHandler := expenses.NewPostEditExpenseHandler(expenses.PostEditExpenseHandlerParams{
+		ExpenseStore:      expenseStore,
+		ExpenseShareStore: expenseShareStore,
+		HouseholdStore:    householdStore,
+		ExchangeRateStore: exchangeRateStore,
+		CategoryStore:     categoryStore,
+	})
 
-		r.Get("/login", auth.NewGetAuthHandler().GetLogin)
+	recurringExpenseHandler := expenses.NewPostRecurringExpenseHandler(expenses.PostRecurringExpenseHandlerParams{
+		RecurringExpenseStore: recurringExpenseStore,
+		HouseholdStore:        householdStore,
+		MembershipStore:       membershipStore,
+		ExchangeRateStore:     exchangeRateStore,
+		CategoryStore:         categoryStore,
+	})
 
-		r.Post("/login", auth.NewPostLoginHandler(auth.PostLoginHandlerParams{
-			UserStore:         userStore,
-			SessionStore:      sessionStore,
-			PasswordHash:      passwordhash,
-			SessionCookieName: cfg.SessionCookieName,
-		}).PostLogin)
+	getBudgetsHandler := expenses.NewGetBudgetsHandler(expenses.GetBudgetsHandlerParams{
+		BudgetStore:       budgetStore,
+		ExpenseShareStore: expenseShareStore,
+		HouseholdStore:    householdStore,
+		ExchangeRateStore: exchangeRateStore,
+		CategoryStore:     categoryStore,
+	})
+
+	budgetHandler := expenses.NewPostBudgetHandler(expenses.PostBudgetHandlerParams{
+		BudgetStore:    budgetStore,
+		HouseholdStore: householdStore,
+		CategoryStore:  categoryStore,
+	})
 
-		r.Post("/logout", auth.NewPostLogoutHandler(auth.PostLogoutHandlerParams{
-			SessionStore:      sessionStore,
-			SessionCookieName: cfg.SessionCookieName,
-		}).PostLogout)
+	categoryHandler := categories.NewPostCategoryHandler(categories.PostCategoryHandlerParams{
+		CategoryStore: categoryStore,
+	})
 
-		//HOUSEHOLDS
-		r.Get("/households", households.NewGetHouseholdsHandler(households.GetHouseholdsHandlerParams{
-			HouseholdStore: householdStore,
-			UserStore:      userStore,
-		}).GetHouseholds)
+	exportHandler := exports.NewGetExportHandler(exports.GetExportHandlerParams{
+		HouseholdStore:    householdStore,
+		MembershipStore:   membershipStore,
+		ExpenseStore:      expenseStore,
+		ExpenseShareStore: expenseShareStore,
+		PaymentStore:      paymentStore,
+	})
+
+	exchangeRateHandler := exchangerates.NewPostExchangeRateHandler(exchangerates.PostExchangeRateHandlerParams{
+		ExchangeRateStore: exchangeRateStore,
+	})
 
-		r.Get("/household/{id}/members", households.NewGetHouseholdMembersHandler(households.GetHouseholdMembersHandlerParams{
-			MembershipStore: membershipStore,
-		}).GetHouseholdMembers)
+	getReportHandler := reports.NewGetReportHandler(reports.GetReportHandlerParams{
+		ReportStore:     reportStore,
+		MembershipStore: membershipStore,
+		FileStore:       reportFileStore,
+	})
 
-		r.Get("/household/{id}/expenses", households.NewGetHouseholdExpensesHandler(households.GetHouseholdExpensesHandlerParams{
-			ExpenseStore: expenseStore,
-		}).GetHouseholdExpenses)
+	reportHandler := reports.NewPostReportsHandler(reports.PostReportHandlerParams{
+		ReportStore:     reportStore,
+		HouseholdStore:  householdStore,
+		MembershipStore: membershipStore,
+		ReportQueue:     reportQueue,
+	})
 
-		r.Post("/household", households.NewPostHouseholdHandler(households.PostHouseholdHandlerParams{
-			HouseholdStore:  householdStore,
-			MembershipStore: membershipStore,
-			UserStore:       userStore,
-		}).PostHousehold)
+	reportScheduleHandler := reports.NewPostReportScheduleHandler(reports.PostReportScheduleHandlerParams{
+		ReportScheduleStore: reportScheduleStore,
+		HouseholdStore:      householdStore,
+	})
 
-		//EXPENSES
-		r.Get("/expenses", expenses.NewGetExpensesHandler(expenses.GetExpensesHandlerParams{
-			HouseholdStore:    householdStore,
-			ExpenseShareStore: expenseShareStore,
-		}).GetExpenses)
+	r := router.New(router.Params{
+		AuthMiddleware:  m.NewAuthMiddleware(sessionStore, cfg.SessionCookieName),
+		MembershipStore: membershipStore,
+		ExpenseStore:    expenseStore,
+		AdminIDs:        cfg.AdminUserIDs,
+		StaticDir:       "./web/static",
+		Handlers: router.Handlers{
+			GetIndex:    getBasicHandler.GetIndex,
+			GetHome:     getBasicHandler.GetHome,
+			GetRegister: getAuthHandler.GetRegister,
+			PostRegister: auth.NewPostRegisterHandler(auth.PostRegisterHandlerParams{
+				UserStore:       userStore,
+				InvitationStore: invitationStore,
+			}).PostRegister,
+			GetLogin: getAuthHandler.GetLogin,
+			PostLogin: auth.NewPostLoginHandler(auth.PostLoginHandlerParams{
+				UserStore:         userStore,
+				SessionStore:      sessionStore,
+				PasswordHash:      passwordhash,
+				SessionCookieName: cfg.SessionCookieName,
+			}).PostLogin,
+			PostLogout: auth.NewPostLogoutHandler(auth.PostLogoutHandlerParams{
+				SessionStore:      sessionStore,
+				SessionCookieName: cfg.SessionCookieName,
+			}).PostLogout,
+
+			GetHouseholds: households.NewGetHouseholdsHandler(households.GetHouseholdsHandlerParams{
+				HouseholdStore: householdStore,
+				UserStore:      userStore,
+			}).GetHouseholds,
+			GetHouseholdMembers: households.NewGetHouseholdMembersHandler(households.GetHouseholdMembersHandlerParams{
+				MembershipStore: membershipStore,
+				InvitationStore: invitationStore,
+			}).GetHouseholdMembers,
+			GetHouseholdExpenses: households.NewGetHouseholdExpensesHandler(households.GetHouseholdExpensesHandlerParams{
+				ExpenseStore: expenseStore,
+			}).GetHouseholdExpenses,
+			GetBalances: balances.NewGetBalancesHandler(balances.GetBalancesHandlerParams{
+				HouseholdStore:    householdStore,
+				MembershipStore:   membershipStore,
+				ExpenseShareStore: expenseShareStore,
+			}).GetBalances,
+			PostSettle: balances.NewPostSettleHandler(balances.PostSettleHandlerParams{
+				ExpenseShareStore: expenseShareStore,
+				PaymentStore:      paymentStore,
+			}).PostSettle,
+			PostInvite:            membershipHandler.PostInvite,
+			PostChangeRole:        membershipHandler.PostChangeRole,
+			PostRemoveMember:      membershipHandler.PostRemoveMember,
+			PostTransferOwnership: membershipHandler.PostTransferOwnership,
+			PostLeave:             membershipHandler.PostLeave,
+			PostHousehold: households.NewPostHouseholdHandler(households.PostHouseholdHandlerParams{
+				HouseholdStore:  householdStore,
+				MembershipStore: membershipStore,
+				InvitationStore: invitationStore,
+				UserStore:       userStore,
+			}).PostHousehold,
+
+			GetInvitations:        getInvitationsHandler.GetInvitations,
+			GetInvitation:         getInvitationsHandler.GetInvitation,
+			PostAcceptInvitation:  invitationHandler.PostAccept,
+			PostDeclineInvitation: invitationHandler.PostDecline,
+
+			GetExpenses: expenses.NewGetExpensesHandler(expenses.GetExpensesHandlerParams{
+				HouseholdStore:    householdStore,
+				ExpenseShareStore: expenseShareStore,
+			}).GetExpenses,
+			GetExpensesChart: expenses.NewGetExpensesChartHandler(expenses.GetExpensesChartHandlerParams{
+				ExpenseShareStore: expenseShareStore,
+				ExchangeRateStore: exchangeRateStore,
+			}).GetExpensesChart,
+			PostExpense: expenses.NewPostExpenseHandler(expenses.PostExpenseHandlerParams{
+				ExpenseStore:      expenseStore,
+				ExpenseShareStore: expenseShareStore,
+				HouseholdStore:    householdStore,
+				MembershipStore:   membershipStore,
+				ExchangeRateStore: exchangeRateStore,
+				UserStore:         userStore,
+				CategoryStore:     categoryStore,
+			}).PostExpense,
+			PostPayExpenseShare: expenses.NewPostExpenseShareHandler(expenses.PostExpenseShareHandlerParams{
+				ExpenseShareStore: expenseShareStore,
+				PaymentStore:      paymentStore,
+			}).PostPayExpenseShare,
+			GetEditExpense: expenses.NewGetEditExpenseHandler(expenses.GetEditExpenseHandlerParams{
+				ExpenseStore:      expenseStore,
+				ExpenseShareStore: expenseShareStore,
+				CategoryStore:     categoryStore,
+			}).GetEditExpense,
+			PostEditExpense:   editExpenseHandler.PostEditExpense,
+			PostDeleteExpense: editExpenseHandler.PostDeleteExpense,
+			GetRecurringExpenses: expenses.NewGetRecurringExpensesHandler(expenses.GetRecurringExpensesHandlerParams{
+				RecurringExpenseStore: recurringExpenseStore,
+				MembershipStore:       membershipStore,
+				CategoryStore:         categoryStore,
+			}).GetRecurringExpenses,
+			PostRecurringExpense:       recurringExpenseHandler.PostRecurringExpense,
+			PostDeleteRecurringExpense: recurringExpenseHandler.PostDeleteRecurringExpense,
+			GetImportExpenses:          expenses.NewGetImportExpensesHandler().GetImportExpenses,
+			PostImportExpenses: expenses.NewPostImportExpensesHandler(expenses.PostImportExpensesHandlerParams{
+				ExpenseStore:    expenseStore,
+				HouseholdStore:  householdStore,
+				MembershipStore: membershipStore,
+				CategoryStore:   categoryStore,
+			}).PostImportExpenses,
+
+			GetBudgets:        getBudgetsHandler.GetBudgets,
+			GetBudgetWarnings: getBudgetsHandler.GetBudgetWarnings,
+			GetBudgetCheck:    getBudgetsHandler.GetBudgetCheck,
+			PostBudget:        budgetHandler.PostBudget,
+			PostDeleteBudget:  budgetHandler.PostDeleteBudget,
+
+			GetCategories: categories.NewGetCategoriesHandler(categories.GetCategoriesHandlerParams{
+				CategoryStore: categoryStore,
+			}).GetCategories,
+			PostCategory:       categoryHandler.PostCategory,
+			PostUpdateCategory: categoryHandler.PostUpdateCategory,
+
+			ExportHousehold: exportHandler.ExportHousehold,
+			ExportUser:      exportHandler.ExportUser,
+
+			GetExchangeRates: exchangerates.NewGetExchangeRatesHandler(exchangerates.GetExchangeRatesHandlerParams{
+				ExchangeRateStore: exchangeRateStore,
+				AdminIDs:          cfg.AdminUserIDs,
+			}).GetExchangeRates,
+			PostExchangeRate:        exchangeRateHandler.PostExchangeRate,
+			PostImportExchangeRates: exchangeRateHandler.PostImportExchangeRates,
+
+			GetReports: reports.NewGetReportsHandler(reports.GetReportsHandlerParams{
+				ReportStore:         reportStore,
+				HouseholdStore:      householdStore,
+				ReportScheduleStore: reportScheduleStore,
+			}).GetReports,
+			DownloadReport:           getReportHandler.DownloadReport,
+			GetReportStatus:          getReportHandler.GetReportStatus,
+			PostGenerateReport:       reportHandler.PostGenerateReport,
+			PostRegenerateReport:     reportHandler.PostRegenerateReport,
+			PostDeleteReport:         reportHandler.PostDeleteReport,
+			PostCreateReportSchedule: reportScheduleHandler.PostCreateReportSchedule,
+			PostDeleteReportSchedule: reportScheduleHandler.PostDeleteReportSchedule,
+			GetHouseholdReports: reports.NewGetHouseholdReportsHandler(reports.GetHouseholdReportsHandlerParams{
+				ReportStore:   reportStore,
+				CategoryStore: categoryStore,
+			}).GetHouseholdReports,
+			PostGenerateHouseholdReport: reportHandler.PostGenerateHouseholdReport,
+		},
+	})
 
-		r.Get("/expenses/chart", expenses.NewGetExpensesChartHandler(expenses.GetExpensesChartHandlerParams{
-			ExpenseShareStore: expenseShareStore,
-		}).GetExpensesChart)
+	scheduler := expenses.NewRecurringScheduler(expenses.RecurringSchedulerParams{
+		RecurringExpenseStore: recurringExpenseStore,
+		MembershipStore:       membershipStore,
+		ExchangeRateStore:     exchangeRateStore,
+		Interval:              cfg.RecurringInterval,
+	})
 
-		r.Post("/expense", expenses.NewPostExpenseHandler(expenses.PostExpenseHandlerParams{
-			ExpenseStore:      expenseStore,
-			ExpenseShareStore: expenseShareStore,
-			MembershipStore:   membershipStore,
-			UserStore:         userStore,
-		}).PostExpense)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
 
-		r.Post("/expense/{id}/pay", expenses.NewPostExpenseShareHandler(expenses.PostExpenseShareHandlerParams{
-			ExpenseShareStore: expenseShareStore,
-		}).PostPayExpenseShare)
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(schedulerCtx)
+	}()
 
-		//REPORTS
-		r.Get("/reports", reports.NewGetReportsHandler(reports.GetReportsHandlerParams{
-			ReportStore: reportStore,
-		}).GetReports)
+	reportQueueDone := make(chan struct{})
 
-		r.Get("/reports/files/{file}", reports.NewGetReportHandler(reports.GetReportHandlerParams{
-			ReportStore: reportStore,
-		}).DownloadPDF)
+	go func() {
+		defer close(reportQueueDone)
+		reportQueue.Run(schedulerCtx)
+	}()
 
-		r.Post("/report", reports.NewPostReportsHandler(reports.PostReportHandlerParams{
-			ReportStore: reportStore,
-		}).PostGenerateReport)
+	reportScheduler := reports.NewReportScheduler(reports.ReportSchedulerParams{
+		ReportStore:         reportStore,
+		ReportScheduleStore: reportScheduleStore,
+		Interval:            cfg.ReportInterval,
 	})
 
+	reportSchedulerDone := make(chan struct{})
+
+	go func() {
+		defer close(reportSchedulerDone)
+		reportScheduler.Run(schedulerCtx)
+	}()
+
 	killSig := make(chan os.Signal, 1)
 
 	signal.Notify(killSig, os.Interrupt, syscall.SIGTERM)
@@ -198,6 +435,14 @@ func main() {
 
 	logger.Info("Shutting down server")
 
+	stopScheduler()
+	<-schedulerDone
+	logger.Info("Recurring expense scheduler stopped")
+	<-reportQueueDone
+	logger.Info("Report queue stopped")
+	<-reportSchedulerDone
+	logger.Info("Report scheduler stopped")
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
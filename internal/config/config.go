@@ -1,11 +1,21 @@
 package config
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
 
 type Config struct {
-	Port              string `envconfig:"PORT" default:":8080"`
-	DatabaseName      string `envconfig:"DATABASE_NAME" default:"hpb.db"`
-	SessionCookieName string `envconfig:"SESSION_COOKIE_NAME" default:"session"`
+	Port              string        `envconfig:"PORT" default:":8080"`
+	DatabaseName      string        `envconfig:"DATABASE_NAME" default:"hpb.db"`
+	SessionCookieName string        `envconfig:"SESSION_COOKIE_NAME" default:"session"`
+	RecurringInterval time.Duration `envconfig:"RECURRING_INTERVAL" default:"1m"`
+	ReportWorkers     int           `envconfig:"REPORT_WORKERS" default:"2"`
+	ReportInterval    time.Duration `envconfig:"REPORT_INTERVAL" default:"30s"`
+	ReportDir         string        `envconfig:"REPORT_DIR" default:"./files/reports"`
+	ReportRetention   time.Duration `envconfig:"REPORT_RETENTION" default:"0"`
+	AdminUserIDs      []uint        `envconfig:"ADMIN_USER_IDS"`
 }
 
 func loadConfig() (*Config, error) {
@@ -3,7 +3,9 @@ package auth
 import (
 	"encoding/base64"
 	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	templBasic "github.com/a-h/templ"
@@ -20,8 +22,10 @@ func NewGetAuthHandler() *GetAuthHandler {
 	return &GetAuthHandler{}
 }
 
+// GetRegister renders the sign-up form. An invitation link sends logged-out
+// users here with the token of the invitation, which is accepted on sign up.
 func (h *GetAuthHandler) GetRegister(w http.ResponseWriter, r *http.Request) {
-	c := templ.Register()
+	c := templ.Register(r.URL.Query().Get("invitation"))
 	err := templ.Layout(c, "Sign up | Home Piggy Bank", false, nil).Render(r.Context(), w)
 
 	if err != nil {
@@ -51,16 +55,19 @@ func (h *GetAuthHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
 }
 
 type PostRegisterHandler struct {
-	userStore store.UserStore
+	userStore       store.UserStore
+	invitationStore store.InvitationStore
 }
 
 type PostRegisterHandlerParams struct {
-	UserStore store.UserStore
+	UserStore       store.UserStore
+	InvitationStore store.InvitationStore
 }
 
 func NewPostRegisterHandler(params PostRegisterHandlerParams) *PostRegisterHandler {
 	return &PostRegisterHandler{
-		userStore: params.UserStore,
+		userStore:       params.UserStore,
+		invitationStore: params.InvitationStore,
 	}
 }
 
@@ -104,10 +111,36 @@ func (h *PostRegisterHandler) PostRegister(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Signing up through an invitation link accepts that invitation, as long
+	// as it was sent to the address the account was created with. The email
+	// is not verified, so holding the link is what proves the invitation is
+	// meant for this user. If accepting fails the invitation stays open and
+	// the link can still be used after logging in.
+	if token := r.FormValue("invitation"); token != "" {
+		h.acceptInvitation(token, email)
+	}
+
 	w.Header().Set("HX-Redirect", "/login?from=register-success")
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h *PostRegisterHandler) acceptInvitation(token, email string) {
+	invitation, err := h.invitationStore.GetInvitationByToken(token)
+	if err != nil || !strings.EqualFold(invitation.Email, strings.TrimSpace(email)) {
+		return
+	}
+
+	user, err := h.userStore.GetUser(email)
+	if err != nil {
+		log.Printf("failed to load new user %s: %v", email, err)
+		return
+	}
+
+	if err := h.invitationStore.AcceptInvitation(token, user.ID); err != nil {
+		log.Printf("failed to accept invitation for %s: %v", email, err)
+	}
+}
+
 type PostLoginHandler struct {
 	userStore         store.UserStore
 	sessionStore      store.SessionStore
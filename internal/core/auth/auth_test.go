@@ -67,8 +67,11 @@ func TestPostRegister_Success(t *testing.T) {
 	userStore.On("EmailExists", "test@test.com").Return(false, nil)
 	userStore.On("CreateUser", "testuser", "test@test.com", "secret").Return(nil)
 
+	invitationStore := &storemock.InvitationStoreMock{}
+
 	handler := NewPostRegisterHandler(PostRegisterHandlerParams{
-		UserStore: userStore,
+		UserStore:       userStore,
+		InvitationStore: invitationStore,
 	})
 
 	form := url.Values{}
@@ -88,4 +91,55 @@ func TestPostRegister_Success(t *testing.T) {
 	require.Equal(t, "/login?from=register-success", resp.Header.Get("HX-Redirect"))
 
 	userStore.AssertExpectations(t)
+	invitationStore.AssertExpectations(t)
+}
+
+func TestPostRegister_Invitation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		email  string
+		accept bool
+	}{
+		{name: "sent to the new address", email: "test@test.com", accept: true},
+		{name: "sent to another address", email: "other@test.com", accept: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userStore := &storemock.UserStoreMock{}
+			userStore.On("UsernameExists", "testuser").Return(false, nil)
+			userStore.On("EmailExists", "test@test.com").Return(false, nil)
+			userStore.On("CreateUser", "testuser", "test@test.com", "secret").Return(nil)
+
+			invitationStore := &storemock.InvitationStoreMock{}
+			invitationStore.On("GetInvitationByToken", "token").Return(store.Invitation{Token: "token", Email: tc.email}, nil)
+
+			if tc.accept {
+				userStore.On("GetUser", "test@test.com").Return(&store.User{ID: 7, Email: "test@test.com"}, nil)
+				invitationStore.On("AcceptInvitation", "token", uint(7)).Return(nil)
+			}
+
+			handler := NewPostRegisterHandler(PostRegisterHandlerParams{
+				UserStore:       userStore,
+				InvitationStore: invitationStore,
+			})
+
+			form := url.Values{}
+			form.Set("username", "testuser")
+			form.Set("email", "test@test.com")
+			form.Set("password", "secret")
+			form.Set("invitation", "token")
+
+			req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			w := httptest.NewRecorder()
+			handler.PostRegister(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			userStore.AssertExpectations(t)
+			invitationStore.AssertExpectations(t)
+		})
+	}
 }
@@ -0,0 +1,198 @@
+package balances
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+type GetBalancesHandler struct {
+	householdStore    store.HouseholdStore
+	membershipStore   store.MembershipStore
+	expenseShareStore store.ExpenseShareStore
+}
+
+type GetBalancesHandlerParams struct {
+	HouseholdStore    store.HouseholdStore
+	MembershipStore   store.MembershipStore
+	ExpenseShareStore store.ExpenseShareStore
+}
+
+func NewGetBalancesHandler(params GetBalancesHandlerParams) *GetBalancesHandler {
+	return &GetBalancesHandler{
+		householdStore:    params.HouseholdStore,
+		membershipStore:   params.MembershipStore,
+		expenseShareStore: params.ExpenseShareStore,
+	}
+}
+
+func (h *GetBalancesHandler) GetBalances(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	householdID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid household id", http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.householdStore.GetHouseholdByID(uint(householdID))
+	if err != nil {
+		http.Error(w, "household not found", http.StatusNotFound)
+		return
+	}
+
+	members, err := h.membershipStore.GetMembersByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch members", http.StatusInternalServerError)
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetSharesByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch expense shares", http.StatusInternalServerError)
+		return
+	}
+
+	balances := netBalances(members, shares, household.BaseCurrency)
+	transfers := settleUp(balances)
+
+	membership := middleware.GetMembership(r.Context())
+	canSettle := membership != nil && membership.Can(store.PermMarkOthersPaid)
+
+	err = templ.HouseholdBalances(household.ID, balances, transfers, lastShareID(shares), canSettle).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostSettleHandler struct {
+	expenseShareStore store.ExpenseShareStore
+	paymentStore      store.PaymentStore
+}
+
+type PostSettleHandlerParams struct {
+	ExpenseShareStore store.ExpenseShareStore
+	PaymentStore      store.PaymentStore
+}
+
+func NewPostSettleHandler(params PostSettleHandlerParams) *PostSettleHandler {
+	return &PostSettleHandler{
+		expenseShareStore: params.ExpenseShareStore,
+		paymentStore:      params.PaymentStore,
+	}
+}
+
+// PostSettle records that the transfers shown on the balances view were made
+// by paying off every share they covered with a settlement payment. It pays
+// off shares of other members, so the route requires PermMarkOthersPaid. Shares
+// created after the view was rendered are left open, so an expense added in the
+// meantime is not settled by accident.
+func (h *PostSettleHandler) PostSettle(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	householdID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid household id", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	settledThrough, err := strconv.ParseUint(r.FormValue("settled_through"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Settle up failed", "Reload the balances and try again")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetSharesByHouseholdID(uint(householdID))
+	if err != nil {
+		http.Error(w, "cannot fetch expense shares", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	byExpense := sharesByExpense(shares)
+
+	var payments []store.Payment
+	for _, s := range shares {
+		if s.ID > uint(settledThrough) {
+			continue
+		}
+
+		owed := s.Outstanding()
+		if owed.IsZero() {
+			continue
+		}
+
+		for i, part := range s.Expense.OwedTo(owed, byExpense[s.ExpenseID]) {
+			if part.IsZero() {
+				continue
+			}
+
+			payments = append(payments, store.Payment{
+				ExpenseShareID: s.ID,
+				PayerID:        s.UserID,
+				PayeeID:        s.Expense.Payers[i].UserID,
+				Amount:         part,
+				PaidOn:         now,
+				Method:         store.PaymentSettlement,
+				Note:           fmt.Sprintf("Settled up by %s", user.Username),
+			})
+		}
+	}
+
+	if len(payments) == 0 {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Settle up failed", "Nothing to settle")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	err = h.paymentStore.CreatePayments(payments)
+	if errors.Is(err, store.ErrOverpayment) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Settle up failed", "Some shares were paid in the meantime, please try again")
+		c.Render(r.Context(), w)
+		return
+	}
+	if err != nil {
+		http.Error(w, "cannot settle household", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+func lastShareID(shares []store.ExpenseShare) uint {
+	var last uint
+	for _, s := range shares {
+		last = max(last, s.ID)
+	}
+	return last
+}
@@ -0,0 +1,132 @@
+package balances
+
+import (
+	"sort"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// netBalances computes the net position of every member of a household. The
+// outstanding part of each share is owed by its user to the payers of the
+// expense, split as store.Expense.OwedTo describes, so it lowers the user's
+// balance and raises the payers' by the same amount. Payments already made and
+// what payers covered of their own shares do not count.
+//
+// Users who still have open shares but are no longer members are included, so
+// the balances always add up to zero. The result is ordered by user ID.
+func netBalances(members []store.Membership, shares []store.ExpenseShare, currency string) []store.Balance {
+	byUser := make(map[uint]*store.Balance)
+
+	add := func(user store.User) *store.Balance {
+		b, ok := byUser[user.ID]
+		if !ok {
+			b = &store.Balance{User: user, Net: money.New(0, currency)}
+			byUser[user.ID] = b
+		}
+		return b
+	}
+
+	for _, m := range members {
+		add(m.User)
+	}
+
+	byExpense := sharesByExpense(shares)
+
+	for _, s := range shares {
+		owed := s.Outstanding()
+		if owed.IsZero() {
+			continue
+		}
+
+		debtor := add(s.User)
+		debtor.Net = debtor.Net.Sub(owed)
+
+		for i, part := range s.Expense.OwedTo(owed, byExpense[s.ExpenseID]) {
+			creditor := add(s.Expense.Payers[i].User)
+			creditor.Net = creditor.Net.Add(part)
+		}
+	}
+
+	balances := make([]store.Balance, 0, len(byUser))
+	for _, b := range byUser {
+		balances = append(balances, *b)
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].User.ID < balances[j].User.ID
+	})
+
+	return balances
+}
+
+// sharesByExpense groups shares by the expense they belong to.
+func sharesByExpense(shares []store.ExpenseShare) map[uint][]store.ExpenseShare {
+	byExpense := make(map[uint][]store.ExpenseShare)
+	for _, s := range shares {
+		byExpense[s.ExpenseID] = append(byExpense[s.ExpenseID], s)
+	}
+	return byExpense
+}
+
+// settleUp returns transfers that bring every balance to zero. It repeatedly
+// lets the member who owes the most pay the member who is owed the most, which
+// settles at least one of them with every transfer and therefore needs at most
+// one transfer fewer than there are members with a non-zero balance. Ties are
+// broken by user ID so the plan is stable between page loads.
+func settleUp(balances []store.Balance) []store.Transfer {
+	var creditors, debtors []store.Balance
+	for _, b := range balances {
+		switch {
+		case b.Net.Minor > 0:
+			creditors = append(creditors, b)
+		case b.Net.Minor < 0:
+			debtors = append(debtors, store.Balance{User: b.User, Net: money.New(-b.Net.Minor, b.Net.Currency)})
+		}
+	}
+
+	var transfers []store.Transfer
+
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sortByAmount(creditors)
+		sortByAmount(debtors)
+
+		amount := creditors[0].Net
+		if debtors[0].Net.Minor < amount.Minor {
+			amount = debtors[0].Net
+		}
+
+		transfers = append(transfers, store.Transfer{
+			From:   debtors[0].User,
+			To:     creditors[0].User,
+			Amount: amount,
+		})
+
+		creditors[0].Net = creditors[0].Net.Sub(amount)
+		debtors[0].Net = debtors[0].Net.Sub(amount)
+
+		creditors = dropSettled(creditors)
+		debtors = dropSettled(debtors)
+	}
+
+	return transfers
+}
+
+func sortByAmount(balances []store.Balance) {
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Net.Minor != balances[j].Net.Minor {
+			return balances[i].Net.Minor > balances[j].Net.Minor
+		}
+		return balances[i].User.ID < balances[j].User.ID
+	})
+}
+
+func dropSettled(balances []store.Balance) []store.Balance {
+	open := balances[:0]
+	for _, b := range balances {
+		if !b.Net.IsZero() {
+			open = append(open, b)
+		}
+	}
+	return open
+}
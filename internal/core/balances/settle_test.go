@@ -0,0 +1,160 @@
+package balances
+
+import (
+	"testing"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func pln(minor int64) money.Money {
+	return money.New(minor, "PLN")
+}
+
+func share(userID, payerID uint, minor int64, paidMinor int64) store.ExpenseShare {
+	var payments []store.Payment
+	if paidMinor > 0 {
+		payments = append(payments, store.Payment{Amount: pln(paidMinor)})
+	}
+
+	var covered money.Money
+	if userID == payerID {
+		covered = pln(minor)
+	}
+
+	return store.ExpenseShare{
+		UserID: userID,
+		User:   store.User{ID: userID},
+		Expense: store.Expense{
+			Payers: []store.ExpensePayer{{UserID: payerID, User: store.User{ID: payerID}, Amount: pln(minor)}},
+		},
+		Amount:   pln(minor),
+		Covered:  covered,
+		Paid:     covered.Minor+paidMinor >= minor,
+		Payments: payments,
+	}
+}
+
+func TestNetBalances(t *testing.T) {
+	members := []store.Membership{
+		{UserID: 1, User: store.User{ID: 1}},
+		{UserID: 2, User: store.User{ID: 2}},
+		{UserID: 3, User: store.User{ID: 3}},
+	}
+
+	shares := []store.ExpenseShare{
+		share(1, 1, 1000, 0), // payer's own share
+		share(2, 1, 1000, 0),
+		share(3, 1, 1000, 1000), // already paid
+		share(3, 1, 800, 300),   // partly paid
+		share(1, 2, 500, 0),
+		share(4, 3, 250, 0), // former member
+	}
+
+	balances := netBalances(members, shares, "PLN")
+
+	got := make(map[uint]money.Money)
+	var total int64
+	for _, b := range balances {
+		got[b.User.ID] = b.Net
+		total += b.Net.Minor
+	}
+
+	require.Equal(t, map[uint]money.Money{
+		1: pln(1000),
+		2: pln(-500),
+		3: pln(-250),
+		4: pln(-250),
+	}, got)
+	require.Zero(t, total)
+}
+
+func TestNetBalances_SeveralPayers(t *testing.T) {
+	// Users 1 and 2 paid 50 and 40 for an expense of 90 split equally, so
+	// each covered their own share of 30 and user 3 owes the 20 and 10 they
+	// paid beyond it.
+	expense := store.Expense{
+		ID: 1,
+		Payers: []store.ExpensePayer{
+			{UserID: 1, User: store.User{ID: 1}, Amount: pln(5000)},
+			{UserID: 2, User: store.User{ID: 2}, Amount: pln(4000)},
+		},
+	}
+
+	shares := []store.ExpenseShare{
+		{ExpenseID: 1, Expense: expense, UserID: 1, User: store.User{ID: 1}, Amount: pln(3000), Covered: pln(3000)},
+		{ExpenseID: 1, Expense: expense, UserID: 2, User: store.User{ID: 2}, Amount: pln(3000), Covered: pln(3000)},
+		{ExpenseID: 1, Expense: expense, UserID: 3, User: store.User{ID: 3}, Amount: pln(3000)},
+	}
+
+	balances := netBalances(nil, shares, "PLN")
+
+	got := make(map[uint]money.Money)
+	for _, b := range balances {
+		got[b.User.ID] = b.Net
+	}
+
+	require.Equal(t, map[uint]money.Money{
+		1: pln(2000),
+		2: pln(1000),
+		3: pln(-3000),
+	}, got)
+}
+
+func TestSettleUp(t *testing.T) {
+	testCases := []struct {
+		name     string
+		balances map[uint]int64
+		want     []store.Transfer
+	}{
+		{
+			name:     "settled",
+			balances: map[uint]int64{1: 0, 2: 0},
+			want:     nil,
+		},
+		{
+			name:     "one debtor pays two creditors",
+			balances: map[uint]int64{1: 300, 2: 200, 3: -500},
+			want: []store.Transfer{
+				{From: store.User{ID: 3}, To: store.User{ID: 1}, Amount: pln(300)},
+				{From: store.User{ID: 3}, To: store.User{ID: 2}, Amount: pln(200)},
+			},
+		},
+		{
+			name:     "chain collapses into one transfer",
+			balances: map[uint]int64{1: 1000, 2: 0, 3: -1000},
+			want: []store.Transfer{
+				{From: store.User{ID: 3}, To: store.User{ID: 1}, Amount: pln(1000)},
+			},
+		},
+		{
+			name:     "ties broken by user id",
+			balances: map[uint]int64{1: 100, 2: 100, 3: -100, 4: -100},
+			want: []store.Transfer{
+				{From: store.User{ID: 3}, To: store.User{ID: 1}, Amount: pln(100)},
+				{From: store.User{ID: 4}, To: store.User{ID: 2}, Amount: pln(100)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var balances []store.Balance
+			for id, minor := range tc.balances {
+				balances = append(balances, store.Balance{User: store.User{ID: id}, Net: pln(minor)})
+			}
+
+			transfers := settleUp(balances)
+			require.Equal(t, tc.want, transfers)
+
+			nonZero := 0
+			for _, minor := range tc.balances {
+				if minor != 0 {
+					nonZero++
+				}
+			}
+			require.LessOrEqual(t, len(transfers), max(nonZero-1, 0))
+		})
+	}
+}
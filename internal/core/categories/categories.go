@@ -0,0 +1,230 @@
+package categories
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+const maxCategoryNameLength = 30
+
+type GetCategoriesHandler struct {
+	categoryStore store.CategoryStore
+}
+
+type GetCategoriesHandlerParams struct {
+	CategoryStore store.CategoryStore
+}
+
+func NewGetCategoriesHandler(params GetCategoriesHandlerParams) *GetCategoriesHandler {
+	return &GetCategoriesHandler{
+		categoryStore: params.CategoryStore,
+	}
+}
+
+// GetCategories lists every category of the household, archived ones
+// included, with the forms to manage them for members who may.
+func (h *GetCategoriesHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	categories, err := h.categoryStore.GetCategoriesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.HouseholdCategories(categories, membership).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostCategoryHandler struct {
+	categoryStore store.CategoryStore
+}
+
+type PostCategoryHandlerParams struct {
+	CategoryStore store.CategoryStore
+}
+
+func NewPostCategoryHandler(params PostCategoryHandlerParams) *PostCategoryHandler {
+	return &PostCategoryHandler{
+		categoryStore: params.CategoryStore,
+	}
+}
+
+// PostCategory adds a category to the household, as a subcategory when a
+// parent is given. The parent must be an active top-level category of the
+// same household.
+func (h *PostCategoryHandler) PostCategory(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermManageCategories) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	category := store.Category{
+		HouseholdID: membership.HouseholdID,
+		Name:        strings.TrimSpace(r.FormValue("name")),
+		Colour:      strings.ToLower(r.FormValue("colour")),
+	}
+
+	if parent := r.FormValue("parent_id"); parent != "" {
+		parentID, err := strconv.ParseUint(parent, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid parent category", http.StatusBadRequest)
+			return
+		}
+
+		p, err := h.categoryStore.GetCategoryByID(uint(parentID))
+		if err != nil || p.HouseholdID != membership.HouseholdID || p.ParentID != nil || p.Archived {
+			http.Error(w, "invalid parent category", http.StatusBadRequest)
+			return
+		}
+
+		category.ParentID = &p.ID
+	}
+
+	existing, err := h.categoryStore.GetCategoriesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	if !validCategory(w, r, category, existing, "Create failed") {
+		return
+	}
+
+	if _, err := h.categoryStore.CreateCategory(category); err != nil {
+		http.Error(w, "cannot create category", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostUpdateCategory renames, recolours, archives or restores a category.
+// Archiving keeps the category on the expenses already in it.
+func (h *PostCategoryHandler) PostUpdateCategory(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermManageCategories) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	categoryID, err := strconv.ParseUint(chi.URLParam(r, "categoryID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid category id", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.categoryStore.GetCategoryByID(uint(categoryID))
+	if err != nil || category.HouseholdID != membership.HouseholdID {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+
+	category.Name = strings.TrimSpace(r.FormValue("name"))
+	category.Colour = strings.ToLower(r.FormValue("colour"))
+	category.Archived = r.FormValue("archived") == "true"
+
+	existing, err := h.categoryStore.GetCategoriesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	if !validCategory(w, r, category, existing, "Update failed") {
+		return
+	}
+
+	if err := h.categoryStore.UpdateCategory(category); err != nil {
+		http.Error(w, "cannot update category", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// validCategory checks the name and colour of category and that no other
+// category under the same parent has its name. On failure it writes the
+// alert and returns false.
+func validCategory(w http.ResponseWriter, r *http.Request, category store.Category, existing []store.Category, title string) bool {
+	if len(category.Name) == 0 || len(category.Name) > maxCategoryNameLength {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(
+			title,
+			fmt.Sprintf("Category name must have between 1 and %d characters", maxCategoryNameLength),
+		)
+		c.Render(r.Context(), w)
+		return false
+	}
+
+	if !store.IsValidColour(category.Colour) {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(title, "Colour must be a hex colour such as #36a2eb")
+		c.Render(r.Context(), w)
+		return false
+	}
+
+	for _, c := range existing {
+		if c.ID != category.ID && sameParent(c, category) && strings.EqualFold(c.Name, category.Name) {
+			w.WriteHeader(http.StatusConflict)
+			c := templAlerts.Error(title, "Category with this name already exists")
+			c.Render(r.Context(), w)
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameParent(a, b store.Category) bool {
+	if a.ParentID == nil || b.ParentID == nil {
+		return a.ParentID == nil && b.ParentID == nil
+	}
+	return *a.ParentID == *b.ParentID
+}
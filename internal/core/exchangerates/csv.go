@@ -0,0 +1,113 @@
+package exchangerates
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// parseCSV reads exchange rates from CSV rows of the form
+//
+//	date,from,to,rate
+//	2025-01-31,EUR,PLN,4.2135
+//
+// A first row that does not start with a date is treated as a header. Commas
+// and semicolons are both accepted as separators.
+func parseCSV(r io.Reader) ([]store.ExchangeRate, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	if strings.Count(firstLine(string(content)), ";") >= 3 {
+		reader.Comma = ';'
+	}
+
+	var rates []store.ExchangeRate
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if line == 1 && !looksLikeDate(record[0]) {
+			continue
+		}
+
+		rate, err := parseRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rates = append(rates, rate)
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("file contains no exchange rates")
+	}
+
+	return rates, nil
+}
+
+func parseRecord(record []string) (store.ExchangeRate, error) {
+	validOn, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+	if err != nil {
+		return store.ExchangeRate{}, fmt.Errorf("invalid date %q", record[0])
+	}
+
+	return newExchangeRate(validOn, record[1], record[2], record[3])
+}
+
+// newExchangeRate validates a single rate as entered by the user.
+func newExchangeRate(validOn time.Time, from, to, rate string) (store.ExchangeRate, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+
+	if !money.IsSupported(from) {
+		return store.ExchangeRate{}, fmt.Errorf("unsupported currency %q", from)
+	}
+
+	if !money.IsSupported(to) {
+		return store.ExchangeRate{}, fmt.Errorf("unsupported currency %q", to)
+	}
+
+	if from == to {
+		return store.ExchangeRate{}, fmt.Errorf("cannot convert %s to itself", from)
+	}
+
+	value, err := money.ParseRate(rate)
+	if err != nil {
+		return store.ExchangeRate{}, fmt.Errorf("invalid rate %q", rate)
+	}
+
+	return store.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		ValidOn:      time.Date(validOn.Year(), validOn.Month(), validOn.Day(), 0, 0, 0, 0, time.UTC),
+		Rate:         value,
+	}, nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func looksLikeDate(s string) bool {
+	_, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	return err == nil
+}
@@ -0,0 +1,63 @@
+package exchangerates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		wantRates []int64
+		wantErr   string
+	}{
+		{
+			name:      "with header",
+			input:     "date,from,to,rate\n2025-01-31,EUR,PLN,4.2135\n2025-01-31,usd,pln,3.9\n",
+			wantRates: []int64{4213500, 3900000},
+		},
+		{
+			name:      "semicolons without header",
+			input:     "2025-01-31;EUR;PLN;4.2135\n",
+			wantRates: []int64{4213500},
+		},
+		{
+			name:    "unsupported currency",
+			input:   "2025-01-31,XYZ,PLN,1\n",
+			wantErr: "line 1",
+		},
+		{
+			name:    "invalid rate",
+			input:   "date,from,to,rate\n2025-01-31,EUR,PLN,abc\n",
+			wantErr: "line 2",
+		},
+		{
+			name:    "empty file",
+			input:   "date,from,to,rate\n",
+			wantErr: "no exchange rates",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rates, err := parseCSV(strings.NewReader(tc.input))
+
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, rates, len(tc.wantRates))
+			for i, rate := range rates {
+				require.Equal(t, tc.wantRates[i], rate.Rate)
+				require.Equal(t, "PLN", rate.ToCurrency)
+				require.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), rate.ValidOn)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package exchangerates
+
+import (
+	"net/http"
+	"time"
+
+	templBasic "github.com/a-h/templ"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+type GetExchangeRatesHandler struct {
+	exchangeRateStore store.ExchangeRateStore
+	adminIDs          []uint
+}
+
+type GetExchangeRatesHandlerParams struct {
+	ExchangeRateStore store.ExchangeRateStore
+	AdminIDs          []uint
+}
+
+func NewGetExchangeRatesHandler(params GetExchangeRatesHandlerParams) *GetExchangeRatesHandler {
+	return &GetExchangeRatesHandler{
+		exchangeRateStore: params.ExchangeRateStore,
+		adminIDs:          params.AdminIDs,
+	}
+}
+
+// GetExchangeRates lists the exchange rates. They convert amounts in every
+// household, so only administrators get the forms to change them.
+func (h *GetExchangeRatesHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	rates, err := h.exchangeRateStore.GetExchangeRates()
+	if err != nil {
+		http.Error(w, "Failed to load exchange rates", http.StatusInternalServerError)
+		return
+	}
+
+	isHX := r.Header.Get("HX-Request") == "true"
+
+	c := templ.ExchangeRates(isHX, rates, middleware.IsAdmin(r.Context(), h.adminIDs))
+
+	var out templBasic.Component
+	if isHX {
+		out = c
+	} else {
+		out = templ.Layout(c, "Exchange rates | Home Piggy Bank", true, user)
+	}
+
+	err = out.Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostExchangeRateHandler struct {
+	exchangeRateStore store.ExchangeRateStore
+}
+
+type PostExchangeRateHandlerParams struct {
+	ExchangeRateStore store.ExchangeRateStore
+}
+
+func NewPostExchangeRateHandler(params PostExchangeRateHandlerParams) *PostExchangeRateHandler {
+	return &PostExchangeRateHandler{
+		exchangeRateStore: params.ExchangeRateStore,
+	}
+}
+
+func (h *PostExchangeRateHandler) PostExchangeRate(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	validOn, err := time.Parse("2006-01-02", r.FormValue("valid_on"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Save failed", "Invalid date")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	rate, err := newExchangeRate(validOn, r.FormValue("from_currency"), r.FormValue("to_currency"), r.FormValue("rate"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Save failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if err := h.exchangeRateStore.SaveExchangeRates([]store.ExchangeRate{rate}); err != nil {
+		http.Error(w, "cannot save exchange rate", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/exchange-rates")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *PostExchangeRateHandler) PostImportExchangeRates(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	const maxUploadSize = 1 << 20
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Import failed", "Please choose a CSV file")
+		c.Render(r.Context(), w)
+		return
+	}
+	defer file.Close()
+
+	rates, err := parseCSV(file)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Import failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if err := h.exchangeRateStore.SaveExchangeRates(rates); err != nil {
+		http.Error(w, "cannot save exchange rates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/exchange-rates")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,441 @@
+package expenses
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+type GetBudgetsHandler struct {
+	budgetStore       store.BudgetStore
+	expenseShareStore store.ExpenseShareStore
+	householdStore    store.HouseholdStore
+	exchangeRateStore store.ExchangeRateStore
+	categoryStore     store.CategoryStore
+}
+
+type GetBudgetsHandlerParams struct {
+	BudgetStore       store.BudgetStore
+	ExpenseShareStore store.ExpenseShareStore
+	HouseholdStore    store.HouseholdStore
+	ExchangeRateStore store.ExchangeRateStore
+	CategoryStore     store.CategoryStore
+}
+
+func NewGetBudgetsHandler(params GetBudgetsHandlerParams) *GetBudgetsHandler {
+	return &GetBudgetsHandler{
+		budgetStore:       params.BudgetStore,
+		expenseShareStore: params.ExpenseShareStore,
+		householdStore:    params.HouseholdStore,
+		exchangeRateStore: params.ExchangeRateStore,
+		categoryStore:     params.CategoryStore,
+	}
+}
+
+// GetBudgets shows every budget of the household against what was spent in
+// its current period.
+func (h *GetBudgetsHandler) GetBudgets(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	budgets, err := h.budgetStore.GetBudgetsByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch budgets", http.StatusInternalServerError)
+		return
+	}
+
+	usages, err := h.usages(budgets, time.Now())
+	if err != nil {
+		http.Error(w, "cannot fetch expenses", http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := h.categoryStore.GetCategoriesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.HouseholdBudgets(usages, activeCategories(categories, 0), membership).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetBudgetWarnings lists the active budgets of the user's households that
+// reached the warning threshold.
+func (h *GetBudgetsHandler) GetBudgetWarnings(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	budgets, err := h.budgetStore.GetBudgetsByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch budgets", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+
+	usages, err := h.usages(activeBudgets(budgets, now), now)
+	if err != nil {
+		http.Error(w, "cannot fetch expenses", http.StatusInternalServerError)
+		return
+	}
+
+	templ.BudgetWarnings(overBudget(usages), false).Render(r.Context(), w)
+}
+
+// GetBudgetCheck warns, while an expense is being added, about the budgets
+// the expense would bring to the warning threshold or past it. The fields of
+// the expense form are read as is; while the amount is invalid the budgets
+// are checked as they stand.
+func (h *GetBudgetsHandler) GetBudgetCheck(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	household, err := h.householdStore.GetHouseholdByID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "invalid household", http.StatusBadRequest)
+		return
+	}
+
+	category, err := parseCategory(h.categoryStore, household.ID, r.FormValue("category"), 0)
+	if err != nil {
+		return
+	}
+
+	budgets, err := h.budgetStore.GetBudgetsByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch budgets", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+
+	var matching []store.Budget
+	for _, b := range activeBudgets(budgets, now) {
+		if inCategory(category, b.CategoryID) {
+			matching = append(matching, b)
+		}
+	}
+
+	usages, err := h.usages(matching, now)
+	if err != nil {
+		http.Error(w, "cannot fetch expenses", http.StatusInternalServerError)
+		return
+	}
+
+	amount := h.pendingAmount(r, household, now)
+	for i := range usages {
+		usages[i].Spent = usages[i].Spent.Add(amount)
+	}
+
+	templ.BudgetWarnings(overBudget(usages), true).Render(r.Context(), w)
+}
+
+// pendingAmount is the amount of the expense being added in the household
+// currency, or zero while it cannot be read or converted.
+func (h *GetBudgetsHandler) pendingAmount(r *http.Request, household store.Household, on time.Time) money.Money {
+	zero := money.New(0, household.BaseCurrency)
+
+	currency := r.FormValue("currency")
+	if currency == "" {
+		currency = household.BaseCurrency
+	}
+
+	if !money.IsSupported(currency) {
+		return zero
+	}
+
+	amount, err := money.Parse(r.FormValue("amount"), currency)
+	if err != nil || amount.Minor < 0 {
+		return zero
+	}
+
+	converted, err := h.exchangeRateStore.Convert(amount, household.BaseCurrency, on)
+	if err != nil {
+		return zero
+	}
+
+	return converted
+}
+
+// usages loads, once per household, the shares of the households of budgets
+// made in any of the periods of their budgets containing now, and compares
+// them with the budgets.
+func (h *GetBudgetsHandler) usages(budgets []store.Budget, now time.Time) ([]store.BudgetUsage, error) {
+	var households []uint
+	periods := map[uint][2]time.Time{}
+
+	for _, b := range budgets {
+		from, to := b.PeriodOn(now)
+
+		period, ok := periods[b.HouseholdID]
+		if !ok {
+			households = append(households, b.HouseholdID)
+			period = [2]time.Time{from, to}
+		}
+		if from.Before(period[0]) {
+			period[0] = from
+		}
+		if to.After(period[1]) {
+			period[1] = to
+		}
+		periods[b.HouseholdID] = period
+	}
+
+	var shares []store.ExpenseShare
+	for _, householdID := range households {
+		period := periods[householdID]
+
+		householdShares, err := h.expenseShareStore.GetSharesByHouseholdIDInPeriod(householdID, period[0], period[1], "all")
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, householdShares...)
+	}
+
+	return budgetUsages(budgets, shares, now), nil
+}
+
+// budgetUsages totals, per budget, the shares of expenses in the budget's
+// household and category, subcategories included, made in its period
+// containing now. The shares of an expense add up to its amount, so this is
+// what the household spent.
+func budgetUsages(budgets []store.Budget, shares []store.ExpenseShare, now time.Time) []store.BudgetUsage {
+	usages := make([]store.BudgetUsage, 0, len(budgets))
+
+	for _, b := range budgets {
+		from, to := b.PeriodOn(now)
+
+		var inPeriod []store.ExpenseShare
+		for _, s := range shares {
+			createdOn := s.Expense.CreatedOn
+			if s.Expense.HouseholdID == b.HouseholdID && !createdOn.Before(from) && createdOn.Before(to) {
+				inPeriod = append(inPeriod, s)
+			}
+		}
+
+		category := strconv.FormatUint(uint64(b.CategoryID), 10)
+		spent := money.New(0, b.Limit.Currency).
+			Add(totalsBy(inPeriod, categoryIDOf)[category]).
+			Add(totalsBy(inPeriod, parentCategoryIDOf)[category])
+
+		usages = append(usages, store.BudgetUsage{Budget: b, Spent: spent})
+	}
+
+	return usages
+}
+
+func categoryIDOf(s store.ExpenseShare) string {
+	return strconv.FormatUint(uint64(s.Expense.Category.ID), 10)
+}
+
+// parentCategoryIDOf is empty for shares of top-level categories.
+func parentCategoryIDOf(s store.ExpenseShare) string {
+	if s.Expense.Category.ParentID == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*s.Expense.Category.ParentID), 10)
+}
+
+// inCategory reports whether c is the category with categoryID or one of its
+// subcategories.
+func inCategory(c store.Category, categoryID uint) bool {
+	return c.ID == categoryID || (c.ParentID != nil && *c.ParentID == categoryID)
+}
+
+func activeBudgets(budgets []store.Budget, now time.Time) []store.Budget {
+	var active []store.Budget
+	for _, b := range budgets {
+		if b.IsActive(now) {
+			active = append(active, b)
+		}
+	}
+	return active
+}
+
+// overBudget keeps the usages that reached the warning threshold.
+func overBudget(usages []store.BudgetUsage) []store.BudgetUsage {
+	var over []store.BudgetUsage
+	for _, u := range usages {
+		if u.IsWarning() || u.IsExceeded() {
+			over = append(over, u)
+		}
+	}
+	return over
+}
+
+type PostBudgetHandler struct {
+	budgetStore    store.BudgetStore
+	householdStore store.HouseholdStore
+	categoryStore  store.CategoryStore
+}
+
+type PostBudgetHandlerParams struct {
+	BudgetStore    store.BudgetStore
+	HouseholdStore store.HouseholdStore
+	CategoryStore  store.CategoryStore
+}
+
+func NewPostBudgetHandler(params PostBudgetHandlerParams) *PostBudgetHandler {
+	return &PostBudgetHandler{
+		budgetStore:    params.BudgetStore,
+		householdStore: params.HouseholdStore,
+		categoryStore:  params.CategoryStore,
+	}
+}
+
+func (h *PostBudgetHandler) PostBudget(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermManageBudgets) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.householdStore.GetHouseholdByID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "invalid household", http.StatusBadRequest)
+		return
+	}
+
+	category, err := parseCategory(h.categoryStore, household.ID, r.FormValue("category"), 0)
+	if err != nil {
+		http.Error(w, "invalid category", http.StatusBadRequest)
+		return
+	}
+
+	period := store.BudgetPeriod(r.FormValue("period"))
+	if !period.IsValid() {
+		http.Error(w, "invalid period", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := money.Parse(r.FormValue("limit"), household.BaseCurrency)
+	if err != nil || limit.Minor <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", "Limit must be a positive amount in "+household.BaseCurrency)
+		c.Render(r.Context(), w)
+		return
+	}
+
+	budget := store.Budget{
+		HouseholdID: household.ID,
+		CategoryID:  category.ID,
+		Limit:       limit,
+		Period:      period,
+		CreatedByID: user.ID,
+	}
+
+	if period == store.BudgetCustom {
+		budget.StartsOn, err = time.Parse(time.DateOnly, r.FormValue("starts_on"))
+		if err == nil {
+			budget.EndsOn, err = time.Parse(time.DateOnly, r.FormValue("ends_on"))
+		}
+
+		if err != nil || budget.EndsOn.Before(budget.StartsOn) {
+			w.WriteHeader(http.StatusBadRequest)
+			c := templAlerts.Error("Create failed", "A custom budget needs a start date and an end date that is not before it")
+			c.Render(r.Context(), w)
+			return
+		}
+	}
+
+	existing, err := h.budgetStore.GetBudgetsByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch budgets", http.StatusInternalServerError)
+		return
+	}
+
+	for _, b := range existing {
+		if b.CategoryID == category.ID && b.Period == store.BudgetMonthly && period == store.BudgetMonthly {
+			w.WriteHeader(http.StatusConflict)
+			c := templAlerts.Error("Create failed", "This category already has a monthly budget")
+			c.Render(r.Context(), w)
+			return
+		}
+	}
+
+	if _, err := h.budgetStore.CreateBudget(budget); err != nil {
+		http.Error(w, "cannot create budget", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *PostBudgetHandler) PostDeleteBudget(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermManageBudgets) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	budgetID, err := strconv.ParseUint(chi.URLParam(r, "budgetID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid budget id", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.budgetStore.GetBudgetByID(uint(budgetID))
+	if err != nil || budget.HouseholdID != membership.HouseholdID {
+		http.Error(w, "Budget not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.budgetStore.DeleteBudget(budget.ID); err != nil {
+		http.Error(w, "cannot delete budget", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
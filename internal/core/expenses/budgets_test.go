@@ -0,0 +1,89 @@
+package expenses
+
+import (
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetUsages(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	food := store.Category{ID: 1, Name: "Food"}
+	groceries := store.Category{ID: 2, Name: "Groceries", ParentID: &food.ID}
+	rent := store.Category{ID: 3, Name: "Rent"}
+
+	share := func(householdID uint, category store.Category, createdOn time.Time, minor int64) store.ExpenseShare {
+		return store.ExpenseShare{
+			Amount: money.New(minor, "PLN"),
+			Expense: store.Expense{
+				HouseholdID: householdID,
+				Category:    category,
+				CreatedOn:   createdOn,
+			},
+		}
+	}
+
+	shares := []store.ExpenseShare{
+		share(1, food, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), 10000),
+		share(1, groceries, time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC), 5000),
+		share(1, food, time.Date(2026, time.September, 30, 23, 0, 0, 0, time.UTC), 7000),
+		share(1, rent, time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC), 200000),
+		share(2, food, time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC), 3000),
+	}
+
+	budgets := []store.Budget{
+		{HouseholdID: 1, CategoryID: food.ID, Period: store.BudgetMonthly, Limit: money.New(20000, "PLN")},
+		{
+			HouseholdID: 1,
+			CategoryID:  food.ID,
+			Period:      store.BudgetCustom,
+			Limit:       money.New(20000, "PLN"),
+			StartsOn:    time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC),
+			EndsOn:      time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{HouseholdID: 2, CategoryID: rent.ID, Period: store.BudgetMonthly, Limit: money.New(100000, "PLN")},
+	}
+
+	usages := budgetUsages(budgets, shares, now)
+
+	require.Len(t, usages, 3)
+	require.Equal(t, money.New(15000, "PLN"), usages[0].Spent)
+	require.Equal(t, int64(75), usages[0].Percent())
+	require.Equal(t, money.New(17000, "PLN"), usages[1].Spent)
+	require.Equal(t, money.New(0, "PLN"), usages[2].Spent)
+}
+
+func TestOverBudget(t *testing.T) {
+	usage := func(spent int64) store.BudgetUsage {
+		return store.BudgetUsage{
+			Budget: store.Budget{Limit: money.New(10000, "PLN")},
+			Spent:  money.New(spent, "PLN"),
+		}
+	}
+
+	usages := []store.BudgetUsage{usage(7999), usage(8000), usage(9999), usage(10000), usage(15000)}
+
+	over := overBudget(usages)
+
+	require.Equal(t, []store.BudgetUsage{usage(8000), usage(9999), usage(10000), usage(15000)}, over)
+	require.True(t, over[0].IsWarning())
+	require.True(t, over[1].IsWarning())
+	require.True(t, over[2].IsExceeded())
+	require.False(t, over[2].IsWarning())
+	require.True(t, over[3].IsExceeded())
+}
+
+func TestActiveBudgets(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	monthly := store.Budget{ID: 1, Period: store.BudgetMonthly}
+	current := store.Budget{ID: 2, Period: store.BudgetCustom, StartsOn: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), EndsOn: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)}
+	ended := store.Budget{ID: 3, Period: store.BudgetCustom, StartsOn: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC), EndsOn: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)}
+	future := store.Budget{ID: 4, Period: store.BudgetCustom, StartsOn: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC), EndsOn: time.Date(2026, time.October, 31, 0, 0, 0, 0, time.UTC)}
+
+	require.Equal(t, []store.Budget{monthly, current}, activeBudgets([]store.Budget{monthly, current, ended, future}, now))
+}
@@ -0,0 +1,314 @@
+package expenses
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+type GetEditExpenseHandler struct {
+	expenseStore      store.ExpenseStore
+	expenseShareStore store.ExpenseShareStore
+	categoryStore     store.CategoryStore
+}
+
+type GetEditExpenseHandlerParams struct {
+	ExpenseStore      store.ExpenseStore
+	ExpenseShareStore store.ExpenseShareStore
+	CategoryStore     store.CategoryStore
+}
+
+func NewGetEditExpenseHandler(params GetEditExpenseHandlerParams) *GetEditExpenseHandler {
+	return &GetEditExpenseHandler{
+		expenseStore:      params.ExpenseStore,
+		expenseShareStore: params.ExpenseShareStore,
+		categoryStore:     params.CategoryStore,
+	}
+}
+
+func (h *GetEditExpenseHandler) GetEditExpense(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	expense, ok := editableExpense(w, r, h.expenseStore)
+	if !ok {
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetSharesByExpenseID(expense.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch expense shares", http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := h.categoryStore.GetCategoriesByHouseholdID(expense.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.EditExpense(expense, shares, activeCategories(categories, expense.CategoryID)).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostEditExpenseHandler struct {
+	expenseStore      store.ExpenseStore
+	expenseShareStore store.ExpenseShareStore
+	householdStore    store.HouseholdStore
+	exchangeRateStore store.ExchangeRateStore
+	categoryStore     store.CategoryStore
+}
+
+type PostEditExpenseHandlerParams struct {
+	ExpenseStore      store.ExpenseStore
+	ExpenseShareStore store.ExpenseShareStore
+	HouseholdStore    store.HouseholdStore
+	ExchangeRateStore store.ExchangeRateStore
+	CategoryStore     store.CategoryStore
+}
+
+func NewPostEditExpenseHandler(params PostEditExpenseHandlerParams) *PostEditExpenseHandler {
+	return &PostEditExpenseHandler{
+		expenseStore:      params.ExpenseStore,
+		expenseShareStore: params.ExpenseShareStore,
+		householdStore:    params.HouseholdStore,
+		exchangeRateStore: params.ExchangeRateStore,
+		categoryStore:     params.CategoryStore,
+	}
+}
+
+// PostEditExpense changes the name, amount, category and split of an expense.
+// The amount is converted at the rate of the day the expense was created and
+// shares are recomputed with resplitExpense, so shares with recorded payments
+// keep their amounts. The payers keep the proportions in which they paid.
+func (h *PostEditExpenseHandler) PostEditExpense(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	expense, ok := editableExpense(w, r, h.expenseStore)
+	if !ok {
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+
+	if len(name) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Edit failed", "Expense name is required")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if len(name) > maxExpenseNameLength {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(
+			"Edit failed",
+			fmt.Sprintf("Expense name cannot be longer than %d characters", maxExpenseNameLength),
+		)
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if name != expense.Name {
+		nameBusy, err := h.expenseStore.NameExists(name)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if nameBusy {
+			w.WriteHeader(http.StatusConflict)
+			c := templAlerts.Error("Edit failed", "Expense with this name already exists")
+			c.Render(r.Context(), w)
+			return
+		}
+	}
+
+	household, err := h.householdStore.GetHouseholdByID(expense.HouseholdID)
+	if err != nil {
+		http.Error(w, "invalid household", http.StatusBadRequest)
+		return
+	}
+
+	amount, originalAmount, ok := parseExpenseAmount(w, r, h.exchangeRateStore, household, expense.CreatedOn, "Edit failed")
+	if !ok {
+		return
+	}
+
+	category, err := parseCategory(h.categoryStore, household.ID, r.FormValue("category"), expense.CategoryID)
+	if err != nil {
+		http.Error(w, "invalid category", http.StatusBadRequest)
+		return
+	}
+
+	splitMode := store.SplitMode(r.FormValue("split_mode"))
+	if splitMode == "" {
+		splitMode = store.SplitEqual
+	}
+
+	if !splitMode.IsValid() {
+		http.Error(w, "invalid split mode", http.StatusBadRequest)
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetSharesByExpenseID(expense.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch expense shares", http.StatusInternalServerError)
+		return
+	}
+
+	splitValues := make(map[uint]string, len(shares))
+	for _, s := range shares {
+		splitValues[s.UserID] = r.FormValue(fmt.Sprintf("split_%d", s.UserID))
+	}
+
+	payers := rescalePayers(expense.Payers, amount)
+
+	newShares, err := resplitExpense(splitMode, amount, shares, splitValues, mainPayer(payers))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Edit failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	amounts := make(map[uint]money.Money, len(shares))
+	for _, s := range shares {
+		amounts[s.UserID] = s.Amount
+		if part, ok := newShares[s.UserID]; ok {
+			amounts[s.UserID] = part
+		}
+	}
+
+	covered := coveredParts(amounts, payers)
+	for i, s := range shares {
+		shares[i].Amount = amounts[s.UserID]
+		shares[i].Covered = covered[s.UserID]
+	}
+
+	edited := expense
+	edited.Name = name
+	edited.Amount = amount
+	edited.OriginalAmount = originalAmount
+	edited.CategoryID = category.ID
+	edited.Category = category
+	edited.SplitMode = splitMode
+	edited.Payers = payers
+
+	if err := h.expenseStore.UpdateExpense(edited, shares, user.ID, describeChanges(expense, edited)); err != nil {
+		http.Error(w, "cannot update expense", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostDeleteExpense soft-deletes an expense together with its shares. An
+// expense that payments were recorded against cannot be deleted.
+func (h *PostEditExpenseHandler) PostDeleteExpense(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	expense, ok := editableExpense(w, r, h.expenseStore)
+	if !ok {
+		return
+	}
+
+	err := h.expenseStore.DeleteExpense(expense.ID, user.ID)
+	if errors.Is(err, store.ErrExpenseHasPayments) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Delete failed", "Payments were already recorded for this expense")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, "cannot delete expense", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// editableExpense loads the expense named by the id route parameter and checks
+// that the acting member may change it. It writes the error response itself
+// and returns false otherwise.
+func editableExpense(w http.ResponseWriter, r *http.Request, expenseStore store.ExpenseStore) (store.Expense, bool) {
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return store.Expense{}, false
+	}
+
+	expenseID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid expense id", http.StatusBadRequest)
+		return store.Expense{}, false
+	}
+
+	expense, err := expenseStore.GetExpenseByID(uint(expenseID))
+	if err != nil {
+		http.Error(w, "Expense not found", http.StatusNotFound)
+		return store.Expense{}, false
+	}
+
+	if !membership.CanEditExpense(expense) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return store.Expense{}, false
+	}
+
+	return expense, true
+}
+
+// describeChanges summarises what an edit changed for the audit trail.
+func describeChanges(before, after store.Expense) string {
+	var changes []string
+
+	if before.Name != after.Name {
+		changes = append(changes, fmt.Sprintf("name %q → %q", before.Name, after.Name))
+	}
+	if before.OriginalAmount != after.OriginalAmount {
+		changes = append(changes, fmt.Sprintf("amount %s → %s", before.OriginalAmount, after.OriginalAmount))
+	}
+	if before.CategoryID != after.CategoryID {
+		changes = append(changes, fmt.Sprintf("category %s → %s", before.Category.FullName(), after.Category.FullName()))
+	}
+	if before.SplitMode != after.SplitMode {
+		changes = append(changes, fmt.Sprintf("split %s → %s", before.SplitMode, after.SplitMode))
+	}
+
+	if len(changes) == 0 {
+		return "shares recomputed"
+	}
+
+	return strings.Join(changes, ", ")
+}
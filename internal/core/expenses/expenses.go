@@ -1,17 +1,19 @@
 package expenses
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"math"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	templBasic "github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
 	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
@@ -78,15 +80,18 @@ func sortShares(shares []store.ExpenseShare) []store.ExpenseShare {
 
 type GetExpensesChartHandler struct {
 	expenseShareStore store.ExpenseShareStore
+	exchangeRateStore store.ExchangeRateStore
 }
 
 type GetExpensesChartHandlerParams struct {
 	ExpenseShareStore store.ExpenseShareStore
+	ExchangeRateStore store.ExchangeRateStore
 }
 
 func NewGetExpensesChartHandler(params GetExpensesChartHandlerParams) *GetExpensesChartHandler {
 	return &GetExpensesChartHandler{
 		expenseShareStore: params.ExpenseShareStore,
+		exchangeRateStore: params.ExchangeRateStore,
 	}
 }
 
@@ -105,8 +110,15 @@ func (h *GetExpensesChartHandler) GetExpensesChart(w http.ResponseWriter, r *htt
 		return
 	}
 
+	shares, err = h.inOneCurrency(shares)
+	if err != nil {
+		http.Error(w, "Failed to convert expenses", 500)
+		return
+	}
+
 	var labels []string
 	var values []float64
+	var colours []string
 
 	var unpaidShares []store.ExpenseShare
 	for _, s := range shares {
@@ -120,6 +132,7 @@ func (h *GetExpensesChartHandler) GetExpensesChart(w http.ResponseWriter, r *htt
 		labels, values = sumByHousehold(unpaidShares)
 	case "category":
 		labels, values = sumByCategory(unpaidShares)
+		colours = categoryColours(unpaidShares, labels)
 	case "status":
 		labels, values = sumByStatus(shares)
 	default:
@@ -127,77 +140,135 @@ func (h *GetExpensesChartHandler) GetExpensesChart(w http.ResponseWriter, r *htt
 		return
 	}
 
-	templ.ExpensesChart(labels, values).Render(r.Context(), w)
+	templ.ExpensesChart(labels, values, colours).Render(r.Context(), w)
+}
+
+// inOneCurrency makes shares summable. Shares from households with different
+// base currencies are converted to money.DefaultCurrency at the rate valid on
+// the day of the expense.
+func (h *GetExpensesChartHandler) inOneCurrency(shares []store.ExpenseShare) ([]store.ExpenseShare, error) {
+	mixed := false
+	for _, s := range shares {
+		if s.Amount.Currency != shares[0].Amount.Currency {
+			mixed = true
+			break
+		}
+	}
+
+	if !mixed {
+		return shares, nil
+	}
+
+	for i, s := range shares {
+		amount, err := h.exchangeRateStore.Convert(s.Amount, money.DefaultCurrency, s.Expense.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		shares[i].Amount = amount
+	}
+
+	return shares, nil
 }
 
 func sumByCategory(shares []store.ExpenseShare) ([]string, []float64) {
-	m := map[string]float64{}
+	return sumBy(shares, categoryOf)
+}
 
+func categoryOf(s store.ExpenseShare) string {
+	return s.Expense.Category.FullName()
+}
+
+// categoryColours returns the colour of the category of each label, in the
+// order of labels.
+func categoryColours(shares []store.ExpenseShare, labels []string) []string {
+	colours := map[string]string{}
 	for _, s := range shares {
-		m[string(s.Expense.Category)] += s.Amount
+		colours[categoryOf(s)] = s.Expense.Category.Colour
 	}
 
-	var labels []string
-	var values []float64
-	for k, v := range m {
-		labels = append(labels, k)
-		values = append(values, v)
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		result[i] = colours[label]
 	}
-
-	return labels, values
+	return result
 }
 
 func sumByHousehold(shares []store.ExpenseShare) ([]string, []float64) {
-	m := map[string]float64{}
+	return sumBy(shares, func(s store.ExpenseShare) string {
+		return s.Expense.Household.Name
+	})
+}
+
+// totalsBy totals share amounts in minor units per label.
+func totalsBy(shares []store.ExpenseShare, label func(store.ExpenseShare) string) map[string]money.Money {
+	m := map[string]money.Money{}
 
 	for _, s := range shares {
-		m[s.Expense.Household.Name] += s.Amount
+		key := label(s)
+		m[key] = m[key].Add(s.Amount)
 	}
 
+	return m
+}
+
+// sumBy totals share amounts per label and only converts the totals to floats
+// for the chart.
+func sumBy(shares []store.ExpenseShare, label func(store.ExpenseShare) string) ([]string, []float64) {
+	m := totalsBy(shares, label)
+
 	var labels []string
 	var values []float64
 	for k, v := range m {
 		labels = append(labels, k)
-		values = append(values, v)
+		values = append(values, v.Float())
 	}
 
 	return labels, values
 }
 
 func sumByStatus(shares []store.ExpenseShare) ([]string, []float64) {
-	var paid, unpaid float64
+	var paid, unpaid money.Money
 	for _, s := range shares {
 		if s.Paid {
-			paid += s.Amount
+			paid = paid.Add(s.Amount)
 		} else {
-			unpaid += s.Amount
+			unpaid = unpaid.Add(s.Amount)
 		}
 	}
 	labels := []string{"Unpaid", "Paid"}
-	values := []float64{unpaid, paid}
+	values := []float64{unpaid.Float(), paid.Float()}
 	return labels, values
 }
 
 type PostExpenseHandler struct {
 	expenseStore      store.ExpenseStore
 	expenseShareStore store.ExpenseShareStore
+	householdStore    store.HouseholdStore
 	membershipStore   store.MembershipStore
+	exchangeRateStore store.ExchangeRateStore
 	userStore         store.UserStore
+	categoryStore     store.CategoryStore
 }
 
 type PostExpenseHandlerParams struct {
 	ExpenseStore      store.ExpenseStore
 	ExpenseShareStore store.ExpenseShareStore
+	HouseholdStore    store.HouseholdStore
 	MembershipStore   store.MembershipStore
+	ExchangeRateStore store.ExchangeRateStore
 	UserStore         store.UserStore
+	CategoryStore     store.CategoryStore
 }
 
 func NewPostExpenseHandler(params PostExpenseHandlerParams) *PostExpenseHandler {
 	return &PostExpenseHandler{
 		expenseStore:      params.ExpenseStore,
 		expenseShareStore: params.ExpenseShareStore,
+		householdStore:    params.HouseholdStore,
 		membershipStore:   params.MembershipStore,
+		exchangeRateStore: params.ExchangeRateStore,
 		userStore:         params.UserStore,
+		categoryStore:     params.CategoryStore,
 	}
 }
 
@@ -208,19 +279,18 @@ func (h *PostExpenseHandler) PostExpense(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermAddExpense) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "invalid form", http.StatusBadRequest)
 		return
 	}
 
-	const (
-		maxExpenseNameLength = 40
-		minExpenseAmount     = 10.0
-	)
-
 	name := r.FormValue("name")
-	amountStr := r.FormValue("amount")
-	categoryStr := r.FormValue("category")
 	householdIDStr := r.FormValue("household_id")
 
 	if len(name) > maxExpenseNameLength {
@@ -246,70 +316,96 @@ func (h *PostExpenseHandler) PostExpense(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	householdID64, err := strconv.ParseUint(householdIDStr, 10, 64)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		c := templAlerts.Error("Create failed", "Invalid amount format")
-		c.Render(r.Context(), w)
+		http.Error(w, "invalid household", http.StatusBadRequest)
 		return
 	}
+	householdID := uint(householdID64)
 
-	if amount < minExpenseAmount {
-		w.WriteHeader(http.StatusBadRequest)
-		c := templAlerts.Error(
-			"Create failed",
-			fmt.Sprintf("Amount must be at least %.2f", minExpenseAmount),
-		)
-		c.Render(r.Context(), w)
+	household, err := h.householdStore.GetHouseholdByID(householdID)
+	if err != nil {
+		http.Error(w, "invalid household", http.StatusBadRequest)
 		return
 	}
 
-	if math.Round(amount*100)/100 != amount {
-		w.WriteHeader(http.StatusBadRequest)
-		c := templAlerts.Error(
-			"Create failed",
-			"Amount can have at most 2 decimal places",
-		)
-		c.Render(r.Context(), w)
+	createdOn := time.Now()
+
+	amount, originalAmount, ok := parseExpenseAmount(w, r, h.exchangeRateStore, household, createdOn, "Create failed")
+	if !ok {
 		return
 	}
 
-	householdID64, err := strconv.ParseUint(householdIDStr, 10, 64)
+	category, err := parseCategory(h.categoryStore, householdID, r.FormValue("category"), 0)
 	if err != nil {
-		http.Error(w, "invalid household", http.StatusBadRequest)
+		http.Error(w, "invalid category", http.StatusBadRequest)
 		return
 	}
-	householdID := uint(householdID64)
 
-	category := store.ExpenseCategory(categoryStr)
-	if !category.IsValid() {
-		http.Error(w, "invalid category", http.StatusBadRequest)
+	splitMode := store.SplitMode(r.FormValue("split_mode"))
+	if splitMode == "" {
+		splitMode = store.SplitEqual
+	}
+
+	if !splitMode.IsValid() {
+		http.Error(w, "invalid split mode", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.membershipStore.GetMembersByHouseholdID(householdID)
+	if err != nil || len(members) == 0 {
+		http.Error(w, "cannot fetch household members", http.StatusInternalServerError)
 		return
 	}
 
+	splitValues := make(map[uint]string, len(members))
+	paidValues := make(map[uint]string, len(members))
+	for _, member := range members {
+		splitValues[member.UserID] = r.FormValue(fmt.Sprintf("split_%d", member.UserID))
+		paidValues[member.UserID] = r.FormValue(fmt.Sprintf("paid_%d", member.UserID))
+	}
+
+	payers, err := parsePayers(r.FormValue("paid_by"), paidValues, members, amount, originalAmount, user.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	shares, err := splitExpense(splitMode, amount, members, splitValues, mainPayer(payers))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	covered := coveredParts(shares, payers)
+
 	expenseID, err := h.expenseStore.CreateExpense(
 		name,
 		amount,
-		category,
-		time.Now(),
+		originalAmount,
+		category.ID,
+		splitMode,
+		createdOn,
 		householdID,
 		user.ID,
+		payers,
 	)
 	if err != nil {
 		http.Error(w, "cannot create expense", http.StatusInternalServerError)
 		return
 	}
 
-	members, err := h.membershipStore.GetMembersByHouseholdID(householdID)
-	if err != nil || len(members) == 0 {
-		http.Error(w, "cannot fetch household members", http.StatusInternalServerError)
-		return
-	}
-
-	shares := splitAmount(amount, len(members))
+	for _, member := range members {
+		share, ok := shares[member.UserID]
+		if !ok {
+			continue
+		}
 
-	for i, member := range members {
-		if err := h.expenseShareStore.CreateExpenseShare(expenseID, member.UserID, shares[i]); err != nil {
+		if err := h.expenseShareStore.CreateExpenseShare(expenseID, member.UserID, share, covered[member.UserID]); err != nil {
 			log.Printf("cannot create expense share for user %d: %v", member.UserID, err)
 		}
 	}
@@ -318,40 +414,166 @@ func (h *PostExpenseHandler) PostExpense(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
-func splitAmount(amount float64, membersCount int) []float64 {
-	if membersCount == 0 {
-		return nil
+const (
+	maxExpenseNameLength = 40
+	minExpenseMajorUnits = 10
+)
+
+// parseExpenseAmount reads the amount and currency fields and converts the
+// amount into the household currency at the rate in effect on the given day.
+// It writes the error response itself, under title, and returns false when
+// the fields are invalid.
+func parseExpenseAmount(w http.ResponseWriter, r *http.Request, exchangeRateStore store.ExchangeRateStore, household store.Household, on time.Time, title string) (money.Money, money.Money, bool) {
+	currency := r.FormValue("currency")
+	if currency == "" {
+		currency = household.BaseCurrency
 	}
 
-	perPerson := math.Ceil(amount/float64(membersCount)*100) / 100
-	shares := make([]float64, membersCount)
-	for i := range shares {
-		shares[i] = perPerson
+	if !money.IsSupported(currency) {
+		http.Error(w, "invalid currency", http.StatusBadRequest)
+		return money.Money{}, money.Money{}, false
 	}
 
-	return shares
+	originalAmount, err := parseAmount(r.FormValue("amount"), currency)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(title, err.Error())
+		c.Render(r.Context(), w)
+		return money.Money{}, money.Money{}, false
+	}
+
+	amount, err := exchangeRateStore.Convert(originalAmount, household.BaseCurrency, on)
+	if errors.Is(err, store.ErrNoExchangeRate) {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(
+			title,
+			fmt.Sprintf("No exchange rate from %s to %s, add one under Exchange rates", currency, household.BaseCurrency),
+		)
+		c.Render(r.Context(), w)
+		return money.Money{}, money.Money{}, false
+	}
+
+	if err != nil {
+		http.Error(w, "cannot convert amount", http.StatusInternalServerError)
+		return money.Money{}, money.Money{}, false
+	}
+
+	if err := checkMinimumAmount(amount); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(title, err.Error())
+		c.Render(r.Context(), w)
+		return money.Money{}, money.Money{}, false
+	}
+
+	return amount, originalAmount, true
+}
+
+// parseAmount reads an amount entered in currency. Its errors are shown to the
+// user as they are.
+func parseAmount(value string, currency string) (money.Money, error) {
+	amount, err := money.Parse(value, currency)
+	if errors.Is(err, money.ErrPrecision) {
+		return money.Money{}, fmt.Errorf("Amount in %s can have at most %d decimal places", currency, money.Exponent(currency))
+	}
+
+	if err != nil {
+		return money.Money{}, errors.New("Invalid amount format")
+	}
+
+	return amount, nil
+}
+
+// checkMinimumAmount refuses expenses below minExpenseMajorUnits of the
+// household currency amount is in.
+func checkMinimumAmount(amount money.Money) error {
+	minExpenseAmount := money.FromFloat(minExpenseMajorUnits, amount.Currency)
+	if amount.Minor < minExpenseAmount.Minor {
+		return fmt.Errorf("Amount must be at least %s", minExpenseAmount)
+	}
+	return nil
+}
+
+// parseCategory looks up the category with the ID in value, which must belong
+// to the household. Archived categories are refused unless the expense is
+// already in it, as with current.
+func parseCategory(categoryStore store.CategoryStore, householdID uint, value string, current uint) (store.Category, error) {
+	categoryID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return store.Category{}, err
+	}
+
+	category, err := categoryStore.GetCategoryByID(uint(categoryID))
+	if err != nil {
+		return store.Category{}, err
+	}
+
+	if category.HouseholdID != householdID || (category.Archived && category.ID != current) {
+		return store.Category{}, errInvalidCategory
+	}
+
+	return category, nil
+}
+
+var errInvalidCategory = errors.New("category is not available in the household")
+
+// activeCategories leaves out archived categories, except current so that an
+// expense already in an archived category keeps it when edited.
+func activeCategories(categories []store.Category, current uint) []store.Category {
+	var active []store.Category
+	for _, c := range categories {
+		if !c.Archived || c.ID == current {
+			active = append(active, c)
+		}
+	}
+	return active
 }
 
 type PostExpenseShareHandler struct {
 	expenseShareStore store.ExpenseShareStore
+	paymentStore      store.PaymentStore
 }
 
 type PostExpenseShareHandlerParams struct {
 	ExpenseShareStore store.ExpenseShareStore
+	PaymentStore      store.PaymentStore
 }
 
 func NewPostExpenseShareHandler(params PostExpenseShareHandlerParams) *PostExpenseShareHandler {
 	return &PostExpenseShareHandler{
 		expenseShareStore: params.ExpenseShareStore,
+		paymentStore:      params.PaymentStore,
 	}
 }
 
+// PostPayExpenseShare records a payment towards a share. The amount defaults to
+// whatever is still outstanding, so paying without entering one settles the
+// share in full. When several members paid for the expense, the payment is
+// split between them as store.Expense.OwedTo describes. Who may record it is
+// decided by canRecordPayment.
 func (h *PostExpenseShareHandler) PostPayExpenseShare(w http.ResponseWriter, r *http.Request) {
-	expenseIDStr := chi.URLParam(r, "id")
-	userIDStr := r.FormValue("user_id")
+	user := middleware.GetUser(r.Context())
 
-	expenseID, _ := strconv.Atoi(expenseIDStr)
-	userID, _ := strconv.Atoi(userIDStr)
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	expenseID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid expense id", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := strconv.ParseUint(r.FormValue("user_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
 
 	share, err := h.expenseShareStore.GetExpenseShare(uint(expenseID), uint(userID))
 	if err != nil {
@@ -359,9 +581,109 @@ func (h *PostExpenseShareHandler) PostPayExpenseShare(w http.ResponseWriter, r *
 		return
 	}
 
-	share.Paid = true
-	_ = h.expenseShareStore.UpdateExpenseShare(share)
+	if !canRecordPayment(middleware.GetMembership(r.Context()), share) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	outstanding := share.Outstanding()
+	if outstanding.IsZero() {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Payment failed", "This share is already paid")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	amount := outstanding
+	if amountStr := strings.TrimSpace(r.FormValue("amount")); amountStr != "" {
+		amount, err = money.Parse(amountStr, outstanding.Currency)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			c := templAlerts.Error("Payment failed", "Invalid amount")
+			c.Render(r.Context(), w)
+			return
+		}
+	}
+
+	if amount.IsZero() || amount.Minor > outstanding.Minor {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Payment failed", fmt.Sprintf("Amount must be between 0 and %s", outstanding))
+		c.Render(r.Context(), w)
+		return
+	}
+
+	method := store.PaymentMethod(r.FormValue("method"))
+	if method == "" {
+		method = store.PaymentTransfer
+	}
+
+	if !method.IsValid() {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Payment failed", "Invalid payment method")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	note := strings.TrimSpace(r.FormValue("note"))
+	if len(note) > 200 {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Payment failed", "Note can have at most 200 characters")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetSharesByExpenseID(share.ExpenseID)
+	if err != nil {
+		http.Error(w, "cannot fetch expense shares", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+
+	var payments []store.Payment
+	for i, part := range share.Expense.OwedTo(amount, shares) {
+		if part.IsZero() {
+			continue
+		}
+
+		payments = append(payments, store.Payment{
+			ExpenseShareID: share.ID,
+			PayerID:        share.UserID,
+			PayeeID:        share.Expense.Payers[i].UserID,
+			Amount:         part,
+			PaidOn:         now,
+			Method:         method,
+			Note:           note,
+		})
+	}
+
+	err = h.paymentStore.CreatePayments(payments)
+	if errors.Is(err, store.ErrOverpayment) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Payment failed", "This share was paid in the meantime")
+		c.Render(r.Context(), w)
+		return
+	}
+	if err != nil {
+		http.Error(w, "cannot record payment", http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("HX-Redirect", "/expenses")
 	w.WriteHeader(http.StatusOK)
 }
+
+// canRecordPayment reports whether the acting member may record a payment
+// towards share. Members may always pay their own shares and confirm payments
+// for expenses they paid for; anyone else needs PermMarkOthersPaid.
+func canRecordPayment(membership *store.Membership, share store.ExpenseShare) bool {
+	if membership == nil {
+		return false
+	}
+
+	if membership.UserID == share.UserID || share.Expense.IsPayer(membership.UserID) {
+		return true
+	}
+
+	return membership.Can(store.PermMarkOthersPaid)
+}
@@ -0,0 +1,40 @@
+package expenses
+
+import (
+	"testing"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanRecordPayment(t *testing.T) {
+	// User 2 owes the share of an expense entered by user 5 and paid by users 1
+	// and 4.
+	share := store.ExpenseShare{UserID: 2, Expense: store.Expense{
+		CreatedByID: 5,
+		Payers:      []store.ExpensePayer{{UserID: 1}, {UserID: 4}},
+	}}
+
+	testCases := []struct {
+		name       string
+		membership *store.Membership
+		want       bool
+	}{
+		{name: "no membership", membership: nil, want: false},
+		{name: "debtor", membership: &store.Membership{UserID: 2, Role: store.RoleMember}, want: true},
+		{name: "debtor as viewer", membership: &store.Membership{UserID: 2, Role: store.RoleViewer}, want: true},
+		{name: "payer", membership: &store.Membership{UserID: 1, Role: store.RoleMember}, want: true},
+		{name: "second payer", membership: &store.Membership{UserID: 4, Role: store.RoleViewer}, want: true},
+		{name: "entered but did not pay", membership: &store.Membership{UserID: 5, Role: store.RoleMember}, want: false},
+		{name: "other member", membership: &store.Membership{UserID: 3, Role: store.RoleMember}, want: false},
+		{name: "other viewer", membership: &store.Membership{UserID: 3, Role: store.RoleViewer}, want: false},
+		{name: "admin", membership: &store.Membership{UserID: 3, Role: store.RoleAdmin}, want: true},
+		{name: "owner", membership: &store.Membership{UserID: 3, Role: store.RoleOwner}, want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, canRecordPayment(tc.membership, share))
+		})
+	}
+}
@@ -0,0 +1,458 @@
+package expenses
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+// importDateLayouts are the date formats accepted in import files.
+var importDateLayouts = []string{time.DateOnly, "02.01.2006", "02/01/2006"}
+
+// defaultImportCategory is the category preselected for rows without a
+// category, while the household has an active category of that name.
+const defaultImportCategory = "Other"
+
+// notImported is the column_<field> value of a field that is not read from
+// the file.
+const notImported = "-"
+
+type GetImportExpensesHandler struct{}
+
+func NewGetImportExpensesHandler() *GetImportExpensesHandler {
+	return &GetImportExpensesHandler{}
+}
+
+func (h *GetImportExpensesHandler) GetImportExpenses(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermAddExpense) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	err := templ.ImportExpenses(membership.HouseholdID).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostImportExpensesHandler struct {
+	expenseStore    store.ExpenseStore
+	householdStore  store.HouseholdStore
+	membershipStore store.MembershipStore
+	categoryStore   store.CategoryStore
+}
+
+type PostImportExpensesHandlerParams struct {
+	ExpenseStore    store.ExpenseStore
+	HouseholdStore  store.HouseholdStore
+	MembershipStore store.MembershipStore
+	CategoryStore   store.CategoryStore
+}
+
+func NewPostImportExpensesHandler(params PostImportExpensesHandlerParams) *PostImportExpensesHandler {
+	return &PostImportExpensesHandler{
+		expenseStore:    params.ExpenseStore,
+		householdStore:  params.HouseholdStore,
+		membershipStore: params.MembershipStore,
+		categoryStore:   params.CategoryStore,
+	}
+}
+
+// PostImportExpenses reads expenses from an uploaded CSV file whose columns
+// are mapped to expense fields by the column_<field> form values. With
+// dry_run set every row is validated and previewed; otherwise all rows are
+// saved in one transaction, and only if none of them has an error. Rows
+// without a category go into the active category chosen as default_category,
+// or defaultImportCategory until one is chosen. Amounts are in the household
+// currency and every expense is split equally between the current members.
+func (h *PostImportExpensesHandler) PostImportExpenses(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermAddExpense) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	const maxUploadSize = 1 << 20
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Import failed", "Please choose a CSV file")
+		c.Render(r.Context(), w)
+		return
+	}
+	defer file.Close()
+
+	header, records, err := readImportFile(file)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Import failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	mapping := importMapping(header, func(field string) string {
+		return r.FormValue("column_" + field)
+	})
+
+	household, err := h.householdStore.GetHouseholdByID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "invalid household", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.membershipStore.GetMembersByHouseholdID(household.ID)
+	if err != nil || len(members) == 0 {
+		http.Error(w, "cannot fetch household members", http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := h.categoryStore.GetCategoriesByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	active := activeCategories(categories, 0)
+
+	importer := expenseImporter{
+		household:  household,
+		members:    members,
+		categories: active,
+		fallback:   fallbackCategory(active, r.FormValue("default_category")),
+		userID:     user.ID,
+		now:        time.Now(),
+		nameExists: h.expenseStore.NameExists,
+	}
+
+	rows, expenses, err := importer.rows(records, mapping)
+	if err != nil {
+		http.Error(w, "cannot check expense names", http.StatusInternalServerError)
+		return
+	}
+
+	invalid := invalidRows(rows)
+
+	if r.FormValue("dry_run") == "true" {
+		err = templ.ImportPreview(header, mapping, rows, invalid, active, importer.fallback).Render(r.Context(), w)
+		if err != nil {
+			http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if invalid > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Import failed", fmt.Sprintf("%d of %d rows have errors, check the preview", invalid, len(rows)))
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if err := h.expenseStore.ImportExpenses(expenses); err != nil {
+		http.Error(w, "cannot import expenses", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// readImportFile reads the header and records of a CSV file. Commas and
+// semicolons are both accepted as separators and rows may be shorter than
+// the header.
+func readImportFile(r io.Reader) ([]string, [][]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text := strings.TrimPrefix(string(content), "\ufeff")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, nil, errors.New("file needs a header row and at least one expense")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		header[i] = strings.TrimSpace(h)
+	}
+
+	return header, records[1:], nil
+}
+
+// fallbackCategory finds the category with the ID selected among categories,
+// or while none is selected the one named defaultImportCategory. It returns
+// nil when there is no such category.
+func fallbackCategory(categories []store.Category, selected string) *store.Category {
+	for i, c := range categories {
+		match := strconv.FormatUint(uint64(c.ID), 10) == selected
+		if selected == "" {
+			match = c.ParentID == nil && strings.EqualFold(c.Name, defaultImportCategory)
+		}
+
+		if match {
+			return &categories[i]
+		}
+	}
+	return nil
+}
+
+// importMapping finds the column of every import field: the header named by
+// selected, or else a header named like the field. Fields selected as
+// notImported and fields without a column are left out.
+func importMapping(header []string, selected func(field string) string) map[string]int {
+	mapping := map[string]int{}
+
+	for _, field := range store.ImportFields {
+		name := selected(field)
+		if name == notImported {
+			continue
+		}
+		if name == "" {
+			name = field
+		}
+
+		for i, h := range header {
+			if strings.EqualFold(h, name) {
+				mapping[field] = i
+				break
+			}
+		}
+	}
+
+	return mapping
+}
+
+// expenseImporter validates imported rows with the rules of PostExpense and
+// turns them into expenses of household.
+type expenseImporter struct {
+	household  store.Household
+	members    []store.Membership
+	categories []store.Category
+	fallback   *store.Category
+	userID     uint
+	now        time.Time
+	nameExists func(name string) (bool, error)
+}
+
+// rows validates every record. The expenses are only complete when no row
+// has errors.
+func (i expenseImporter) rows(records [][]string, mapping map[string]int) ([]store.ImportRow, []store.ImportedExpense, error) {
+	rows := make([]store.ImportRow, 0, len(records))
+	expenses := make([]store.ImportedExpense, 0, len(records))
+	names := map[string]bool{}
+
+	for n, record := range records {
+		row := store.ImportRow{Line: n + 2, Values: map[string]string{}}
+		for field, column := range mapping {
+			if column < len(record) {
+				row.Values[field] = strings.TrimSpace(record[column])
+			}
+		}
+
+		expense, errs, err := i.expense(row.Values, mapping, names)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		row.Errors = errs
+		rows = append(rows, row)
+		if len(errs) == 0 {
+			expenses = append(expenses, expense)
+		}
+	}
+
+	return rows, expenses, nil
+}
+
+// expense builds the expense of a row and lists what is wrong with it. names
+// holds the names of the rows before it, which must be unique as well.
+func (i expenseImporter) expense(values map[string]string, mapping map[string]int, names map[string]bool) (store.ImportedExpense, []string, error) {
+	var errs []string
+
+	if _, ok := mapping["name"]; !ok {
+		errs = append(errs, "Map a column to the name")
+	}
+	if _, ok := mapping["amount"]; !ok {
+		errs = append(errs, "Map a column to the amount")
+	}
+
+	name := values["name"]
+	switch {
+	case name == "":
+		errs = append(errs, "Expense name is required")
+	case len(name) > maxExpenseNameLength:
+		errs = append(errs, fmt.Sprintf("Expense name cannot be longer than %d characters", maxExpenseNameLength))
+	case names[name]:
+		errs = append(errs, "Expense name appears more than once in the file")
+	default:
+		names[name] = true
+
+		busy, err := i.nameExists(name)
+		if err != nil {
+			return store.ImportedExpense{}, nil, err
+		}
+		if busy {
+			errs = append(errs, "Expense with this name already exists")
+		}
+	}
+
+	amount, err := parseAmount(values["amount"], i.household.BaseCurrency)
+	if err == nil {
+		err = checkMinimumAmount(amount)
+	}
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	createdOn := i.now
+	if value := values["date"]; value != "" {
+		createdOn, err = parseImportDate(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Date %q is not in the format YYYY-MM-DD or DD.MM.YYYY", value))
+		}
+	}
+
+	category, err := i.category(values["category"])
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	payerID, err := i.payer(values["payer"])
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return store.ImportedExpense{}, errs, nil
+	}
+
+	payers := []store.ExpensePayer{{UserID: payerID, Amount: amount}}
+
+	split, err := splitExpense(store.SplitEqual, amount, i.members, nil, payerID)
+	if err != nil {
+		return store.ImportedExpense{}, []string{err.Error()}, nil
+	}
+
+	covered := coveredParts(split, payers)
+
+	var shares []store.ExpenseShare
+	for _, member := range i.members {
+		shares = append(shares, store.ExpenseShare{UserID: member.UserID, Amount: split[member.UserID], Covered: covered[member.UserID]})
+	}
+
+	expense := store.Expense{
+		Name:           name,
+		Amount:         amount,
+		OriginalAmount: amount,
+		CategoryID:     category.ID,
+		SplitMode:      store.SplitEqual,
+		CreatedOn:      createdOn,
+		CreatedByID:    i.userID,
+		HouseholdID:    i.household.ID,
+		Payers:         payers,
+	}
+
+	return store.ImportedExpense{Expense: expense, Shares: shares}, nil, nil
+}
+
+// category finds an active category by its name, or its full name for
+// subcategories. Rows without a category go into the fallback category.
+func (i expenseImporter) category(value string) (store.Category, error) {
+	if value == "" {
+		if i.fallback == nil {
+			return store.Category{}, errors.New("Choose a category for rows without one")
+		}
+		return *i.fallback, nil
+	}
+
+	for _, c := range i.categories {
+		if strings.EqualFold(c.FullName(), value) || (c.ParentID == nil && strings.EqualFold(c.Name, value)) {
+			return c, nil
+		}
+	}
+
+	return store.Category{}, fmt.Errorf("Category %q does not exist in the household", value)
+}
+
+// payer finds a member by username. Rows without a payer were paid by the
+// member importing them.
+func (i expenseImporter) payer(value string) (uint, error) {
+	if value == "" {
+		return i.userID, nil
+	}
+
+	for _, m := range i.members {
+		if strings.EqualFold(m.User.Username, value) {
+			return m.UserID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Payer %q is not a member of the household", value)
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range importDateLayouts {
+		var date time.Time
+		date, err = time.Parse(layout, value)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func invalidRows(rows []store.ImportRow) int {
+	var invalid int
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			invalid++
+		}
+	}
+	return invalid
+}
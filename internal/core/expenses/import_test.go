@@ -0,0 +1,122 @@
+package expenses
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadImportFile(t *testing.T) {
+	header, records, err := readImportFile(strings.NewReader("\ufeffWhat;Kwota;Date\nBread;12,50;2025-01-31\nMilk;15\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"What", "Kwota", "Date"}, header)
+	require.Equal(t, [][]string{{"Bread", "12,50", "2025-01-31"}, {"Milk", "15"}}, records)
+
+	_, _, err = readImportFile(strings.NewReader("name,amount\n"))
+	require.Error(t, err)
+}
+
+func TestImportMapping(t *testing.T) {
+	header := []string{"What", "Amount", "Date", "Paid by"}
+	selected := map[string]string{"name": "What", "payer": "Paid by", "date": notImported}
+
+	mapping := importMapping(header, func(field string) string {
+		return selected[field]
+	})
+
+	require.Equal(t, map[string]int{"name": 0, "amount": 1, "payer": 3}, mapping)
+}
+
+func TestExpenseImporterRows(t *testing.T) {
+	food := store.Category{ID: 1, HouseholdID: 3, Name: "Food"}
+	groceries := store.Category{ID: 2, HouseholdID: 3, Name: "Groceries", ParentID: &food.ID, Parent: &food}
+	other := store.Category{ID: 3, HouseholdID: 3, Name: "Other"}
+
+	importer := expenseImporter{
+		household: store.Household{ID: 3, BaseCurrency: "PLN"},
+		members: []store.Membership{
+			{UserID: 1, User: store.User{ID: 1, Username: "anna"}},
+			{UserID: 2, User: store.User{ID: 2, Username: "piotr"}},
+		},
+		categories: []store.Category{food, groceries, other},
+		fallback:   &other,
+		userID:     1,
+		now:        time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC),
+		nameExists: func(name string) (bool, error) {
+			return name == "Rent", nil
+		},
+	}
+
+	mapping := map[string]int{"name": 0, "amount": 1, "date": 2, "category": 3, "payer": 4}
+	records := [][]string{
+		{"Bread", "25.01", "31.01.2025", "food / groceries", "Piotr"},
+		{"Cinema", "40", "", "", ""},
+		{"Rent", "9.99", "2025-13-01", "Holidays", "ewa"},
+		{"Cinema", "12.345"},
+		{strings.Repeat("x", maxExpenseNameLength+1), "abc"},
+	}
+
+	rows, expenses, err := importer.rows(records, mapping)
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+
+	require.Empty(t, rows[0].Errors)
+	require.Empty(t, rows[1].Errors)
+	require.Equal(t, []string{
+		"Expense with this name already exists",
+		"Amount must be at least 10.00 PLN",
+		`Date "2025-13-01" is not in the format YYYY-MM-DD or DD.MM.YYYY`,
+		`Category "Holidays" does not exist in the household`,
+		`Payer "ewa" is not a member of the household`,
+	}, rows[2].Errors)
+	require.Equal(t, []string{
+		"Expense name appears more than once in the file",
+		"Amount in PLN can have at most 2 decimal places",
+	}, rows[3].Errors)
+	require.Equal(t, []string{
+		"Expense name cannot be longer than 40 characters",
+		"Invalid amount format",
+	}, rows[4].Errors)
+	require.Equal(t, 2, rows[0].Line)
+
+	require.Len(t, expenses, 2)
+
+	bread := expenses[0]
+	require.Equal(t, "Bread", bread.Expense.Name)
+	require.Equal(t, money.New(2501, "PLN"), bread.Expense.Amount)
+	require.Equal(t, groceries.ID, bread.Expense.CategoryID)
+	require.Equal(t, time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC), bread.Expense.CreatedOn)
+	require.Equal(t, []store.ExpensePayer{{UserID: 2, Amount: money.New(2501, "PLN")}}, bread.Expense.Payers)
+	require.Equal(t, []store.ExpenseShare{
+		{UserID: 1, Amount: money.New(1250, "PLN"), Covered: money.New(0, "PLN")},
+		{UserID: 2, Amount: money.New(1251, "PLN"), Covered: money.New(1251, "PLN")},
+	}, bread.Shares)
+
+	cinema := expenses[1]
+	require.Equal(t, other.ID, cinema.Expense.CategoryID)
+	require.Equal(t, importer.now, cinema.Expense.CreatedOn)
+	require.Equal(t, uint(1), cinema.Expense.Payers[0].UserID)
+
+	importer.fallback = nil
+	rows, _, err = importer.rows(records[1:2], mapping)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Choose a category for rows without one"}, rows[0].Errors)
+}
+
+func TestFallbackCategory(t *testing.T) {
+	food := store.Category{ID: 1, Name: "Food"}
+	misc := store.Category{ID: 2, Name: "Other", ParentID: &food.ID}
+	other := store.Category{ID: 3, Name: "other"}
+	categories := []store.Category{food, misc, other}
+
+	require.Equal(t, &other, fallbackCategory(categories, ""))
+	require.Equal(t, &food, fallbackCategory(categories, "1"))
+	require.Nil(t, fallbackCategory(categories, "9"))
+
+	// A household that renamed or archived "Other" has to choose.
+	require.Nil(t, fallbackCategory(categories[:2], ""))
+}
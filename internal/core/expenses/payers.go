@@ -0,0 +1,138 @@
+package expenses
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// severalPayers is the paid_by value for an expense paid by more than one
+// member, with the amounts in the paid_<userID> fields.
+const severalPayers = "several"
+
+// parsePayers reads who paid for an expense of amount, entered as
+// originalAmount. An empty paidBy means the member adding the expense paid it
+// all; otherwise paidBy is the user ID of a single payer or severalPayers.
+//
+// With several payers, values holds the raw amount each member paid, keyed by
+// user ID and in the currency the expense was entered in. They must add up to
+// originalAmount and are converted by splitting amount in the same
+// proportions, so the payers always add up to amount exactly. Payers are
+// returned in ascending user ID order.
+func parsePayers(paidBy string, values map[uint]string, members []store.Membership, amount, originalAmount money.Money, userID uint) ([]store.ExpensePayer, error) {
+	switch paidBy {
+	case "":
+		return []store.ExpensePayer{{UserID: userID, Amount: amount}}, nil
+	case severalPayers:
+		return severalPayerParts(values, members, amount, originalAmount)
+	}
+
+	payerID, err := strconv.ParseUint(paidBy, 10, 64)
+	if err != nil || !isMember(members, uint(payerID)) {
+		return nil, errors.New("Payer must be a member of the household")
+	}
+
+	return []store.ExpensePayer{{UserID: uint(payerID), Amount: amount}}, nil
+}
+
+func severalPayerParts(values map[uint]string, members []store.Membership, amount, originalAmount money.Money) ([]store.ExpensePayer, error) {
+	ordered := make([]store.Membership, len(members))
+	copy(ordered, members)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	weights := make([]int64, len(ordered))
+	var total int64
+
+	for i, member := range ordered {
+		value := strings.TrimSpace(values[member.UserID])
+		if value == "" {
+			continue
+		}
+
+		paid, err := money.Parse(value, originalAmount.Currency)
+		if err != nil || paid.Minor < 0 {
+			return nil, fmt.Errorf("Invalid amount paid by %s", member.User.Username)
+		}
+
+		weights[i] = paid.Minor
+		total += paid.Minor
+	}
+
+	if total != originalAmount.Minor {
+		return nil, errors.New("Amounts paid must add up to the expense amount")
+	}
+
+	var payers []store.ExpensePayer
+	for i, part := range amount.Allocate(weights) {
+		if part.Minor > 0 {
+			payers = append(payers, store.ExpensePayer{UserID: ordered[i].UserID, Amount: part})
+		}
+	}
+
+	return payers, nil
+}
+
+func isMember(members []store.Membership, userID uint) bool {
+	for _, m := range members {
+		if m.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// rescalePayers returns payers changed to add up to amount while keeping the
+// proportions in which they paid. It is used when an edit changes the amount.
+func rescalePayers(payers []store.ExpensePayer, amount money.Money) []store.ExpensePayer {
+	weights := make([]int64, len(payers))
+	for i, p := range payers {
+		weights[i] = p.Amount.Minor
+	}
+
+	rescaled := make([]store.ExpensePayer, 0, len(payers))
+	for i, part := range amount.Allocate(weights) {
+		if part.Minor > 0 {
+			p := payers[i]
+			p.Amount = part
+			rescaled = append(rescaled, p)
+		}
+	}
+
+	return rescaled
+}
+
+// mainPayer returns the user ID of the member who paid the most, the lowest
+// user ID on a tie. The remainder of a split goes to them first.
+func mainPayer(payers []store.ExpensePayer) uint {
+	var main store.ExpensePayer
+	for _, p := range payers {
+		if p.Amount.Minor > main.Amount.Minor || (p.Amount.Minor == main.Amount.Minor && p.UserID < main.UserID) {
+			main = p
+		}
+	}
+	return main.UserID
+}
+
+// coveredParts returns the part of each share, keyed by user ID, that its user
+// already paid as a payer of the expense: the smaller of the share and what
+// they paid. Members who paid nothing cover nothing.
+func coveredParts(shares map[uint]money.Money, payers []store.ExpensePayer) map[uint]money.Money {
+	paid := make(map[uint]money.Money, len(payers))
+	for _, p := range payers {
+		paid[p.UserID] = paid[p.UserID].Add(p.Amount)
+	}
+
+	covered := make(map[uint]money.Money, len(shares))
+	for userID, share := range shares {
+		covered[userID] = money.New(min(paid[userID].Minor, share.Minor), share.Currency)
+	}
+
+	return covered
+}
@@ -0,0 +1,115 @@
+package expenses
+
+import (
+	"testing"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePayers(t *testing.T) {
+	members := []store.Membership{
+		{UserID: 3, User: store.User{ID: 3, Username: "ewa"}},
+		{UserID: 1, User: store.User{ID: 1, Username: "anna"}},
+		{UserID: 2, User: store.User{ID: 2, Username: "piotr"}},
+	}
+
+	tests := []struct {
+		name     string
+		paidBy   string
+		values   map[uint]string
+		original money.Money
+		amount   money.Money
+		want     map[uint]int64
+		wantErr  string
+	}{
+		{
+			name:     "defaults to the member adding the expense",
+			original: money.New(10000, "PLN"),
+			amount:   money.New(10000, "PLN"),
+			want:     map[uint]int64{1: 10000},
+		},
+		{
+			name:     "another member",
+			paidBy:   "3",
+			original: money.New(10000, "PLN"),
+			amount:   money.New(10000, "PLN"),
+			want:     map[uint]int64{3: 10000},
+		},
+		{
+			name:     "not a member",
+			paidBy:   "9",
+			original: money.New(10000, "PLN"),
+			amount:   money.New(10000, "PLN"),
+			wantErr:  "Payer must be a member of the household",
+		},
+		{
+			name:     "several",
+			paidBy:   severalPayers,
+			values:   map[uint]string{2: "60", 3: "40"},
+			original: money.New(10000, "PLN"),
+			amount:   money.New(10000, "PLN"),
+			want:     map[uint]int64{2: 6000, 3: 4000},
+		},
+		{
+			name:     "several converted in proportion",
+			paidBy:   severalPayers,
+			values:   map[uint]string{1: "10", 2: "20"},
+			original: money.New(3000, "EUR"),
+			amount:   money.New(12800, "PLN"),
+			want:     map[uint]int64{1: 4267, 2: 8533},
+		},
+		{
+			name:     "several must add up",
+			paidBy:   severalPayers,
+			values:   map[uint]string{1: "10", 2: "20"},
+			original: money.New(4000, "PLN"),
+			amount:   money.New(4000, "PLN"),
+			wantErr:  "Amounts paid must add up to the expense amount",
+		},
+		{
+			name:     "several invalid amount",
+			paidBy:   severalPayers,
+			values:   map[uint]string{2: "abc"},
+			original: money.New(4000, "PLN"),
+			amount:   money.New(4000, "PLN"),
+			wantErr:  "Invalid amount paid by piotr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payers, err := parsePayers(tt.paidBy, tt.values, members, tt.amount, tt.original, 1)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make(map[uint]int64, len(payers))
+			for _, p := range payers {
+				got[p.UserID] = p.Amount.Minor
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCoveredParts(t *testing.T) {
+	shares := map[uint]money.Money{
+		1: money.New(3000, "PLN"),
+		2: money.New(3000, "PLN"),
+		3: money.New(3000, "PLN"),
+	}
+	payers := []store.ExpensePayer{
+		{UserID: 1, Amount: money.New(7000, "PLN")},
+		{UserID: 2, Amount: money.New(2000, "PLN")},
+	}
+
+	require.Equal(t, map[uint]money.Money{
+		1: money.New(3000, "PLN"),
+		2: money.New(2000, "PLN"),
+		3: money.New(0, "PLN"),
+	}, coveredParts(shares, payers))
+}
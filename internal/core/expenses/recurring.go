@@ -0,0 +1,292 @@
+package expenses
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+// maxRecurringNameLength leaves room for the date that is appended to the name
+// of every occurrence.
+const maxRecurringNameLength = maxExpenseNameLength - len(" 2006-01-02")
+
+type GetRecurringExpensesHandler struct {
+	recurringExpenseStore store.RecurringExpenseStore
+	membershipStore       store.MembershipStore
+	categoryStore         store.CategoryStore
+}
+
+type GetRecurringExpensesHandlerParams struct {
+	RecurringExpenseStore store.RecurringExpenseStore
+	MembershipStore       store.MembershipStore
+	CategoryStore         store.CategoryStore
+}
+
+func NewGetRecurringExpensesHandler(params GetRecurringExpensesHandlerParams) *GetRecurringExpensesHandler {
+	return &GetRecurringExpensesHandler{
+		recurringExpenseStore: params.RecurringExpenseStore,
+		membershipStore:       params.MembershipStore,
+		categoryStore:         params.CategoryStore,
+	}
+}
+
+func (h *GetRecurringExpensesHandler) GetRecurringExpenses(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	recurring, err := h.recurringExpenseStore.GetRecurringExpensesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch recurring expenses", http.StatusInternalServerError)
+		return
+	}
+
+	members, err := h.membershipStore.GetMembersByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch members", http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := h.categoryStore.GetCategoriesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.RecurringExpenses(recurring, members, activeCategories(categories, 0), membership).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostRecurringExpenseHandler struct {
+	recurringExpenseStore store.RecurringExpenseStore
+	householdStore        store.HouseholdStore
+	membershipStore       store.MembershipStore
+	exchangeRateStore     store.ExchangeRateStore
+	categoryStore         store.CategoryStore
+}
+
+type PostRecurringExpenseHandlerParams struct {
+	RecurringExpenseStore store.RecurringExpenseStore
+	HouseholdStore        store.HouseholdStore
+	MembershipStore       store.MembershipStore
+	ExchangeRateStore     store.ExchangeRateStore
+	CategoryStore         store.CategoryStore
+}
+
+func NewPostRecurringExpenseHandler(params PostRecurringExpenseHandlerParams) *PostRecurringExpenseHandler {
+	return &PostRecurringExpenseHandler{
+		recurringExpenseStore: params.RecurringExpenseStore,
+		householdStore:        params.HouseholdStore,
+		membershipStore:       params.MembershipStore,
+		exchangeRateStore:     params.ExchangeRateStore,
+		categoryStore:         params.CategoryStore,
+	}
+}
+
+// PostRecurringExpense defines a recurring expense. The amount and split are
+// checked against today's exchange rate and members, so a definition that
+// could not be materialized right now is rejected up front.
+func (h *PostRecurringExpenseHandler) PostRecurringExpense(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermAddExpense) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if len(name) == 0 || len(name) > maxRecurringNameLength {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error(
+			"Create failed",
+			fmt.Sprintf("Name must have between 1 and %d characters", maxRecurringNameLength),
+		)
+		c.Render(r.Context(), w)
+		return
+	}
+
+	household, err := h.householdStore.GetHouseholdByID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "invalid household", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+
+	amount, originalAmount, ok := parseExpenseAmount(w, r, h.exchangeRateStore, household, now, "Create failed")
+	if !ok {
+		return
+	}
+
+	category, err := parseCategory(h.categoryStore, household.ID, r.FormValue("category"), 0)
+	if err != nil {
+		http.Error(w, "invalid category", http.StatusBadRequest)
+		return
+	}
+
+	splitMode := store.SplitMode(r.FormValue("split_mode"))
+	if splitMode == "" {
+		splitMode = store.SplitEqual
+	}
+
+	if !splitMode.IsValid() {
+		http.Error(w, "invalid split mode", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.membershipStore.GetMembersByHouseholdID(household.ID)
+	if err != nil || len(members) == 0 {
+		http.Error(w, "cannot fetch household members", http.StatusInternalServerError)
+		return
+	}
+
+	paidBy := r.FormValue("paid_by")
+	if paidBy == severalPayers {
+		http.Error(w, "a recurring expense has a single payer", http.StatusBadRequest)
+		return
+	}
+
+	payers, err := parsePayers(paidBy, nil, members, amount, originalAmount, user.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	splitValues := make(map[uint]string, len(members))
+	var splits []store.RecurringExpenseSplit
+	for _, member := range members {
+		value := strings.TrimSpace(r.FormValue(fmt.Sprintf("split_%d", member.UserID)))
+		splitValues[member.UserID] = value
+		if splitMode != store.SplitEqual && value != "" {
+			splits = append(splits, store.RecurringExpenseSplit{UserID: member.UserID, Value: value})
+		}
+	}
+
+	if _, err := splitExpense(splitMode, amount, members, splitValues, payers[0].UserID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	recurring := store.RecurringExpense{
+		Name:        name,
+		Amount:      originalAmount,
+		CategoryID:  category.ID,
+		SplitMode:   splitMode,
+		Splits:      splits,
+		Frequency:   store.Frequency(r.FormValue("frequency")),
+		Cron:        strings.TrimSpace(r.FormValue("cron")),
+		PaidByID:    payers[0].UserID,
+		HouseholdID: household.ID,
+		CreatedByID: user.ID,
+	}
+	recurring.Day, _ = strconv.Atoi(r.FormValue("day"))
+	recurring.Month, _ = strconv.Atoi(r.FormValue("month"))
+
+	sched, err := recurring.Schedule()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	startsOn := now.UTC().Truncate(24 * time.Hour)
+	if startsOnStr := r.FormValue("starts_on"); startsOnStr != "" {
+		startsOn, err = time.Parse(time.DateOnly, startsOnStr)
+		if err != nil {
+			http.Error(w, "invalid start date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	recurring.NextRunOn = sched.Next(startsOn.Add(-time.Nanosecond))
+	if recurring.NextRunOn.IsZero() {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", "This schedule never falls due")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if _, err := h.recurringExpenseStore.CreateRecurringExpense(recurring); err != nil {
+		http.Error(w, "cannot create recurring expense", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostDeleteRecurringExpense stops a recurring expense. The expenses it
+// already created are kept.
+func (h *PostRecurringExpenseHandler) PostDeleteRecurringExpense(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	recurringID, err := strconv.ParseUint(chi.URLParam(r, "recurringID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid recurring expense id", http.StatusBadRequest)
+		return
+	}
+
+	recurring, err := h.recurringExpenseStore.GetRecurringExpenseByID(uint(recurringID))
+	if err != nil {
+		http.Error(w, "Recurring expense not found", http.StatusNotFound)
+		return
+	}
+
+	if !membership.CanEditRecurringExpense(recurring) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := h.recurringExpenseStore.DeleteRecurringExpense(recurring.ID); err != nil {
+		http.Error(w, "cannot stop recurring expense", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
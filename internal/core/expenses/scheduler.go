@@ -0,0 +1,202 @@
+package expenses
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/schedule"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// maxCatchUp is how many occurrences of one recurring expense a run
+// materializes at most, so that a long downtime is caught up over several runs
+// rather than in one burst.
+const maxCatchUp = 50
+
+// RecurringScheduler turns recurring expenses into expenses as they fall due.
+// It runs inside the server process; occurrences missed while the server was
+// down are caught up on the next run.
+type RecurringScheduler struct {
+	recurringExpenseStore store.RecurringExpenseStore
+	membershipStore       store.MembershipStore
+	exchangeRateStore     store.ExchangeRateStore
+	interval              time.Duration
+}
+
+type RecurringSchedulerParams struct {
+	RecurringExpenseStore store.RecurringExpenseStore
+	MembershipStore       store.MembershipStore
+	ExchangeRateStore     store.ExchangeRateStore
+	Interval              time.Duration
+}
+
+func NewRecurringScheduler(params RecurringSchedulerParams) *RecurringScheduler {
+	return &RecurringScheduler{
+		recurringExpenseStore: params.RecurringExpenseStore,
+		membershipStore:       params.MembershipStore,
+		exchangeRateStore:     params.ExchangeRateStore,
+		interval:              params.Interval,
+	}
+}
+
+// Run materializes due expenses right away and then every interval until ctx
+// is cancelled. A run in progress when ctx is cancelled finishes its current
+// occurrence first, as every occurrence is saved in its own transaction.
+func (s *RecurringScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue materializes the occurrences due at or before now, up to maxCatchUp
+// per recurring expense. A recurring expense that fails is logged and retried
+// from the same occurrence on the next run, so no occurrence is ever skipped.
+func (s *RecurringScheduler) RunDue(ctx context.Context, now time.Time) {
+	due, err := s.recurringExpenseStore.GetDueRecurringExpenses(now)
+	if err != nil {
+		log.Printf("cannot fetch due recurring expenses: %v", err)
+		return
+	}
+
+	for _, recurring := range due {
+		if err := s.catchUp(ctx, recurring, now); err != nil {
+			log.Printf("cannot materialize recurring expense %d: %v", recurring.ID, err)
+		}
+	}
+}
+
+// catchUp materializes the due occurrences of recurring one at a time, oldest
+// first, and at most maxCatchUp of them; the rest are left to the next run. A
+// schedule without further occurrences stops the recurring expense.
+func (s *RecurringScheduler) catchUp(ctx context.Context, recurring store.RecurringExpense, now time.Time) error {
+	sched, err := recurring.Schedule()
+	if err != nil {
+		return err
+	}
+
+	materialized := 0
+	for occurrence := recurring.NextRunOn; !occurrence.After(now); {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if materialized == maxCatchUp {
+			log.Printf("recurring expense %d has %d more occurrences due, left to the next run", recurring.ID, dueOccurrences(sched, occurrence, now))
+			return nil
+		}
+
+		expense, shares, err := s.occurrence(recurring, occurrence)
+		if err != nil {
+			return fmt.Errorf("occurrence %s: %w", occurrence.Format(time.DateOnly), err)
+		}
+
+		next := sched.Next(occurrence)
+		if err := s.recurringExpenseStore.MaterializeRecurringExpense(expense, shares, next); err != nil {
+			return err
+		}
+
+		if next.IsZero() {
+			return s.recurringExpenseStore.DeleteRecurringExpense(recurring.ID)
+		}
+
+		materialized++
+		occurrence = next
+	}
+
+	return nil
+}
+
+// dueOccurrences counts the occurrences of sched from occurrence on that are
+// due at or before now.
+func dueOccurrences(sched schedule.Schedule, occurrence, now time.Time) int {
+	count := 0
+	for ; !occurrence.IsZero() && !occurrence.After(now); occurrence = sched.Next(occurrence) {
+		count++
+	}
+	return count
+}
+
+// occurrence builds the expense and shares of recurring due on occurrenceOn,
+// split between whoever is a member of the household at that point.
+func (s *RecurringScheduler) occurrence(recurring store.RecurringExpense, occurrenceOn time.Time) (store.Expense, []store.ExpenseShare, error) {
+	amount, err := s.exchangeRateStore.Convert(recurring.Amount, recurring.Household.BaseCurrency, occurrenceOn)
+	if err != nil {
+		return store.Expense{}, nil, err
+	}
+
+	members, err := s.membershipStore.GetMembersByHouseholdID(recurring.HouseholdID)
+	if err != nil {
+		return store.Expense{}, nil, err
+	}
+
+	return recurringOccurrence(recurring, occurrenceOn, amount, members)
+}
+
+// recurringOccurrence is the expense materialized from recurring on
+// occurrenceOn, with amount already in the household currency. The expense is
+// named after the recurring expense and the date, so every occurrence gets a
+// unique name.
+//
+// Split values no longer matching the members, e.g. after someone left the
+// household, fall back to an equal split of the occurrence, so that the
+// recurring expense keeps moving on instead of failing on every run.
+func recurringOccurrence(recurring store.RecurringExpense, occurrenceOn time.Time, amount money.Money, members []store.Membership) (store.Expense, []store.ExpenseShare, error) {
+	values := make(map[uint]string, len(recurring.Splits))
+	for _, split := range recurring.Splits {
+		values[split.UserID] = split.Value
+	}
+
+	payers := []store.ExpensePayer{{UserID: recurring.PaidByID, Amount: amount}}
+
+	splitMode := recurring.SplitMode
+	split, err := splitExpense(splitMode, amount, members, values, recurring.PaidByID)
+	if err != nil && splitMode != store.SplitEqual {
+		log.Printf("recurring expense %d splits equally on %s: %v", recurring.ID, occurrenceOn.Format(time.DateOnly), err)
+		splitMode = store.SplitEqual
+		split, err = splitExpense(splitMode, amount, members, nil, recurring.PaidByID)
+	}
+	if err != nil {
+		return store.Expense{}, nil, err
+	}
+
+	covered := coveredParts(split, payers)
+
+	var shares []store.ExpenseShare
+	for _, member := range members {
+		part, ok := split[member.UserID]
+		if !ok {
+			continue
+		}
+		shares = append(shares, store.ExpenseShare{UserID: member.UserID, Amount: part, Covered: covered[member.UserID]})
+	}
+
+	recurringID := recurring.ID
+
+	expense := store.Expense{
+		Name:               fmt.Sprintf("%s %s", recurring.Name, occurrenceOn.Format(time.DateOnly)),
+		Amount:             amount,
+		OriginalAmount:     recurring.Amount,
+		CategoryID:         recurring.CategoryID,
+		SplitMode:          splitMode,
+		CreatedOn:          occurrenceOn,
+		CreatedByID:        recurring.CreatedByID,
+		HouseholdID:        recurring.HouseholdID,
+		Payers:             payers,
+		RecurringExpenseID: &recurringID,
+		OccurrenceOn:       &occurrenceOn,
+	}
+
+	return expense, shares, nil
+}
@@ -0,0 +1,156 @@
+package expenses
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	storemock "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringOccurrence(t *testing.T) {
+	members := []store.Membership{
+		{UserID: 1, User: store.User{ID: 1, Username: "anna"}},
+		{UserID: 2, User: store.User{ID: 2, Username: "piotr"}},
+	}
+	recurring := store.RecurringExpense{
+		ID:          7,
+		Name:        "Rent",
+		Amount:      money.New(50000, "EUR"),
+		CategoryID:  4,
+		SplitMode:   store.SplitPercentage,
+		Splits:      []store.RecurringExpenseSplit{{UserID: 1, Value: "60"}, {UserID: 2, Value: "40"}},
+		PaidByID:    2,
+		HouseholdID: 3,
+		CreatedByID: 1,
+	}
+	occurrenceOn := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	expense, shares, err := recurringOccurrence(recurring, occurrenceOn, money.New(200000, "PLN"), members)
+	require.NoError(t, err)
+
+	require.Equal(t, "Rent 2026-10-01", expense.Name)
+	require.Equal(t, money.New(200000, "PLN"), expense.Amount)
+	require.Equal(t, money.New(50000, "EUR"), expense.OriginalAmount)
+	require.Equal(t, occurrenceOn, expense.CreatedOn)
+	require.Equal(t, uint(4), expense.CategoryID)
+	require.Equal(t, occurrenceOn, *expense.OccurrenceOn)
+	require.Equal(t, uint(7), *expense.RecurringExpenseID)
+	require.Equal(t, []store.ExpensePayer{{UserID: 2, Amount: money.New(200000, "PLN")}}, expense.Payers)
+
+	require.Equal(t, []store.ExpenseShare{
+		{UserID: 1, Amount: money.New(120000, "PLN"), Covered: money.New(0, "PLN")},
+		{UserID: 2, Amount: money.New(80000, "PLN"), Covered: money.New(80000, "PLN")},
+	}, shares)
+
+	recurring.Splits = []store.RecurringExpenseSplit{{UserID: 1, Value: "60"}}
+	expense, shares, err = recurringOccurrence(recurring, occurrenceOn, money.New(200000, "PLN"), members)
+	require.NoError(t, err)
+
+	require.Equal(t, store.SplitEqual, expense.SplitMode)
+	require.Equal(t, []store.ExpenseShare{
+		{UserID: 1, Amount: money.New(100000, "PLN"), Covered: money.New(0, "PLN")},
+		{UserID: 2, Amount: money.New(100000, "PLN"), Covered: money.New(100000, "PLN")},
+	}, shares)
+}
+
+func TestRecurringScheduler_MemberLeft(t *testing.T) {
+	recurringExpenseStore := &storemock.RecurringExpenseStoreMock{}
+	membershipStore := &storemock.MembershipStoreMock{}
+	exchangeRateStore := &storemock.ExchangeRateStoreMock{}
+
+	september := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	// Piotr, who was split 40%, has left the household and Ewa joined.
+	recurring := store.RecurringExpense{
+		ID:          7,
+		Name:        "Rent",
+		Amount:      money.New(90000, "PLN"),
+		SplitMode:   store.SplitPercentage,
+		Splits:      []store.RecurringExpenseSplit{{UserID: 1, Value: "60"}, {UserID: 2, Value: "40"}},
+		Frequency:   store.FrequencyMonthly,
+		Day:         1,
+		NextRunOn:   september,
+		PaidByID:    1,
+		HouseholdID: 3,
+		Household:   store.Household{ID: 3, BaseCurrency: "PLN"},
+	}
+	members := []store.Membership{
+		{UserID: 1, User: store.User{ID: 1, Username: "anna"}},
+		{UserID: 3, User: store.User{ID: 3, Username: "ewa"}},
+	}
+
+	recurringExpenseStore.On("GetDueRecurringExpenses", now).Return([]store.RecurringExpense{recurring}, nil)
+	membershipStore.On("GetMembersByHouseholdID", uint(3)).Return(members, nil)
+	exchangeRateStore.On("Convert", recurring.Amount, "PLN", mock.Anything).Return(recurring.Amount, nil)
+
+	equalShares := []store.ExpenseShare{
+		{UserID: 1, Amount: money.New(45000, "PLN"), Covered: money.New(45000, "PLN")},
+		{UserID: 3, Amount: money.New(45000, "PLN"), Covered: money.New(0, "PLN")},
+	}
+	isOccurrence := func(on time.Time) any {
+		return mock.MatchedBy(func(expense store.Expense) bool {
+			return expense.OccurrenceOn.Equal(on) && expense.SplitMode == store.SplitEqual
+		})
+	}
+	recurringExpenseStore.On("MaterializeRecurringExpense", isOccurrence(september), equalShares, october).Return(nil)
+	recurringExpenseStore.On("MaterializeRecurringExpense", isOccurrence(october), equalShares, november).Return(nil)
+
+	scheduler := NewRecurringScheduler(RecurringSchedulerParams{
+		RecurringExpenseStore: recurringExpenseStore,
+		MembershipStore:       membershipStore,
+		ExchangeRateStore:     exchangeRateStore,
+	})
+
+	scheduler.RunDue(context.Background(), now)
+
+	recurringExpenseStore.AssertExpectations(t)
+	recurringExpenseStore.AssertNumberOfCalls(t, "MaterializeRecurringExpense", 2)
+}
+
+func TestRecurringScheduler_CatchUpLimit(t *testing.T) {
+	recurringExpenseStore := &storemock.RecurringExpenseStoreMock{}
+	membershipStore := &storemock.MembershipStoreMock{}
+	exchangeRateStore := &storemock.ExchangeRateStoreMock{}
+
+	firstDue := time.Date(2026, time.August, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	recurring := store.RecurringExpense{
+		ID:          7,
+		Name:        "Bread",
+		Amount:      money.New(500, "PLN"),
+		SplitMode:   store.SplitEqual,
+		Frequency:   store.FrequencyCron,
+		Cron:        "0 9 * * *",
+		NextRunOn:   firstDue,
+		PaidByID:    1,
+		HouseholdID: 3,
+		Household:   store.Household{ID: 3, BaseCurrency: "PLN"},
+	}
+
+	recurringExpenseStore.On("GetDueRecurringExpenses", now).Return([]store.RecurringExpense{recurring}, nil)
+	membershipStore.On("GetMembersByHouseholdID", uint(3)).Return([]store.Membership{{UserID: 1}}, nil)
+	exchangeRateStore.On("Convert", recurring.Amount, "PLN", mock.Anything).Return(recurring.Amount, nil)
+	recurringExpenseStore.On("MaterializeRecurringExpense", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	scheduler := NewRecurringScheduler(RecurringSchedulerParams{
+		RecurringExpenseStore: recurringExpenseStore,
+		MembershipStore:       membershipStore,
+		ExchangeRateStore:     exchangeRateStore,
+	})
+
+	scheduler.RunDue(context.Background(), now)
+
+	// 76 daily occurrences are due; the rest are left to the next run.
+	recurringExpenseStore.AssertNumberOfCalls(t, "MaterializeRecurringExpense", maxCatchUp)
+	last := recurringExpenseStore.Calls[len(recurringExpenseStore.Calls)-1]
+	require.Equal(t, firstDue.AddDate(0, 0, maxCatchUp), last.Arguments.Get(2))
+}
@@ -0,0 +1,194 @@
+package expenses
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// splitExpense divides amount between members according to mode. values holds
+// the raw per-member form input keyed by user ID and is ignored for the equal
+// mode. The returned amounts are keyed by user ID and always add up to amount;
+// members with a zero share are left out.
+//
+// Minor units that cannot be divided evenly go to the payer first and then to the
+// remaining members in ascending user ID order, so the same input always
+// produces the same shares.
+func splitExpense(mode store.SplitMode, amount money.Money, members []store.Membership, values map[uint]string, payerID uint) (map[uint]money.Money, error) {
+	members = remainderOrder(members, payerID)
+
+	var parts []money.Money
+	var err error
+
+	switch mode {
+	case store.SplitEqual:
+		parts = amount.Allocate(equalWeights(len(members)))
+	case store.SplitExact:
+		parts, err = exactParts(amount, members, values)
+	case store.SplitPercentage:
+		parts, err = percentageParts(amount, members, values)
+	case store.SplitShares:
+		parts, err = weightedParts(amount, members, values)
+	default:
+		return nil, errors.New("Unknown split mode")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if money.Sum(amount.Currency, parts) != amount {
+		return nil, errors.New("Shares must add up to the expense amount")
+	}
+
+	shares := make(map[uint]money.Money, len(members))
+	for i, member := range members {
+		if parts[i].Minor > 0 {
+			shares[member.UserID] = parts[i]
+		}
+	}
+
+	return shares, nil
+}
+
+// remainderOrder returns a copy of members with the payer first and everyone
+// else sorted by user ID.
+func remainderOrder(members []store.Membership, payerID uint) []store.Membership {
+	ordered := make([]store.Membership, len(members))
+	copy(ordered, members)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if (ordered[i].UserID == payerID) != (ordered[j].UserID == payerID) {
+			return ordered[i].UserID == payerID
+		}
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	return ordered
+}
+
+func exactParts(total money.Money, members []store.Membership, values map[uint]string) ([]money.Money, error) {
+	parts := make([]money.Money, len(members))
+
+	for i, member := range members {
+		value, err := money.Parse(values[member.UserID], total.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid amount for %s", member.User.Username)
+		}
+		parts[i] = value
+	}
+
+	if sum := money.Sum(total.Currency, parts); sum != total {
+		return nil, fmt.Errorf("Amounts add up to %s instead of %s", sum, total)
+	}
+
+	return parts, nil
+}
+
+func percentageParts(total money.Money, members []store.Membership, values map[uint]string) ([]money.Money, error) {
+	// Percentages are kept in hundredths of a percent, so 100% is 10000.
+	const fullPercentage = 10000
+
+	weights := make([]int64, len(members))
+	var sum int64
+
+	for i, member := range members {
+		value, err := money.ParseDecimal(values[member.UserID], 2)
+		if err != nil || value < 0 || value > fullPercentage {
+			return nil, fmt.Errorf("Invalid percentage for %s", member.User.Username)
+		}
+		weights[i] = value
+		sum += value
+	}
+
+	if sum != fullPercentage {
+		return nil, fmt.Errorf("Percentages add up to %.2f%% instead of 100%%", float64(sum)/100)
+	}
+
+	return total.Allocate(weights), nil
+}
+
+// maxShares is the most shares a single member can have in a split by shares,
+// which keeps the sum of the shares of a whole household far from overflowing.
+const maxShares = 10000
+
+func weightedParts(total money.Money, members []store.Membership, values map[uint]string) ([]money.Money, error) {
+	weights := make([]int64, len(members))
+	var sum int64
+
+	for i, member := range members {
+		value, err := strconv.ParseInt(strings.TrimSpace(values[member.UserID]), 10, 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("Invalid number of shares for %s", member.User.Username)
+		}
+		if value > maxShares {
+			return nil, fmt.Errorf("%s can have at most %d shares", member.User.Username, maxShares)
+		}
+		weights[i] = value
+		sum += value
+	}
+
+	if sum == 0 {
+		return nil, errors.New("At least one member must have a share")
+	}
+
+	return total.Allocate(weights), nil
+}
+
+func equalWeights(n int) []int64 {
+	weights := make([]int64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+// resplitExpense divides the edited amount of an expense between the members
+// who already have a share in it. Locked shares keep their amounts and the
+// rest is split between the other members with splitExpense. The result holds
+// the new amount of every share that is not locked, zero included, keyed by
+// user ID.
+func resplitExpense(mode store.SplitMode, amount money.Money, shares []store.ExpenseShare, values map[uint]string, payerID uint) (map[uint]money.Money, error) {
+	rest := amount
+	members := make([]store.Membership, 0, len(shares))
+	for _, s := range shares {
+		if s.Locked() {
+			rest = rest.Sub(s.Amount)
+			continue
+		}
+		members = append(members, store.Membership{UserID: s.UserID})
+	}
+
+	if rest.Minor < 0 {
+		return nil, errors.New("Amount cannot be less than the shares that are already paid")
+	}
+
+	result := make(map[uint]money.Money, len(members))
+	for _, m := range members {
+		result[m.UserID] = money.New(0, amount.Currency)
+	}
+
+	if rest.IsZero() {
+		return result, nil
+	}
+
+	if len(members) == 0 {
+		return nil, errors.New("Every share is already paid, the amount can no longer change")
+	}
+
+	split, err := splitExpense(mode, rest, members, values, payerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for userID, part := range split {
+		result[userID] = part
+	}
+
+	return result, nil
+}
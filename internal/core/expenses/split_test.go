@@ -0,0 +1,267 @@
+package expenses
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitExpense(t *testing.T) {
+	members := []store.Membership{
+		{UserID: 1, User: store.User{ID: 1, Username: "anna"}},
+		{UserID: 2, User: store.User{ID: 2, Username: "piotr"}},
+		{UserID: 3, User: store.User{ID: 3, Username: "ewa"}},
+	}
+
+	tests := []struct {
+		name   string
+		mode   store.SplitMode
+		amount int64
+		payer  uint
+		values map[uint]string
+		want   map[uint]int64
+	}{
+		{
+			name:   "equal",
+			mode:   store.SplitEqual,
+			amount: 10000,
+			payer:  1,
+			want:   map[uint]int64{1: 3334, 2: 3333, 3: 3333},
+		},
+		{
+			name:   "equal remainder goes to payer first",
+			mode:   store.SplitEqual,
+			amount: 10000,
+			payer:  3,
+			want:   map[uint]int64{1: 3333, 2: 3333, 3: 3334},
+		},
+		{
+			name:   "equal remainder continues by user id",
+			mode:   store.SplitEqual,
+			amount: 10001,
+			payer:  3,
+			want:   map[uint]int64{1: 3334, 2: 3333, 3: 3334},
+		},
+		{
+			name:   "exact",
+			mode:   store.SplitExact,
+			amount: 10000,
+			values: map[uint]string{1: "50", 2: "49.99", 3: "0.01"},
+			want:   map[uint]int64{1: 5000, 2: 4999, 3: 1},
+		},
+		{
+			name:   "exact skips members without a share",
+			mode:   store.SplitExact,
+			amount: 3000,
+			values: map[uint]string{1: "30"},
+			want:   map[uint]int64{1: 3000},
+		},
+		{
+			name:   "percentage",
+			mode:   store.SplitPercentage,
+			amount: 150000,
+			values: map[uint]string{1: "50", 2: "33.33", 3: "16.67"},
+			want:   map[uint]int64{1: 75000, 2: 49995, 3: 25005},
+		},
+		{
+			name:   "shares",
+			mode:   store.SplitShares,
+			amount: 1000,
+			values: map[uint]string{1: "2", 2: "1", 3: "0"},
+			payer:  1,
+			want:   map[uint]int64{1: 667, 2: 333},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitExpense(tt.mode, money.New(tt.amount, "PLN"), members, tt.values, tt.payer)
+			require.NoError(t, err)
+
+			want := make(map[uint]money.Money, len(tt.want))
+			for userID, minor := range tt.want {
+				want[userID] = money.New(minor, "PLN")
+			}
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestSplitExpense_Invalid(t *testing.T) {
+	members := []store.Membership{
+		{UserID: 1, User: store.User{ID: 1, Username: "anna"}},
+		{UserID: 2, User: store.User{ID: 2, Username: "piotr"}},
+	}
+
+	tests := []struct {
+		name   string
+		mode   store.SplitMode
+		values map[uint]string
+	}{
+		{name: "exact below amount", mode: store.SplitExact, values: map[uint]string{1: "10", 2: "10"}},
+		{name: "exact above amount", mode: store.SplitExact, values: map[uint]string{1: "90", 2: "10.01"}},
+		{name: "exact three decimals", mode: store.SplitExact, values: map[uint]string{1: "50.005", 2: "49.995"}},
+		{name: "exact negative", mode: store.SplitExact, values: map[uint]string{1: "110", 2: "-10"}},
+		{name: "percentage not 100", mode: store.SplitPercentage, values: map[uint]string{1: "50", 2: "49"}},
+		{name: "shares all zero", mode: store.SplitShares, values: map[uint]string{1: "0", 2: "0"}},
+		{name: "shares not a number", mode: store.SplitShares, values: map[uint]string{1: "one", 2: "1"}},
+		{name: "shares above limit", mode: store.SplitShares, values: map[uint]string{1: "10001", 2: "1"}},
+		{name: "shares overflowing", mode: store.SplitShares, values: map[uint]string{1: "9223372036854775807", 2: "1"}},
+		{name: "percentage overflowing", mode: store.SplitPercentage, values: map[uint]string{1: "92233720368547758.07", 2: "-92233720368547658.07"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := splitExpense(tt.mode, money.New(10000, "PLN"), members, tt.values, 1)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSplitExpense_SharesAddUpToAmount(t *testing.T) {
+	property := func(cents uint32, memberCount uint8, payerIndex uint8, seed int64) bool {
+		amount := money.New(int64(cents%10_000_000+1000), "PLN")
+		n := int(memberCount%12) + 1
+
+		members := make([]store.Membership, n)
+		for i := range members {
+			members[i] = store.Membership{UserID: uint(i*7 + 1)}
+		}
+		payer := members[int(payerIndex)%n].UserID
+
+		rng := rand.New(rand.NewSource(seed))
+		weights := make(map[uint]string, n)
+		for _, m := range members {
+			weights[m.UserID] = strconv.Itoa(rng.Intn(5))
+		}
+		weights[payer] = strconv.Itoa(rng.Intn(5) + 1)
+
+		for _, mode := range []store.SplitMode{store.SplitEqual, store.SplitShares} {
+			shares, err := splitExpense(mode, amount, members, weights, payer)
+			if err != nil {
+				return false
+			}
+
+			sum := money.New(0, "PLN")
+			for _, share := range shares {
+				sum = sum.Add(share)
+			}
+			if sum != amount {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestSplitExpense_EqualSharesDifferByAtMostOneCent(t *testing.T) {
+	property := func(cents uint32, memberCount uint8) bool {
+		amount := money.New(int64(cents%10_000_000+1000), "PLN")
+		n := int(memberCount%12) + 1
+
+		members := make([]store.Membership, n)
+		for i := range members {
+			members[i] = store.Membership{UserID: uint(i + 1)}
+		}
+
+		shares, err := splitExpense(store.SplitEqual, amount, members, nil, 1)
+		if err != nil {
+			return false
+		}
+
+		lowest, highest := amount.Minor, int64(0)
+		for _, share := range shares {
+			lowest = min(lowest, share.Minor)
+			highest = max(highest, share.Minor)
+		}
+
+		return highest-lowest <= 1 && shares[1].Minor == highest
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestResplitExpense(t *testing.T) {
+	pln := func(minor int64) money.Money { return money.New(minor, "PLN") }
+	share := func(userID uint, minor int64, paid bool) store.ExpenseShare {
+		s := store.ExpenseShare{UserID: userID, Amount: pln(minor)}
+		if paid {
+			s.Payments = []store.Payment{{Amount: pln(minor)}}
+		}
+		return s
+	}
+
+	testCases := []struct {
+		name    string
+		mode    store.SplitMode
+		amount  money.Money
+		shares  []store.ExpenseShare
+		values  map[uint]string
+		want    map[uint]money.Money
+		wantErr bool
+	}{
+		{
+			name:   "nothing paid",
+			mode:   store.SplitEqual,
+			amount: pln(9000),
+			shares: []store.ExpenseShare{share(1, 2000, false), share(2, 2000, false), share(3, 2000, false)},
+			want:   map[uint]money.Money{1: pln(3000), 2: pln(3000), 3: pln(3000)},
+		},
+		{
+			name:   "paid share kept",
+			mode:   store.SplitEqual,
+			amount: pln(8000),
+			shares: []store.ExpenseShare{share(1, 2000, false), share(2, 2000, true), share(3, 2000, false)},
+			want:   map[uint]money.Money{1: pln(3000), 3: pln(3000)},
+		},
+		{
+			name:   "exact split of the rest",
+			mode:   store.SplitExact,
+			amount: pln(5000),
+			shares: []store.ExpenseShare{share(1, 2000, false), share(2, 2000, true), share(3, 2000, false)},
+			values: map[uint]string{1: "30.00", 3: "0"},
+			want:   map[uint]money.Money{1: pln(3000), 3: pln(0)},
+		},
+		{
+			name:   "amount equal to paid shares",
+			mode:   store.SplitEqual,
+			amount: pln(2000),
+			shares: []store.ExpenseShare{share(1, 2000, false), share(2, 2000, true)},
+			want:   map[uint]money.Money{1: pln(0)},
+		},
+		{
+			name:    "amount below paid shares",
+			mode:    store.SplitEqual,
+			amount:  pln(1000),
+			shares:  []store.ExpenseShare{share(1, 2000, false), share(2, 2000, true)},
+			wantErr: true,
+		},
+		{
+			name:    "every share paid",
+			mode:    store.SplitEqual,
+			amount:  pln(5000),
+			shares:  []store.ExpenseShare{share(1, 2000, true), share(2, 2000, true)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resplitExpense(tc.mode, tc.amount, tc.shares, tc.values, 1)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
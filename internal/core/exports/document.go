@@ -0,0 +1,419 @@
+package exports
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/spreadsheet"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// documentVersion is bumped whenever a field of the export is renamed, removed
+// or changes meaning, so that whoever reads an export can tell the layouts
+// apart. Adding a field does not change the version.
+const documentVersion = 1
+
+const (
+	scopeHousehold = "household"
+	scopeUser      = "user"
+)
+
+// document is everything exported for one household or one user. Amounts
+// are decimal strings in major units next to their currency, so that no
+// precision is lost to floats.
+type document struct {
+	Version    int        `json:"version"`
+	ExportedOn time.Time  `json:"exported_on"`
+	Scope      string     `json:"scope"`
+	User       *user      `json:"user,omitempty"`
+	Household  *household `json:"household,omitempty"`
+	Members    []member   `json:"members"`
+	Expenses   []expense  `json:"expenses"`
+	Shares     []share    `json:"shares"`
+	Payments   []payment  `json:"payments"`
+}
+
+type user struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type household struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	BaseCurrency string `json:"base_currency"`
+}
+
+type member struct {
+	HouseholdID uint   `json:"household_id"`
+	Household   string `json:"household"`
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+}
+
+type expense struct {
+	ID               uint      `json:"id"`
+	HouseholdID      uint      `json:"household_id"`
+	Name             string    `json:"name"`
+	Amount           string    `json:"amount"`
+	Currency         string    `json:"currency"`
+	OriginalAmount   string    `json:"original_amount"`
+	OriginalCurrency string    `json:"original_currency"`
+	Category         string    `json:"category"`
+	SplitMode        string    `json:"split_mode"`
+	CreatedOn        time.Time `json:"created_on"`
+	CreatedBy        string    `json:"created_by"`
+	Recurring        bool      `json:"recurring"`
+	Payers           []payer   `json:"payers"`
+}
+
+type payer struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Amount   string `json:"amount"`
+}
+
+type share struct {
+	ID        uint   `json:"id"`
+	ExpenseID uint   `json:"expense_id"`
+	Expense   string `json:"expense"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Amount    string `json:"amount"`
+	Covered   string `json:"covered"`
+	Currency  string `json:"currency"`
+	Paid      bool   `json:"paid"`
+}
+
+type payment struct {
+	ID        uint      `json:"id"`
+	ShareID   uint      `json:"share_id"`
+	ExpenseID uint      `json:"expense_id"`
+	Expense   string    `json:"expense"`
+	PayerID   uint      `json:"payer_id"`
+	Payer     string    `json:"payer"`
+	PayeeID   uint      `json:"payee_id"`
+	Payee     string    `json:"payee"`
+	Amount    string    `json:"amount"`
+	Currency  string    `json:"currency"`
+	PaidOn    time.Time `json:"paid_on"`
+	Method    string    `json:"method"`
+	Note      string    `json:"note"`
+}
+
+// usernames resolves user IDs to names from whatever users the stores
+// preloaded. Former members are still known through their shares and
+// payments.
+type usernames map[uint]string
+
+func (u usernames) add(users ...store.User) {
+	for _, user := range users {
+		if user.ID != 0 && user.Username != "" {
+			u[user.ID] = user.Username
+		}
+	}
+}
+
+func (u usernames) addExpense(e store.Expense) {
+	u.add(e.CreatedBy)
+	for _, p := range e.Payers {
+		u.add(p.User)
+	}
+}
+
+// householdDocument exports a household with its members, expenses, every
+// share of those expenses and every payment made towards them.
+func householdDocument(h store.Household, members []store.Membership, expenses []store.Expense, shares []store.ExpenseShare, now time.Time) document {
+	names := usernames{}
+	for _, m := range members {
+		names.add(m.User)
+	}
+	for _, e := range expenses {
+		names.addExpense(e)
+	}
+	for _, s := range shares {
+		names.add(s.User)
+	}
+
+	d := document{
+		Version:    documentVersion,
+		ExportedOn: now,
+		Scope:      scopeHousehold,
+		Household: &household{
+			ID:           h.ID,
+			Name:         h.Name,
+			Description:  h.Description,
+			BaseCurrency: h.BaseCurrency,
+		},
+		Members:  []member{},
+		Expenses: []expense{},
+		Shares:   []share{},
+		Payments: []payment{},
+	}
+
+	for _, m := range members {
+		d.Members = append(d.Members, member{
+			HouseholdID: h.ID,
+			Household:   h.Name,
+			UserID:      m.UserID,
+			Username:    names[m.UserID],
+			Role:        m.Role,
+		})
+	}
+
+	for _, e := range expenses {
+		d.Expenses = append(d.Expenses, exportExpense(e, names))
+	}
+
+	for _, s := range shares {
+		d.Shares = append(d.Shares, exportShare(s, names))
+		for _, p := range s.Payments {
+			p.ExpenseShare = s
+			d.Payments = append(d.Payments, exportPayment(p, names))
+		}
+	}
+
+	return d
+}
+
+// userDocument exports the account of u: its memberships, every share it
+// owes with the expense the share is of, and every payment it made or
+// received. households are the households of u with their memberships.
+func userDocument(u store.User, households []store.Household, shares []store.ExpenseShare, payments []store.Payment, now time.Time) document {
+	names := usernames{}
+	names.add(u)
+	for _, h := range households {
+		for _, m := range h.Memberships {
+			names.add(m.User)
+		}
+	}
+	for _, s := range shares {
+		names.addExpense(s.Expense)
+	}
+	for _, p := range payments {
+		names.add(p.Payer, p.Payee)
+	}
+
+	d := document{
+		Version:    documentVersion,
+		ExportedOn: now,
+		Scope:      scopeUser,
+		User: &user{
+			ID:       u.ID,
+			Username: u.Username,
+			Email:    u.Email,
+		},
+		Members:  []member{},
+		Expenses: []expense{},
+		Shares:   []share{},
+		Payments: []payment{},
+	}
+
+	for _, h := range households {
+		for _, m := range h.Memberships {
+			if m.UserID == u.ID {
+				d.Members = append(d.Members, member{
+					HouseholdID: h.ID,
+					Household:   h.Name,
+					UserID:      u.ID,
+					Username:    u.Username,
+					Role:        m.Role,
+				})
+			}
+		}
+	}
+
+	seen := map[uint]bool{}
+	for _, s := range shares {
+		if !seen[s.ExpenseID] {
+			seen[s.ExpenseID] = true
+			d.Expenses = append(d.Expenses, exportExpense(s.Expense, names))
+		}
+		d.Shares = append(d.Shares, exportShare(s, names))
+	}
+
+	for _, p := range payments {
+		d.Payments = append(d.Payments, exportPayment(p, names))
+	}
+
+	return d
+}
+
+func exportExpense(e store.Expense, names usernames) expense {
+	exported := expense{
+		ID:               e.ID,
+		HouseholdID:      e.HouseholdID,
+		Name:             e.Name,
+		Amount:           e.Amount.Amount(),
+		Currency:         e.Amount.Currency,
+		OriginalAmount:   e.OriginalAmount.Amount(),
+		OriginalCurrency: e.OriginalAmount.Currency,
+		Category:         e.Category.FullName(),
+		SplitMode:        string(e.SplitMode),
+		CreatedOn:        e.CreatedOn,
+		CreatedBy:        names[e.CreatedByID],
+		Recurring:        e.RecurringExpenseID != nil,
+		Payers:           []payer{},
+	}
+
+	for _, p := range e.Payers {
+		exported.Payers = append(exported.Payers, payer{
+			UserID:   p.UserID,
+			Username: names[p.UserID],
+			Amount:   p.Amount.Amount(),
+		})
+	}
+
+	return exported
+}
+
+func exportShare(s store.ExpenseShare, names usernames) share {
+	return share{
+		ID:        s.ID,
+		ExpenseID: s.ExpenseID,
+		Expense:   s.Expense.Name,
+		UserID:    s.UserID,
+		Username:  names[s.UserID],
+		Amount:    s.Amount.Amount(),
+		Covered:   s.Covered.Amount(),
+		Currency:  s.Amount.Currency,
+		Paid:      s.Paid,
+	}
+}
+
+// exportPayment needs the share of p with its expense loaded.
+func exportPayment(p store.Payment, names usernames) payment {
+	return payment{
+		ID:        p.ID,
+		ShareID:   p.ExpenseShareID,
+		ExpenseID: p.ExpenseShare.ExpenseID,
+		Expense:   p.ExpenseShare.Expense.Name,
+		PayerID:   p.PayerID,
+		Payer:     names[p.PayerID],
+		PayeeID:   p.PayeeID,
+		Payee:     names[p.PayeeID],
+		Amount:    p.Amount.Amount(),
+		Currency:  p.Amount.Currency,
+		PaidOn:    p.PaidOn,
+		Method:    string(p.Method),
+		Note:      p.Note,
+	}
+}
+
+// writeJSON writes d as one indented JSON document.
+func writeJSON(w io.Writer, d document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// writeCSVArchive writes d as a ZIP archive with one CSV file per kind of
+// record. The CSV files carry the same fields as the JSON document, except
+// that the payers of an expense are joined into one column, and text that a
+// spreadsheet would take for a formula is quoted.
+func writeCSVArchive(w io.Writer, d document) error {
+	zw := zip.NewWriter(w)
+
+	for _, t := range d.tables() {
+		f, err := zw.Create(t.name)
+		if err != nil {
+			return err
+		}
+
+		cw := csv.NewWriter(f)
+		if err := cw.Write(t.header); err != nil {
+			return err
+		}
+		for _, row := range t.rows {
+			if err := cw.Write(spreadsheet.Row(row)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+type table struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+const csvTimeLayout = "2006-01-02 15:04:05"
+
+func (d document) tables() []table {
+	members := table{
+		name:   "members.csv",
+		header: []string{"household_id", "household", "user_id", "username", "role"},
+	}
+	for _, m := range d.Members {
+		members.rows = append(members.rows, []string{
+			id(m.HouseholdID), m.Household, id(m.UserID), m.Username, m.Role,
+		})
+	}
+
+	expenses := table{
+		name: "expenses.csv",
+		header: []string{
+			"id", "household_id", "name", "amount", "currency", "original_amount", "original_currency",
+			"category", "split_mode", "created_on", "created_by", "recurring", "paid_by",
+		},
+	}
+	for _, e := range d.Expenses {
+		paidBy := make([]string, len(e.Payers))
+		for i, p := range e.Payers {
+			paidBy[i] = fmt.Sprintf("%s %s", p.Username, p.Amount)
+		}
+
+		expenses.rows = append(expenses.rows, []string{
+			id(e.ID), id(e.HouseholdID), e.Name, e.Amount, e.Currency, e.OriginalAmount, e.OriginalCurrency,
+			e.Category, e.SplitMode, e.CreatedOn.Format(csvTimeLayout), e.CreatedBy,
+			strconv.FormatBool(e.Recurring), strings.Join(paidBy, "; "),
+		})
+	}
+
+	shares := table{
+		name:   "shares.csv",
+		header: []string{"id", "expense_id", "expense", "user_id", "username", "amount", "covered", "currency", "paid"},
+	}
+	for _, s := range d.Shares {
+		shares.rows = append(shares.rows, []string{
+			id(s.ID), id(s.ExpenseID), s.Expense, id(s.UserID), s.Username,
+			s.Amount, s.Covered, s.Currency, strconv.FormatBool(s.Paid),
+		})
+	}
+
+	payments := table{
+		name: "payments.csv",
+		header: []string{
+			"id", "share_id", "expense_id", "expense", "payer_id", "payer", "payee_id", "payee",
+			"amount", "currency", "paid_on", "method", "note",
+		},
+	}
+	for _, p := range d.Payments {
+		payments.rows = append(payments.rows, []string{
+			id(p.ID), id(p.ShareID), id(p.ExpenseID), p.Expense, id(p.PayerID), p.Payer, id(p.PayeeID), p.Payee,
+			p.Amount, p.Currency, p.PaidOn.Format(csvTimeLayout), p.Method, p.Note,
+		})
+	}
+
+	return []table{members, expenses, shares, payments}
+}
+
+func id(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
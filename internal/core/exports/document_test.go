@@ -0,0 +1,171 @@
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	anna  = store.User{ID: 1, Username: "anna", Email: "anna@example.com", Password: "hash"}
+	piotr = store.User{ID: 2, Username: "piotr", Email: "piotr@example.com", Password: "hash"}
+	now   = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+)
+
+func testExpense() store.Expense {
+	food := store.Category{ID: 1, Name: "Food"}
+	return store.Expense{
+		ID:             7,
+		HouseholdID:    3,
+		Name:           "Groceries",
+		Amount:         money.New(2501, "PLN"),
+		OriginalAmount: money.New(2501, "PLN"),
+		Category:       store.Category{ID: 2, Name: "Market", ParentID: &food.ID, Parent: &food},
+		SplitMode:      store.SplitEqual,
+		CreatedOn:      time.Date(2026, time.October, 1, 9, 30, 0, 0, time.UTC),
+		CreatedByID:    anna.ID,
+		CreatedBy:      anna,
+		Payers:         []store.ExpensePayer{{UserID: piotr.ID, User: piotr, Amount: money.New(2501, "PLN")}},
+	}
+}
+
+func testShares(e store.Expense) []store.ExpenseShare {
+	return []store.ExpenseShare{
+		{
+			ID: 11, ExpenseID: e.ID, Expense: e, UserID: anna.ID, User: anna,
+			Amount: money.New(1250, "PLN"), Covered: money.New(0, "PLN"), Paid: true,
+			Payments: []store.Payment{{
+				ID: 21, ExpenseShareID: 11, PayerID: anna.ID, PayeeID: piotr.ID,
+				Amount: money.New(1250, "PLN"), PaidOn: now, Method: store.PaymentMethod("cash"),
+			}},
+		},
+		{
+			ID: 12, ExpenseID: e.ID, Expense: e, UserID: piotr.ID, User: piotr,
+			Amount: money.New(1251, "PLN"), Covered: money.New(1251, "PLN"), Paid: true,
+		},
+	}
+}
+
+func TestHouseholdDocument(t *testing.T) {
+	e := testExpense()
+	members := []store.Membership{
+		{UserID: anna.ID, User: anna, HouseholdID: 3, Role: store.RoleOwner},
+	}
+
+	d := householdDocument(store.Household{ID: 3, Name: "Home", BaseCurrency: "PLN"}, members, []store.Expense{e}, testShares(e), now)
+
+	require.Equal(t, documentVersion, d.Version)
+	require.Equal(t, scopeHousehold, d.Scope)
+	require.Nil(t, d.User)
+	require.Equal(t, []member{{HouseholdID: 3, Household: "Home", UserID: 1, Username: "anna", Role: store.RoleOwner}}, d.Members)
+
+	require.Len(t, d.Expenses, 1)
+	require.Equal(t, "25.01", d.Expenses[0].Amount)
+	require.Equal(t, "Food / Market", d.Expenses[0].Category)
+	require.Equal(t, "anna", d.Expenses[0].CreatedBy)
+	require.Equal(t, []payer{{UserID: 2, Username: "piotr", Amount: "25.01"}}, d.Expenses[0].Payers)
+
+	require.Len(t, d.Shares, 2)
+	require.Equal(t, "piotr", d.Shares[1].Username)
+
+	require.Equal(t, []payment{{
+		ID: 21, ShareID: 11, ExpenseID: 7, Expense: "Groceries",
+		PayerID: 1, Payer: "anna", PayeeID: 2, Payee: "piotr",
+		Amount: "12.50", Currency: "PLN", PaidOn: now, Method: "cash",
+	}}, d.Payments)
+}
+
+func TestUserDocument(t *testing.T) {
+	e := testExpense()
+	shares := testShares(e)[:1]
+	households := []store.Household{{
+		ID:   3,
+		Name: "Home",
+		Memberships: []store.Membership{
+			{UserID: anna.ID, User: anna, Role: store.RoleMember},
+			{UserID: piotr.ID, User: piotr, Role: store.RoleOwner},
+		},
+	}}
+	payments := []store.Payment{shares[0].Payments[0]}
+	payments[0].ExpenseShare = shares[0]
+
+	d := userDocument(anna, households, shares, payments, now)
+
+	require.Equal(t, scopeUser, d.Scope)
+	require.Equal(t, &user{ID: 1, Username: "anna", Email: "anna@example.com"}, d.User)
+	require.Equal(t, []member{{HouseholdID: 3, Household: "Home", UserID: 1, Username: "anna", Role: store.RoleMember}}, d.Members)
+	require.Len(t, d.Expenses, 1)
+	require.Len(t, d.Shares, 1)
+	require.Len(t, d.Payments, 1)
+	require.Equal(t, "piotr", d.Payments[0].Payee)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, d))
+	require.NotContains(t, buf.String(), "hash")
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, float64(documentVersion), decoded["version"])
+	require.NotContains(t, decoded, "household")
+}
+
+func TestWriteCSVArchive(t *testing.T) {
+	e := testExpense()
+	d := householdDocument(store.Household{ID: 3, Name: "Home"}, nil, []store.Expense{e}, testShares(e), now)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSVArchive(&buf, d))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string][][]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		require.NoError(t, err)
+		files[f.Name] = records
+	}
+
+	require.Len(t, files, 4)
+	require.Len(t, files["members.csv"], 1)
+	require.Equal(t, []string{
+		"7", "3", "Groceries", "25.01", "PLN", "25.01", "PLN",
+		"Food / Market", string(store.SplitEqual), "2026-10-01 09:30:00", "anna", "false", "piotr 25.01",
+	}, files["expenses.csv"][1])
+	require.Len(t, files["shares.csv"], 3)
+	require.Equal(t, "12.50", files["payments.csv"][1][8])
+}
+
+func TestWriteCSVArchive_Formula(t *testing.T) {
+	e := testExpense()
+	e.Name = `=HYPERLINK("http://example.com","Groceries")`
+	d := householdDocument(store.Household{ID: 3, Name: "Home"}, nil, []store.Expense{e}, nil, now)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSVArchive(&buf, d))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	rc, err := zr.Open("expenses.csv")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	records, err := csv.NewReader(rc).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, `'=HYPERLINK("http://example.com","Groceries")`, records[1][2])
+}
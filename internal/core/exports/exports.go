@@ -0,0 +1,164 @@
+package exports
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+type GetExportHandler struct {
+	householdStore    store.HouseholdStore
+	membershipStore   store.MembershipStore
+	expenseStore      store.ExpenseStore
+	expenseShareStore store.ExpenseShareStore
+	paymentStore      store.PaymentStore
+}
+
+type GetExportHandlerParams struct {
+	HouseholdStore    store.HouseholdStore
+	MembershipStore   store.MembershipStore
+	ExpenseStore      store.ExpenseStore
+	ExpenseShareStore store.ExpenseShareStore
+	PaymentStore      store.PaymentStore
+}
+
+func NewGetExportHandler(params GetExportHandlerParams) *GetExportHandler {
+	return &GetExportHandler{
+		householdStore:    params.HouseholdStore,
+		membershipStore:   params.MembershipStore,
+		expenseStore:      params.ExpenseStore,
+		expenseShareStore: params.ExpenseShareStore,
+		paymentStore:      params.PaymentStore,
+	}
+}
+
+// ExportHousehold downloads the members, expenses, shares and payments of
+// the household as JSON or, with format=csv, as a ZIP of CSV files. Every
+// member may export, viewers included.
+func (h *GetExportHandler) ExportHousehold(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	format, ok := exportFormat(r)
+	if !ok {
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.householdStore.GetHouseholdByID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "Household not found", http.StatusNotFound)
+		return
+	}
+
+	members, err := h.membershipStore.GetMembersByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch members", http.StatusInternalServerError)
+		return
+	}
+
+	expenses, err := h.expenseStore.GetExpensesByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch expenses", http.StatusInternalServerError)
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetSharesByHouseholdID(household.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch shares", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	d := householdDocument(household, members, expenses, shares, now)
+	writeExport(w, d, format, fmt.Sprintf("household-%d-%s", household.ID, now.Format("2006-01-02")))
+}
+
+// ExportUser downloads everything stored about the logged-in user across
+// all of their households: memberships, the expenses they have a share in,
+// those shares and the payments they made or received.
+func (h *GetExportHandler) ExportUser(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	format, ok := exportFormat(r)
+	if !ok {
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	households, err := h.householdStore.GetHouseholdsByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch households", http.StatusInternalServerError)
+		return
+	}
+
+	shares, err := h.expenseShareStore.GetExpensesByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "cannot fetch shares", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+
+	payments, err := h.paymentStore.GetPaymentsByUserID(user.ID, time.Time{}, now)
+	if err != nil {
+		http.Error(w, "cannot fetch payments", http.StatusInternalServerError)
+		return
+	}
+
+	d := userDocument(*user, households, shares, payments, now)
+	writeExport(w, d, format, fmt.Sprintf("user-%d-%s", user.ID, now.Format("2006-01-02")))
+}
+
+func exportFormat(r *http.Request) (string, bool) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", formatJSON:
+		return formatJSON, true
+	case formatCSV:
+		return formatCSV, true
+	default:
+		return "", false
+	}
+}
+
+// writeExport sends d as an attachment named name with the extension of
+// format. The export is built in memory first so that a failure can still be
+// reported with an error status.
+func writeExport(w http.ResponseWriter, d document, format, name string) {
+	write, contentType, ext := writeJSON, "application/json", ".json"
+	if format == formatCSV {
+		write, contentType, ext = writeCSVArchive, "application/zip", ".zip"
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, d); err != nil {
+		http.Error(w, "cannot write export", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s%s"`, name, ext))
+	w.Write(buf.Bytes())
+}
@@ -2,13 +2,13 @@ package households
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 
 	templBasic "github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
 	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
@@ -47,10 +47,12 @@ func (h *GetHouseholdsHandler) GetHouseholds(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ownedHouseholds := make([]store.Household, 0, len(households))
+	expenseHouseholds := make([]store.Household, 0, len(households))
 	for _, h := range households {
-		if h.CreatedByID == user.ID {
-			ownedHouseholds = append(ownedHouseholds, h)
+		for _, m := range h.Memberships {
+			if m.UserID == user.ID && m.Can(store.PermAddExpense) {
+				expenseHouseholds = append(expenseHouseholds, h)
+			}
 		}
 	}
 
@@ -67,7 +69,7 @@ func (h *GetHouseholdsHandler) GetHouseholds(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	c := templ.Households(isHX, households, ownedHouseholds, filteredUsers)
+	c := templ.Households(isHX, households, expenseHouseholds, filteredUsers)
 
 	var out templBasic.Component
 	if isHX {
@@ -87,12 +89,14 @@ func (h *GetHouseholdsHandler) GetHouseholds(w http.ResponseWriter, r *http.Requ
 type PostHouseholdHandler struct {
 	householdStore  store.HouseholdStore
 	membershipStore store.MembershipStore
+	invitationStore store.InvitationStore
 	userStore       store.UserStore
 }
 
 type PostHouseholdHandlerParams struct {
 	HouseholdStore  store.HouseholdStore
 	MembershipStore store.MembershipStore
+	InvitationStore store.InvitationStore
 	UserStore       store.UserStore
 }
 
@@ -100,6 +104,7 @@ func NewPostHouseholdHandler(params PostHouseholdHandlerParams) *PostHouseholdHa
 	return &PostHouseholdHandler{
 		householdStore:  params.HouseholdStore,
 		membershipStore: params.MembershipStore,
+		invitationStore: params.InvitationStore,
 		userStore:       params.UserStore,
 	}
 }
@@ -123,8 +128,13 @@ func (h *PostHouseholdHandler) PostHousehold(w http.ResponseWriter, r *http.Requ
 
 	name := r.FormValue("name")
 	description := r.FormValue("description")
+	baseCurrency := r.FormValue("base_currency")
 	memberUsernames := r.Form["members[]"]
 
+	if baseCurrency == "" {
+		baseCurrency = money.DefaultCurrency
+	}
+
 	if len(name) == 0 {
 		w.WriteHeader(http.StatusBadRequest)
 		c := templAlerts.Error("Create failed", "Household name is required")
@@ -152,6 +162,25 @@ func (h *PostHouseholdHandler) PostHousehold(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if !money.IsSupported(baseCurrency) {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Create failed", fmt.Sprintf("Currency %s is not supported", baseCurrency))
+		c.Render(r.Context(), w)
+		return
+	}
+
+	invitees := make([]store.User, 0, len(memberUsernames))
+	for _, username := range memberUsernames {
+		invitee, err := h.userStore.GetUserByUsername(username)
+		if err != nil || invitee == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			c := templAlerts.Error("Create failed", fmt.Sprintf("User %s not found", username))
+			c.Render(r.Context(), w)
+			return
+		}
+		invitees = append(invitees, *invitee)
+	}
+
 	nameBusy, err := h.householdStore.NameExists(name)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -165,14 +194,14 @@ func (h *PostHouseholdHandler) PostHousehold(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	householdID, err := h.createHouseholdWithMembership(name, description, user.ID, "owner")
+	householdID, err := h.createHouseholdWithMembership(name, description, baseCurrency, user.ID, store.RoleOwner)
 	if err != nil {
 		http.Error(w, "could not create household", http.StatusInternalServerError)
 		return
 	}
 
-	if err := h.addMembers(memberUsernames, householdID); err != nil {
-		http.Error(w, "could not add members", http.StatusInternalServerError)
+	if err := h.inviteMembers(invitees, householdID, user.ID); err != nil {
+		http.Error(w, "could not invite members", http.StatusInternalServerError)
 		return
 	}
 
@@ -180,9 +209,9 @@ func (h *PostHouseholdHandler) PostHousehold(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *PostHouseholdHandler) createHouseholdWithMembership(householdName string, description string, userID uint, role string) (uint, error) {
+func (h *PostHouseholdHandler) createHouseholdWithMembership(householdName string, description string, baseCurrency string, userID uint, role string) (uint, error) {
 
-	householdID, err := h.householdStore.CreateHousehold(householdName, description, userID)
+	householdID, err := h.householdStore.CreateHousehold(householdName, description, baseCurrency, userID)
 	if err != nil {
 		return 0, err
 	}
@@ -194,16 +223,12 @@ func (h *PostHouseholdHandler) createHouseholdWithMembership(householdName strin
 	return householdID, nil
 }
 
-func (h *PostHouseholdHandler) addMembers(usernames []string, householdID uint) error {
-	for _, username := range usernames {
-		user, err := h.userStore.GetUserByUsername(username)
-		if err != nil {
-			continue
-		}
-
-		if err := h.membershipStore.CreateMembership(user.ID, householdID, "member"); err != nil {
-			log.Printf("failed to add member %s: %v", username, err)
-			continue
+// inviteMembers sends an invitation to every user picked when the household
+// was created. They only become members once they accept it.
+func (h *PostHouseholdHandler) inviteMembers(users []store.User, householdID uint, invitedByID uint) error {
+	for _, u := range users {
+		if _, err := h.invitationStore.CreateInvitation(householdID, invitedByID, u.Email, invitationExpiry()); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -211,15 +236,18 @@ func (h *PostHouseholdHandler) addMembers(usernames []string, householdID uint)
 
 type GetHouseholdMembersHandler struct {
 	membershipStore store.MembershipStore
+	invitationStore store.InvitationStore
 }
 
 type GetHouseholdMembersHandlerParams struct {
 	MembershipStore store.MembershipStore
+	InvitationStore store.InvitationStore
 }
 
 func NewGetHouseholdMembersHandler(params GetHouseholdMembersHandlerParams) *GetHouseholdMembersHandler {
 	return &GetHouseholdMembersHandler{
 		membershipStore: params.MembershipStore,
+		invitationStore: params.InvitationStore,
 	}
 }
 
@@ -244,7 +272,15 @@ func (h *GetHouseholdMembersHandler) GetHouseholdMembers(w http.ResponseWriter,
 		return
 	}
 
-	err = templ.HouseholdMembers(members).Render(r.Context(), w)
+	invitations, err := h.invitationStore.GetOpenInvitationsByHouseholdID(uint(householdID))
+	if err != nil {
+		http.Error(w, "cannot fetch invitations", http.StatusInternalServerError)
+		return
+	}
+
+	current := middleware.GetMembership(r.Context())
+
+	err = templ.HouseholdMembers(members, invitations, current, assignableRolesFor(current.Role)).Render(r.Context(), w)
 
 	if err != nil {
 		http.Error(w, "Error rendering template", http.StatusInternalServerError)
@@ -291,7 +327,13 @@ func (h *GetHouseholdExpensesHandler) GetHouseholdExpenses(w http.ResponseWriter
 		expenses = []store.Expense{}
 	}
 
-	err = templ.HouseholdExpenses(expenses).Render(r.Context(), w)
+	audits, err := h.expenseStore.GetExpenseAuditsByHouseholdID(uint(householdID))
+	if err != nil {
+		http.Error(w, "cannot fetch expense history", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.HouseholdExpenses(expenses, audits, middleware.GetMembership(r.Context())).Render(r.Context(), w)
 
 	if err != nil {
 		http.Error(w, "Error rendering template", http.StatusInternalServerError)
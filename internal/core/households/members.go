@@ -0,0 +1,333 @@
+package households
+
+import (
+	"net/http"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+// PostMembershipHandler manages the members of a household. All of its routes
+// are guarded by middleware.RequireMember, which also puts the membership of
+// the acting user in the request context.
+type PostMembershipHandler struct {
+	membershipStore   store.MembershipStore
+	invitationStore   store.InvitationStore
+	userStore         store.UserStore
+	expenseShareStore store.ExpenseShareStore
+}
+
+type PostMembershipHandlerParams struct {
+	MembershipStore   store.MembershipStore
+	InvitationStore   store.InvitationStore
+	UserStore         store.UserStore
+	ExpenseShareStore store.ExpenseShareStore
+}
+
+func NewPostMembershipHandler(params PostMembershipHandlerParams) *PostMembershipHandler {
+	return &PostMembershipHandler{
+		membershipStore:   params.MembershipStore,
+		invitationStore:   params.InvitationStore,
+		userStore:         params.UserStore,
+		expenseShareStore: params.ExpenseShareStore,
+	}
+}
+
+// invitationTTL is how long an invitation can be answered.
+const invitationTTL = 7 * 24 * time.Hour
+
+func invitationExpiry() time.Time {
+	return time.Now().Add(invitationTTL)
+}
+
+// assignableRoles are the roles that can be given to another member.
+// Ownership itself changes hands only through PostTransferOwnership.
+var assignableRoles = []string{store.RoleAdmin, store.RoleMember, store.RoleViewer}
+
+// assignableRolesFor returns the roles a member with role may hand out: only
+// those below their own, so an admin cannot create other admins.
+func assignableRolesFor(role string) []string {
+	roles := make([]string, 0, len(assignableRoles))
+	for _, r := range assignableRoles {
+		if store.Outranks(role, r) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// PostInvite invites someone to the household by username or by email. An
+// email address does not have to be registered yet. Either way the invitee
+// joins only through the invitation link, which managers of the household
+// share with them: someone without an account registers from it and joins
+// right away, anyone else accepts it once logged in.
+func (h *PostMembershipHandler) PostInvite(w http.ResponseWriter, r *http.Request) {
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	email, ok := h.inviteeEmail(w, r)
+	if !ok {
+		return
+	}
+
+	if user, err := h.userStore.GetUser(email); err == nil && user != nil {
+		if _, err := h.membershipStore.GetMembership(membership.HouseholdID, user.ID); err == nil {
+			w.WriteHeader(http.StatusConflict)
+			c := templAlerts.Error("Invite failed", "User is already a member")
+			c.Render(r.Context(), w)
+			return
+		}
+	}
+
+	invitations, err := h.invitationStore.GetOpenInvitationsByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch invitations", http.StatusInternalServerError)
+		return
+	}
+
+	for _, inv := range invitations {
+		if strings.EqualFold(inv.Email, email) {
+			w.WriteHeader(http.StatusConflict)
+			c := templAlerts.Error("Invite failed", "This address has already been invited")
+			c.Render(r.Context(), w)
+			return
+		}
+	}
+
+	if _, err := h.invitationStore.CreateInvitation(membership.HouseholdID, membership.UserID, email, invitationExpiry()); err != nil {
+		http.Error(w, "cannot create invitation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// inviteeEmail resolves the invitee form field into the address the
+// invitation is sent to: an email address is taken as is, anything else must
+// be the username of a registered user. It writes the error response itself
+// and returns false when the field cannot be resolved.
+func (h *PostMembershipHandler) inviteeEmail(w http.ResponseWriter, r *http.Request) (string, bool) {
+	invitee := strings.TrimSpace(r.FormValue("invitee"))
+
+	inviteError := func(description string) (string, bool) {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Invite failed", description)
+		c.Render(r.Context(), w)
+		return "", false
+	}
+
+	if invitee == "" {
+		return inviteError("Username or email is required")
+	}
+
+	if strings.Contains(invitee, "@") {
+		address, err := mail.ParseAddress(invitee)
+		if err != nil {
+			return inviteError("Invalid email address")
+		}
+		return address.Address, true
+	}
+
+	user, err := h.userStore.GetUserByUsername(invitee)
+	if err != nil || user == nil {
+		return inviteError("User not found")
+	}
+
+	return user.Email, true
+}
+
+func (h *PostMembershipHandler) PostRemoveMember(w http.ResponseWriter, r *http.Request) {
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	target, ok := h.targetMember(w, r, membership)
+	if !ok {
+		return
+	}
+
+	if !store.Outranks(membership.Role, target.Role) {
+		w.WriteHeader(http.StatusForbidden)
+		c := templAlerts.Error("Remove failed", "You can only remove members with a lower role than yours")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if !h.checkSettled(w, r, target, "Remove failed") {
+		return
+	}
+
+	if err := h.membershipStore.DeleteMembership(target.HouseholdID, target.UserID); err != nil {
+		http.Error(w, "cannot remove member", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *PostMembershipHandler) PostChangeRole(w http.ResponseWriter, r *http.Request) {
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	target, ok := h.targetMember(w, r, membership)
+	if !ok {
+		return
+	}
+
+	role := r.FormValue("role")
+	if !slices.Contains(assignableRoles, role) {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Change role failed", "Invalid role")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if target.Role == store.RoleOwner {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Change role failed", "Transfer ownership to change the owner's role")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if !store.Outranks(membership.Role, target.Role) || !slices.Contains(assignableRolesFor(membership.Role), role) {
+		w.WriteHeader(http.StatusForbidden)
+		c := templAlerts.Error("Change role failed", "You can only assign roles below your own to members with a lower role")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if err := h.membershipStore.UpdateMembershipRole(target.HouseholdID, target.UserID, role); err != nil {
+		http.Error(w, "cannot change role", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *PostMembershipHandler) PostTransferOwnership(w http.ResponseWriter, r *http.Request) {
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	target, ok := h.targetMember(w, r, membership)
+	if !ok {
+		return
+	}
+
+	if err := h.membershipStore.TransferOwnership(membership.HouseholdID, membership.UserID, target.UserID); err != nil {
+		http.Error(w, "cannot transfer ownership", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostLeave removes the acting user from the household. The owner has to hand
+// the household over first so that it is never left without one.
+func (h *PostMembershipHandler) PostLeave(w http.ResponseWriter, r *http.Request) {
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if membership.Role == store.RoleOwner {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Leave failed", "Transfer ownership before leaving the household")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	if !h.checkSettled(w, r, *membership, "Leave failed") {
+		return
+	}
+
+	if err := h.membershipStore.DeleteMembership(membership.HouseholdID, membership.UserID); err != nil {
+		http.Error(w, "cannot leave household", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// targetMember loads the membership named by the userID route parameter. It
+// writes the error response itself and returns false when the user is not a
+// member of the same household or is the acting user.
+func (h *PostMembershipHandler) targetMember(w http.ResponseWriter, r *http.Request, acting *store.Membership) (store.Membership, bool) {
+	userID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return store.Membership{}, false
+	}
+
+	if uint(userID) == acting.UserID {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Update failed", "You cannot change your own membership here")
+		c.Render(r.Context(), w)
+		return store.Membership{}, false
+	}
+
+	target, err := h.membershipStore.GetMembership(acting.HouseholdID, uint(userID))
+	if err != nil {
+		http.Error(w, "member not found", http.StatusNotFound)
+		return store.Membership{}, false
+	}
+
+	return target, true
+}
+
+// checkSettled refuses to let a member leave or be removed while they still
+// owe or are owed an unpaid share in the household. Debts are kept with the
+// people who incurred them: the household settles up first, and only then can
+// the membership end. It writes the error response and returns false when the
+// member is not settled.
+func (h *PostMembershipHandler) checkSettled(w http.ResponseWriter, r *http.Request, member store.Membership, title string) bool {
+	shares, err := h.expenseShareStore.GetSharesByHouseholdID(member.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch expense shares", http.StatusInternalServerError)
+		return false
+	}
+
+	if hasOpenShares(shares, member.UserID) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error(title, "Open balances must be settled up first")
+		c.Render(r.Context(), w)
+		return false
+	}
+
+	return true
+}
+
+func hasOpenShares(shares []store.ExpenseShare, userID uint) bool {
+	for _, s := range shares {
+		if s.Outstanding().IsZero() {
+			continue
+		}
+		if s.UserID == userID || s.Expense.IsPayer(userID) {
+			return true
+		}
+	}
+	return false
+}
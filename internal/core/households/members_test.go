@@ -0,0 +1,65 @@
+package households
+
+import (
+	"testing"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasOpenShares(t *testing.T) {
+	share := func(userID, payerID uint, minor, paidMinor int64) store.ExpenseShare {
+		var covered money.Money
+		if userID == payerID {
+			covered = money.New(minor, "PLN")
+		}
+
+		return store.ExpenseShare{
+			UserID:   userID,
+			Expense:  store.Expense{Payers: []store.ExpensePayer{{UserID: payerID}}},
+			Amount:   money.New(minor, "PLN"),
+			Covered:  covered,
+			Payments: []store.Payment{{Amount: money.New(paidMinor, "PLN")}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		shares []store.ExpenseShare
+		userID uint
+		want   bool
+	}{
+		{name: "no shares", userID: 2, want: false},
+		{name: "owes money", shares: []store.ExpenseShare{share(2, 1, 1000, 0)}, userID: 2, want: true},
+		{name: "is owed money", shares: []store.ExpenseShare{share(2, 1, 1000, 0)}, userID: 1, want: true},
+		{name: "partly paid", shares: []store.ExpenseShare{share(2, 1, 1000, 400)}, userID: 2, want: true},
+		{name: "fully paid", shares: []store.ExpenseShare{share(2, 1, 1000, 1000)}, userID: 2, want: false},
+		{name: "own share only", shares: []store.ExpenseShare{share(2, 2, 1000, 0)}, userID: 2, want: false},
+		{name: "other members' debts", shares: []store.ExpenseShare{share(3, 1, 1000, 0)}, userID: 2, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, hasOpenShares(tc.shares, tc.userID))
+		})
+	}
+}
+
+func TestAssignableRolesFor(t *testing.T) {
+	testCases := []struct {
+		role string
+		want []string
+	}{
+		{role: store.RoleOwner, want: []string{store.RoleAdmin, store.RoleMember, store.RoleViewer}},
+		{role: store.RoleAdmin, want: []string{store.RoleMember, store.RoleViewer}},
+		{role: store.RoleMember, want: []string{store.RoleViewer}},
+		{role: store.RoleViewer, want: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.role, func(t *testing.T) {
+			require.Equal(t, tc.want, assignableRolesFor(tc.role))
+		})
+	}
+}
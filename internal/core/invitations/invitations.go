@@ -0,0 +1,192 @@
+package invitations
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	templBasic "github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+type GetInvitationsHandler struct {
+	invitationStore store.InvitationStore
+}
+
+type GetInvitationsHandlerParams struct {
+	InvitationStore store.InvitationStore
+}
+
+func NewGetInvitationsHandler(params GetInvitationsHandlerParams) *GetInvitationsHandler {
+	return &GetInvitationsHandler{
+		invitationStore: params.InvitationStore,
+	}
+}
+
+// GetInvitations renders the open invitations sent to the email address of
+// the logged-in user. Email addresses are not verified, so they are only
+// listed as a reminder; answering one takes the invitation link. It is only
+// ever requested by the layout, so it always returns a fragment.
+func (h *GetInvitationsHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	invitations, err := h.invitationStore.GetOpenInvitationsByEmail(user.Email)
+	if err != nil {
+		http.Error(w, "cannot fetch invitations", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.Invitations(invitations).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetInvitation is the page an invitation link opens, where the invited user
+// accepts or declines it. Logged-out visitors are sent to sign up with the
+// invitation instead.
+func (h *GetInvitationsHandler) GetInvitation(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/register?invitation="+url.QueryEscape(token), http.StatusFound)
+		return
+	}
+
+	invitation, err := h.invitationStore.GetInvitationByToken(token)
+	if err != nil {
+		http.Error(w, "invitation not found", http.StatusNotFound)
+		return
+	}
+
+	if !strings.EqualFold(invitation.Email, strings.TrimSpace(user.Email)) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	isHX := r.Header.Get("HX-Request") == "true"
+
+	c := templ.Invitation(isHX, invitation, invitation.IsOpen(time.Now()))
+
+	var out templBasic.Component
+	if isHX {
+		out = c
+	} else {
+		out = templ.Layout(c, "Invitation | Home Piggy Bank", true, user)
+	}
+
+	err = out.Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+type PostInvitationHandler struct {
+	invitationStore store.InvitationStore
+}
+
+type PostInvitationHandlerParams struct {
+	InvitationStore store.InvitationStore
+}
+
+func NewPostInvitationHandler(params PostInvitationHandlerParams) *PostInvitationHandler {
+	return &PostInvitationHandler{
+		invitationStore: params.InvitationStore,
+	}
+}
+
+func (h *PostInvitationHandler) PostAccept(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	invitation, ok := h.openInvitation(w, r, user, "Accept failed")
+	if !ok {
+		return
+	}
+
+	err := h.invitationStore.AcceptInvitation(invitation.Token, user.ID)
+	if errors.Is(err, store.ErrInvitationClosed) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Accept failed", "This invitation is no longer valid")
+		c.Render(r.Context(), w)
+		return
+	}
+	if err != nil {
+		http.Error(w, "cannot accept invitation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *PostInvitationHandler) PostDecline(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	invitation, ok := h.openInvitation(w, r, user, "Decline failed")
+	if !ok {
+		return
+	}
+
+	err := h.invitationStore.DeclineInvitation(invitation.Token)
+	if errors.Is(err, store.ErrInvitationClosed) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Decline failed", "This invitation is no longer valid")
+		c.Render(r.Context(), w)
+		return
+	}
+	if err != nil {
+		http.Error(w, "cannot decline invitation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/home")
+	w.WriteHeader(http.StatusOK)
+}
+
+// openInvitation loads the invitation named by the token route parameter. Only
+// the user the invitation was sent to may answer it, and only while it is
+// open. It writes the error response itself and returns false otherwise.
+func (h *PostInvitationHandler) openInvitation(w http.ResponseWriter, r *http.Request, user *store.User, title string) (store.Invitation, bool) {
+	invitation, err := h.invitationStore.GetInvitationByToken(chi.URLParam(r, "token"))
+	if err != nil {
+		http.Error(w, "invitation not found", http.StatusNotFound)
+		return store.Invitation{}, false
+	}
+
+	if !strings.EqualFold(invitation.Email, strings.TrimSpace(user.Email)) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return store.Invitation{}, false
+	}
+
+	if !invitation.IsOpen(time.Now()) {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error(title, "This invitation is no longer valid")
+		c.Render(r.Context(), w)
+		return store.Invitation{}, false
+	}
+
+	return invitation, true
+}
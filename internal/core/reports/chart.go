@@ -0,0 +1,157 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sort"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// subtotal is the sum of the shares in one category or household of a
+// report.
+type subtotal struct {
+	label  string
+	colour string
+	count  int
+	amount money.Money
+}
+
+// categorySubtotals sums shares by the category of their expense, largest
+// first. Categories belong to a household, so the household name is added to
+// the label when the shares come from more than one household.
+func categorySubtotals(shares []store.ExpenseShare) []subtotal {
+	multiple := len(householdSubtotals(shares)) > 1
+
+	return subtotals(shares, func(s store.ExpenseShare) (uint, string, string) {
+		c := s.Expense.Category
+		label := c.FullName()
+		if multiple {
+			label = fmt.Sprintf("%s (%s)", label, s.Expense.Household.Name)
+		}
+		return c.ID, label, c.Colour
+	})
+}
+
+// householdSubtotals sums shares by the household of their expense, largest
+// first.
+func householdSubtotals(shares []store.ExpenseShare) []subtotal {
+	return subtotals(shares, func(s store.ExpenseShare) (uint, string, string) {
+		return s.Expense.HouseholdID, s.Expense.Household.Name, ""
+	})
+}
+
+func subtotals(shares []store.ExpenseShare, key func(store.ExpenseShare) (uint, string, string)) []subtotal {
+	var result []subtotal
+	index := map[uint]int{}
+
+	for _, s := range shares {
+		id, label, colour := key(s)
+
+		i, ok := index[id]
+		if !ok {
+			i = len(result)
+			index[id] = i
+			result = append(result, subtotal{label: label, colour: colour, amount: money.New(0, s.Amount.Currency)})
+		}
+
+		result[i].count++
+		result[i].amount = result[i].amount.Add(s.Amount)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].amount.Float() > result[j].amount.Float()
+	})
+
+	return result
+}
+
+const (
+	chartWidth     = 600
+	chartRowHeight = 30
+	chartBarHeight = 20
+)
+
+// renderBarChart draws one horizontal bar per subtotal, scaled to the
+// largest, as a PNG. Labels are left to the PDF, which lays them out next to
+// the rows of the image, so no font has to be rasterised here.
+func renderBarChart(totals []subtotal) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartRowHeight*len(totals)))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	largest := 0.0
+	for _, t := range totals {
+		largest = max(largest, t.amount.Float())
+	}
+
+	for i, t := range totals {
+		width := 1
+		if largest > 0 {
+			width = max(width, int(t.amount.Float()/largest*chartWidth))
+		}
+
+		top := i*chartRowHeight + (chartRowHeight-chartBarHeight)/2
+		bar := image.Rect(0, top, width, top+chartBarHeight)
+		draw.Draw(img, bar, image.NewUniform(parseColour(t.colour)), image.Point{}, draw.Src)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseColour reads a colour in the form store.IsValidColour accepts and
+// falls back to grey for anything else.
+func parseColour(hex string) color.RGBA {
+	c := color.RGBA{R: 0xc7, G: 0xc7, B: 0xc7, A: 0xff}
+	if !store.IsValidColour(hex) {
+		return c
+	}
+
+	fmt.Sscanf(hex, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	return c
+}
+
+// memberTotal is what one member of a household owes in a household report:
+// the sum of their shares, how much of it is settled, by covering it as a
+// payer or by payments, and how much is still outstanding.
+type memberTotal struct {
+	username    string
+	count       int
+	amount      money.Money
+	settled     money.Money
+	outstanding money.Money
+}
+
+// memberTotals sums shares by member, in the order members first appear.
+func memberTotals(shares []store.ExpenseShare) []memberTotal {
+	var result []memberTotal
+	index := map[uint]int{}
+
+	for _, s := range shares {
+		i, ok := index[s.UserID]
+		if !ok {
+			i = len(result)
+			index[s.UserID] = i
+			zero := money.New(0, s.Amount.Currency)
+			result = append(result, memberTotal{username: s.User.Username, amount: zero, settled: zero, outstanding: zero})
+		}
+
+		outstanding := s.Outstanding()
+
+		result[i].count++
+		result[i].amount = result[i].amount.Add(s.Amount)
+		result[i].settled = result[i].settled.Add(s.Amount.Sub(outstanding))
+		result[i].outstanding = result[i].outstanding.Add(outstanding)
+	}
+
+	return result
+}
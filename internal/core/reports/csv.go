@@ -0,0 +1,42 @@
+package reports
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/spreadsheet"
+)
+
+// csvRenderer writes the shares of a report as a single CSV table, one row
+// per share, which is what spreadsheets and accounting tools import best.
+// The totals are left to whoever opens it. Names entered by members are
+// quoted when a spreadsheet would take them for a formula.
+type csvRenderer struct{}
+
+func (csvRenderer) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (csvRenderer) Render(w io.Writer, content Content) error {
+	t := shareTable(content)
+
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		header[i] = c.title
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range t.rows {
+		if err := cw.Write(spreadsheet.Row(row)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
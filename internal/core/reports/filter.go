@@ -0,0 +1,127 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// parseReportFilter reads the optional household_id and category_id
+// selections and the min_amount and max_amount bounds of a report form.
+// Only households out of households and their categories can be selected.
+// The form must already be parsed.
+func parseReportFilter(r *http.Request, households []store.Household) (store.ReportFilter, error) {
+	var filter store.ReportFilter
+
+	for _, value := range r.Form["household_id"] {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || !slices.ContainsFunc(households, func(h store.Household) bool { return h.ID == uint(id) }) {
+			return store.ReportFilter{}, errors.New("Unknown household")
+		}
+		filter.HouseholdIDs = append(filter.HouseholdIDs, uint(id))
+	}
+
+	for _, value := range r.Form["category_id"] {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || findCategory(households, uint(id)) == nil {
+			return store.ReportFilter{}, errors.New("Unknown category")
+		}
+		filter.CategoryIDs = append(filter.CategoryIDs, uint(id))
+	}
+
+	filter.MinAmount = strings.TrimSpace(r.FormValue("min_amount"))
+	filter.MaxAmount = strings.TrimSpace(r.FormValue("max_amount"))
+
+	lower, err := money.ParseDecimal(filter.MinAmount, 2)
+	if err != nil {
+		return store.ReportFilter{}, errors.New("Invalid minimum amount")
+	}
+
+	upper, err := money.ParseDecimal(filter.MaxAmount, 2)
+	if err != nil {
+		return store.ReportFilter{}, errors.New("Invalid maximum amount")
+	}
+
+	if filter.MaxAmount != "" && lower > upper {
+		return store.ReportFilter{}, errors.New("Minimum amount must not be greater than maximum amount")
+	}
+
+	return filter, nil
+}
+
+func findCategory(households []store.Household, categoryID uint) *store.Category {
+	for _, h := range households {
+		for i, c := range h.Categories {
+			if c.ID == categoryID {
+				return &h.Categories[i]
+			}
+		}
+	}
+	return nil
+}
+
+// describeFilter sums up filter for the header of a report, naming the
+// households and categories it selects out of households. It is empty for
+// an empty filter.
+func describeFilter(filter store.ReportFilter, households []store.Household) string {
+	var parts []string
+
+	if len(filter.HouseholdIDs) > 0 {
+		var names []string
+		for _, h := range households {
+			if slices.Contains(filter.HouseholdIDs, h.ID) {
+				names = append(names, h.Name)
+			}
+		}
+		parts = append(parts, "households: "+strings.Join(names, ", "))
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		var names []string
+		for _, id := range filter.CategoryIDs {
+			if c := findCategory(households, id); c != nil {
+				names = append(names, c.FullName())
+			}
+		}
+		parts = append(parts, "categories: "+strings.Join(names, ", "))
+	}
+
+	switch {
+	case filter.MinAmount != "" && filter.MaxAmount != "":
+		parts = append(parts, fmt.Sprintf("amount: %s - %s", filter.MinAmount, filter.MaxAmount))
+	case filter.MinAmount != "":
+		parts = append(parts, "amount: at least "+filter.MinAmount)
+	case filter.MaxAmount != "":
+		parts = append(parts, "amount: at most "+filter.MaxAmount)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// filterShares keeps the shares that pass filter.
+func filterShares(shares []store.ExpenseShare, filter store.ReportFilter) []store.ExpenseShare {
+	var kept []store.ExpenseShare
+	for _, s := range shares {
+		if filter.Matches(s) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// filterPayments keeps the payments towards shares that pass filter.
+func filterPayments(payments []store.Payment, filter store.ReportFilter) []store.Payment {
+	var kept []store.Payment
+	for _, p := range payments {
+		if filter.Matches(p.ExpenseShare) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
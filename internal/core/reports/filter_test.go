@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func testHouseholds() []store.Household {
+	food := store.Category{ID: 1, HouseholdID: 1, Name: "Food"}
+	return []store.Household{
+		{ID: 1, Name: "Home", Categories: []store.Category{
+			food,
+			{ID: 4, HouseholdID: 1, Name: "Groceries", ParentID: &food.ID, Parent: &food},
+		}},
+		{ID: 2, Name: "Cottage", Categories: []store.Category{{ID: 3, HouseholdID: 2, Name: "Repairs"}}},
+	}
+}
+
+func TestParseReportFilter(t *testing.T) {
+	parse := func(form url.Values) (store.ReportFilter, error) {
+		r := httptest.NewRequest("POST", "/report", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		require.NoError(t, r.ParseForm())
+		return parseReportFilter(r, testHouseholds())
+	}
+
+	filter, err := parse(url.Values{"household_id": {"1", "2"}, "category_id": {"4"}, "min_amount": {"10"}, "max_amount": {" 99.50 "}})
+	require.NoError(t, err)
+	require.Equal(t, store.ReportFilter{HouseholdIDs: []uint{1, 2}, CategoryIDs: []uint{4}, MinAmount: "10", MaxAmount: "99.50"}, filter)
+	require.Equal(t, "households: Home, Cottage; categories: Food / Groceries; amount: 10 - 99.50", describeFilter(filter, testHouseholds()))
+
+	filter, err = parse(url.Values{})
+	require.NoError(t, err)
+	require.True(t, filter.IsEmpty())
+	require.Empty(t, describeFilter(filter, testHouseholds()))
+
+	for _, form := range []url.Values{
+		{"household_id": {"5"}},
+		{"category_id": {"9"}},
+		{"min_amount": {"abc"}},
+		{"max_amount": {"1.005"}},
+		{"min_amount": {"20"}, "max_amount": {"10"}},
+	} {
+		_, err := parse(form)
+		require.Error(t, err, form.Encode())
+	}
+}
+
+func TestFilterShares(t *testing.T) {
+	households := testHouseholds()
+	share := func(household int, category int, amount money.Money) store.ExpenseShare {
+		h := households[household]
+		return store.ExpenseShare{Amount: amount, Expense: store.Expense{HouseholdID: h.ID, Category: h.Categories[category]}}
+	}
+
+	shares := []store.ExpenseShare{
+		share(0, 0, money.New(500, "PLN")),
+		share(0, 1, money.New(5000, "PLN")),
+		share(1, 0, money.New(2000, "EUR")),
+		share(1, 0, money.New(30, "HUF")),
+	}
+
+	require.Len(t, filterShares(shares, store.ReportFilter{}), 4)
+	require.Len(t, filterShares(shares, store.ReportFilter{HouseholdIDs: []uint{2}}), 2)
+	require.Equal(t, shares[:2], filterShares(shares, store.ReportFilter{CategoryIDs: []uint{1}}))
+	require.Equal(t, shares[1:2], filterShares(shares, store.ReportFilter{CategoryIDs: []uint{4}}))
+	require.Equal(t, shares[1:4], filterShares(shares, store.ReportFilter{MinAmount: "20"}))
+	require.Equal(t, []store.ExpenseShare{shares[0], shares[2]}, filterShares(shares, store.ReportFilter{MaxAmount: "20"}))
+}
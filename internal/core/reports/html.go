@@ -0,0 +1,72 @@
+package reports
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+)
+
+//go:embed templates/report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportHTML))
+
+// htmlRenderer writes a report as a standalone page with its own print
+// styles, meant to be opened in a browser and printed from there.
+type htmlRenderer struct{}
+
+func (htmlRenderer) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+// htmlPage, htmlTable and htmlCell export the fields the template reads.
+type htmlPage struct {
+	Title     string
+	Period    string
+	Filters   string
+	Generated string
+	Tables    []htmlTable
+}
+
+type htmlTable struct {
+	Title   string
+	Columns []htmlCell
+	Rows    [][]htmlCell
+}
+
+type htmlCell struct {
+	Title   string
+	Text    string
+	Numeric bool
+}
+
+func (htmlRenderer) Render(w io.Writer, content Content) error {
+	report := content.Report
+
+	page := htmlPage{
+		Title:     reportTitle(report),
+		Period:    reportPeriod(report),
+		Filters:   content.Filters,
+		Generated: report.GenerationDate.Format("02.01.2006 15:04"),
+	}
+
+	for _, t := range reportTables(content) {
+		ht := htmlTable{Title: t.title}
+
+		for _, c := range t.columns {
+			ht.Columns = append(ht.Columns, htmlCell{Title: c.title, Numeric: c.numeric})
+		}
+
+		for _, row := range t.rows {
+			cells := make([]htmlCell, len(row))
+			for i, value := range row {
+				cells[i] = htmlCell{Text: value, Numeric: t.columns[i].numeric}
+			}
+			ht.Rows = append(ht.Rows, cells)
+		}
+
+		page.Tables = append(page.Tables, ht)
+	}
+
+	return reportTemplate.Execute(w, page)
+}
@@ -1,47 +1,339 @@
 package reports
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 
 	"github.com/jung-kurt/gofpdf"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 )
 
-func GenerateReportPDF(report store.Report) (string, error) {
+// The core PDF fonts only cover Latin-1, which has no ą, ę, ł, ś or ż, so a
+// UTF-8 font is embedded and subset into every report.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	fontBold []byte
+)
+
+const (
+	fontFamily = "DejaVu"
+	rowHeight  = 6
+)
+
+// shareColumns are the columns of the table of shares, with their widths in
+// mm adding up to the printable width of an A4 page. A household report shows
+// the member of each share instead of its household.
+var shareColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Date", 20, "L"},
+	{"Household", 32, "L"},
+	{"Name", 52, "L"},
+	{"Category", 44, "L"},
+	{"Amount", 26, "R"},
+	{"Paid", 16, "C"},
+}
+
+// pdfRenderer lays a report out as an A4 document.
+type pdfRenderer struct{}
+
+func (pdfRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+func (pdfRenderer) Render(w io.Writer, content Content) error {
+	pdf, err := buildReportPDF(content.Report, content.Shares, content.Payments, content.Filters)
+	if err != nil {
+		return err
+	}
+
+	return pdf.Output(w)
+}
+
+// buildReportPDF lays out the report: a summary, the subtotals per category
+// with a bar chart and per household, or per member in a household report,
+// every share in the period and the payment history. Every page has a header
+// with the period and the filters and a footer with the page number.
+func buildReportPDF(report store.Report, shares []store.ExpenseShare, payments []store.Payment, filters string) (*gofpdf.Fpdf, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fontBold)
 
-	pdf.Cell(0, 10, "Expense Report")
+	period := reportPeriod(report)
+	title := reportTitle(report)
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont(fontFamily, "B", 9)
+		pdf.CellFormat(95, 6, fit(pdf, "Home Piggy Bank - "+title, 95), "", 0, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.CellFormat(95, 6, period, "", 1, "R", false, 0, "")
+		if filters != "" {
+			pdf.SetFont(fontFamily, "", 8)
+			pdf.MultiCell(190, 4, "Filters: "+filters, "", "L", false)
+		}
+		pdf.Line(10, pdf.GetY(), 200, pdf.GetY())
+		pdf.Ln(4)
+	})
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.CellFormat(95, 6, "Generated "+report.GenerationDate.Format("02.01.2006 15:04"), "", 0, "L", false, 0, "")
+		pdf.CellFormat(95, 6, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.Cell(0, 10, fit(pdf, title, 190))
 	pdf.Ln(12)
 
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetFont(fontFamily, "", 12)
 
-	pdf.Cell(0, 8, fmt.Sprintf("Period: %s - %s",
-		report.PeriodStart.Format("02.01.2006"),
-		report.PeriodEnd.Format("02.01.2006"),
-	))
+	pdf.Cell(0, 8, "Period: "+period)
 	pdf.Ln(8)
 
-	pdf.Cell(0, 8, fmt.Sprintf("Total expenses: %.2f", report.TotalExpenses))
+	pdf.Cell(0, 8, fmt.Sprintf("Total expenses: %s", report.TotalExpenses))
 	pdf.Ln(8)
 
 	pdf.Cell(0, 8, fmt.Sprintf("Payment status: %s", report.PaymentStatus))
 	pdf.Ln(8)
 
-	dir := "./files/reports"
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", err
+	if err := writeCategories(pdf, categorySubtotals(shares)); err != nil {
+		return nil, err
+	}
+	if report.HouseholdID != nil {
+		writeMembers(pdf, memberTotals(shares))
+	} else {
+		writeHouseholds(pdf, householdSubtotals(shares))
 	}
+	writeShares(pdf, shares, report.HouseholdID != nil)
+	writePayments(pdf, report, payments)
 
-	path := filepath.Join(dir, report.FileName)
+	return pdf, pdf.Error()
+}
+
+func writeHeading(pdf *gofpdf.Fpdf, title string) {
+	pdf.Ln(4)
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.Cell(0, 10, title)
+	pdf.Ln(10)
+	pdf.SetFont(fontFamily, "", 10)
+}
+
+// writeCategories prints the subtotals per category as a bar chart, each bar
+// between its category and its amount.
+func writeCategories(pdf *gofpdf.Fpdf, totals []subtotal) error {
+	writeHeading(pdf, "Expenses by category")
+
+	if len(totals) == 0 {
+		pdf.Cell(0, rowHeight, "No expenses in this period")
+		pdf.Ln(rowHeight)
+		return nil
+	}
 
-	err := pdf.OutputFileAndClose(path)
+	chart, err := renderBarChart(totals)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	const labelWidth, chartWidthMM, amountWidth = 60.0, 100.0, 30.0
+	row := chartWidthMM * chartRowHeight / chartWidth
+	height := row * float64(len(totals))
+
+	if pdf.GetY()+height > pageBottom(pdf) {
+		pdf.AddPage()
+	}
+
+	top := pdf.GetY()
+	pdf.RegisterImageOptionsReader("category-chart", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(chart))
+	pdf.ImageOptions("category-chart", 10+labelWidth, top, chartWidthMM, height, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	for i, t := range totals {
+		pdf.SetXY(10, top+float64(i)*row)
+		pdf.CellFormat(labelWidth-2, row, fit(pdf, t.label, labelWidth-2), "", 0, "R", false, 0, "")
+		pdf.SetX(10 + labelWidth + chartWidthMM)
+		pdf.CellFormat(amountWidth, row, t.amount.String(), "", 0, "R", false, 0, "")
+	}
+
+	pdf.SetXY(10, top+height)
+	return nil
+}
+
+func writeHouseholds(pdf *gofpdf.Fpdf, totals []subtotal) {
+	if len(totals) == 0 {
+		return
+	}
+
+	writeHeading(pdf, "Expenses by household")
+
+	for _, t := range totals {
+		pdf.CellFormat(100, rowHeight, fit(pdf, t.label, 100), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(40, rowHeight, fmt.Sprintf("%d shares", t.count), "B", 0, "R", false, 0, "")
+		pdf.CellFormat(50, rowHeight, t.amount.String(), "B", 1, "R", false, 0, "")
+	}
+}
+
+// writeMembers prints the shares of each member of a household and how much
+// of them is settled and outstanding.
+func writeMembers(pdf *gofpdf.Fpdf, totals []memberTotal) {
+	if len(totals) == 0 {
+		return
+	}
+
+	writeHeading(pdf, "Expenses by member")
+
+	columns := []string{"Member", "Shares", "Total", "Settled", "Outstanding"}
+	widths := []float64{58, 20, 38, 37, 37}
+
+	pdf.SetFont(fontFamily, "B", 10)
+	align := func(column int) string {
+		if column == 0 {
+			return "L"
+		}
+		return "R"
+	}
+
+	for i, c := range columns {
+		pdf.CellFormat(widths[i], rowHeight, c, "B", 0, align(i), false, 0, "")
+	}
+	pdf.Ln(rowHeight)
+	pdf.SetFont(fontFamily, "", 10)
+
+	for _, t := range totals {
+		values := []string{
+			fit(pdf, t.username, widths[0]),
+			fmt.Sprintf("%d", t.count),
+			t.amount.String(),
+			t.settled.String(),
+			t.outstanding.String(),
+		}
+
+		for i, v := range values {
+			pdf.CellFormat(widths[i], rowHeight, v, "B", 0, align(i), false, 0, "")
+		}
+		pdf.Ln(rowHeight)
+	}
+}
+
+// writeShares prints every share of the report as a table, repeating the
+// column titles at the top of each page it runs over.
+func writeShares(pdf *gofpdf.Fpdf, shares []store.ExpenseShare, byMember bool) {
+	if len(shares) == 0 {
+		return
+	}
+
+	writeHeading(pdf, "Expenses")
+	writeShareColumns(pdf, byMember)
+
+	for _, s := range shares {
+		if pdf.GetY()+rowHeight > pageBottom(pdf) {
+			pdf.AddPage()
+			writeShareColumns(pdf, byMember)
+		}
+
+		owner := s.Expense.Household.Name
+		if byMember {
+			owner = s.User.Username
+		}
+
+		paid := "no"
+		if s.Paid {
+			paid = "yes"
+		}
+
+		values := []string{
+			s.Expense.CreatedOn.Format("02.01.2006"),
+			owner,
+			s.Expense.Name,
+			s.Expense.Category.FullName(),
+			s.Amount.String(),
+			paid,
+		}
+
+		for i, c := range shareColumns {
+			pdf.CellFormat(c.width, rowHeight, fit(pdf, values[i], c.width), "B", 0, c.align, false, 0, "")
+		}
+		pdf.Ln(rowHeight)
+	}
+}
+
+func writeShareColumns(pdf *gofpdf.Fpdf, byMember bool) {
+	pdf.SetFont(fontFamily, "B", 10)
+	for i, c := range shareColumns {
+		title := c.title
+		if i == 1 && byMember {
+			title = "Member"
+		}
+		pdf.CellFormat(c.width, rowHeight, title, "B", 0, c.align, false, 0, "")
+	}
+	pdf.Ln(rowHeight)
+	pdf.SetFont(fontFamily, "", 10)
+}
+
+// writePayments lists the payments in the report period: those the report
+// owner made or received, or every payment between members in a household
+// report.
+func writePayments(pdf *gofpdf.Fpdf, report store.Report, payments []store.Payment) {
+	writeHeading(pdf, "Payment history")
+
+	if len(payments) == 0 {
+		pdf.Cell(0, rowHeight, "No payments in this period")
+		pdf.Ln(rowHeight)
+		return
+	}
+
+	for _, p := range payments {
+		direction := fmt.Sprintf("to %s", p.Payee.Username)
+		switch {
+		case report.HouseholdID != nil:
+			direction = fmt.Sprintf("%s to %s", p.Payer.Username, p.Payee.Username)
+		case p.PayeeID == report.UserID:
+			direction = fmt.Sprintf("from %s", p.Payer.Username)
+		}
+
+		line := fmt.Sprintf("%s  %s  %s for %s, %s (%s)",
+			p.PaidOn.Format("02.01.2006"),
+			p.Amount,
+			direction,
+			p.ExpenseShare.Expense.Name,
+			p.ExpenseShare.Expense.Category.FullName(),
+			p.Method,
+		)
+
+		pdf.Cell(0, rowHeight, fit(pdf, line, 190))
+		pdf.Ln(rowHeight)
+	}
+}
+
+// pageBottom is the lowest y a row can end at before the automatic page
+// break.
+func pageBottom(pdf *gofpdf.Fpdf) float64 {
+	_, height := pdf.GetPageSize()
+	_, margin := pdf.GetAutoPageBreak()
+	return height - margin
+}
+
+// fit shortens s with an ellipsis until it fits in a cell width mm wide in
+// the current font, leaving room for the cell padding.
+func fit(pdf *gofpdf.Fpdf, s string, width float64) string {
+	width -= 2 * pdf.GetCellMargin()
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
 	}
 
-	return path, nil
+	return string(runes) + "…"
 }
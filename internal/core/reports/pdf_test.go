@@ -0,0 +1,138 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func testShares() []store.ExpenseShare {
+	home := store.Household{ID: 1, Name: "Mieszkanie Łódź", BaseCurrency: "PLN"}
+	cottage := store.Household{ID: 2, Name: "Domek", BaseCurrency: "EUR"}
+	food := store.Category{ID: 1, HouseholdID: 1, Name: "Żywność", Colour: "#36a2eb"}
+	rent := store.Category{ID: 2, HouseholdID: 1, Name: "Czynsz", Colour: "#ff6384"}
+	repairs := store.Category{ID: 3, HouseholdID: 2, Name: "Repairs"}
+
+	share := func(h store.Household, c store.Category, name string, minor int64) store.ExpenseShare {
+		return store.ExpenseShare{
+			Expense: store.Expense{
+				Name:        name,
+				HouseholdID: h.ID,
+				Household:   h,
+				Category:    c,
+				CreatedOn:   time.Date(2026, time.September, 3, 0, 0, 0, 0, time.UTC),
+			},
+			Amount: money.New(minor, h.BaseCurrency),
+		}
+	}
+
+	return []store.ExpenseShare{
+		share(home, food, "Zakupy w spożywczym", 4550),
+		share(home, rent, "Czynsz za wrzesień", 120000),
+		share(cottage, repairs, "Roof", 30000),
+		share(home, food, "Piekarnia", 1200),
+	}
+}
+
+func TestSubtotals(t *testing.T) {
+	shares := testShares()
+
+	require.Equal(t, []subtotal{
+		{label: "Czynsz (Mieszkanie Łódź)", colour: "#ff6384", count: 1, amount: money.New(120000, "PLN")},
+		{label: "Repairs (Domek)", colour: "", count: 1, amount: money.New(30000, "EUR")},
+		{label: "Żywność (Mieszkanie Łódź)", colour: "#36a2eb", count: 2, amount: money.New(5750, "PLN")},
+	}, categorySubtotals(shares))
+
+	require.Equal(t, []subtotal{
+		{label: "Mieszkanie Łódź", count: 3, amount: money.New(125750, "PLN")},
+		{label: "Domek", count: 1, amount: money.New(30000, "EUR")},
+	}, householdSubtotals(shares))
+
+	require.Equal(t, "Żywność", categorySubtotals(shares[3:])[0].label)
+}
+
+func TestRenderBarChart(t *testing.T) {
+	chart, err := renderBarChart(categorySubtotals(testShares()))
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(chart))
+	require.NoError(t, err)
+	require.Equal(t, chartWidth, img.Bounds().Dx())
+	require.Equal(t, 3*chartRowHeight, img.Bounds().Dy())
+
+	r, g, b, _ := img.At(chartWidth-1, chartRowHeight/2).RGBA()
+	require.Equal(t, []uint32{0xff, 0x63, 0x84}, []uint32{r >> 8, g >> 8, b >> 8})
+}
+
+func TestBuildReportPDF(t *testing.T) {
+	shares := testShares()
+	for i := range 80 {
+		shares = append(shares, shares[i%len(shares)])
+		shares[len(shares)-1].Expense.Name = fmt.Sprintf("Wydatek nr %d ze zbyt długą nazwą, żeby zmieścić się w kolumnie", i)
+	}
+
+	report := store.Report{
+		UserID:         1,
+		PeriodStart:    time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:      time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC),
+		TotalExpenses:  money.New(125750, "PLN"),
+		PaymentStatus:  "all",
+		GenerationDate: time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC),
+	}
+
+	pdf, err := buildReportPDF(report, shares, nil, "categories: Żywność; amount: at least 10")
+	require.NoError(t, err)
+	require.Greater(t, pdf.PageNo(), 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, pdf.Output(&buf))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF")))
+}
+
+func TestMemberTotals(t *testing.T) {
+	anna := store.User{ID: 1, Username: "anna"}
+	piotr := store.User{ID: 2, Username: "piotr"}
+
+	shares := []store.ExpenseShare{
+		{UserID: 1, User: anna, Amount: money.New(5000, "PLN"), Covered: money.New(5000, "PLN"), Paid: true},
+		{UserID: 2, User: piotr, Amount: money.New(5000, "PLN"), Covered: money.New(0, "PLN"), Payments: []store.Payment{
+			{Amount: money.New(2000, "PLN"), PaidOn: time.Date(2026, time.September, 5, 0, 0, 0, 0, time.UTC)},
+		}},
+		{UserID: 2, User: piotr, Amount: money.New(1000, "PLN"), Covered: money.New(0, "PLN"), Payments: []store.Payment{
+			{Amount: money.New(1000, "PLN"), PaidOn: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+
+	require.Equal(t, []memberTotal{
+		{username: "anna", count: 1, amount: money.New(5000, "PLN"), settled: money.New(5000, "PLN"), outstanding: money.New(0, "PLN")},
+		{username: "piotr", count: 2, amount: money.New(6000, "PLN"), settled: money.New(3000, "PLN"), outstanding: money.New(3000, "PLN")},
+	}, memberTotals(shares))
+
+	payments := sharePayments(shares)
+	require.Len(t, payments, 2)
+	require.Equal(t, money.New(1000, "PLN"), payments[0].Amount)
+	require.Equal(t, money.New(1000, "PLN"), payments[0].ExpenseShare.Amount)
+}
+
+func TestBuildHouseholdReportPDF(t *testing.T) {
+	shares := testShares()[:2]
+	householdID := shares[0].Expense.HouseholdID
+
+	report := store.Report{
+		UserID:        1,
+		HouseholdID:   &householdID,
+		Household:     &shares[0].Expense.Household,
+		TotalExpenses: money.New(124550, "PLN"),
+		PaymentStatus: "all",
+	}
+
+	pdf, err := buildReportPDF(report, shares, sharePayments(shares), "")
+	require.NoError(t, err)
+	require.Equal(t, 1, pdf.PageNo())
+}
@@ -0,0 +1,363 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxReportAttempts is how many times a report is generated before it
+	// fails for good.
+	maxReportAttempts = 3
+	// reportRetryDelay is how long a report waits after its first failed
+	// attempt; every further attempt waits that much longer.
+	reportRetryDelay = time.Minute
+	// failedReportLifetime is how long a report that failed for good stays
+	// listed before it is cleaned up.
+	failedReportLifetime = 24 * time.Hour
+)
+
+// ReportQueue generates queued reports in the background with a pool of
+// workers. It runs inside the server process; reports still queued or
+// running when the server stops are picked up again on the next start.
+type ReportQueue struct {
+	reportStore       store.ReportStore
+	expenseShareStore store.ExpenseShareStore
+	paymentStore      store.PaymentStore
+	householdStore    store.HouseholdStore
+	fileStore         store.FileStore
+	workers           int
+	interval          time.Duration
+	retention         time.Duration
+	wake              chan struct{}
+}
+
+type ReportQueueParams struct {
+	ReportStore       store.ReportStore
+	ExpenseShareStore store.ExpenseShareStore
+	PaymentStore      store.PaymentStore
+	HouseholdStore    store.HouseholdStore
+	FileStore         store.FileStore
+	Workers           int
+	Interval          time.Duration
+	// Retention is how long a generated report is kept before it is
+	// deleted along with its file. Zero keeps reports forever.
+	Retention time.Duration
+}
+
+func NewReportQueue(params ReportQueueParams) *ReportQueue {
+	workers := max(params.Workers, 1)
+
+	return &ReportQueue{
+		reportStore:       params.ReportStore,
+		expenseShareStore: params.ExpenseShareStore,
+		paymentStore:      params.PaymentStore,
+		householdStore:    params.HouseholdStore,
+		fileStore:         params.FileStore,
+		workers:           workers,
+		interval:          params.Interval,
+		retention:         params.Retention,
+		wake:              make(chan struct{}, workers),
+	}
+}
+
+// Enqueue records report as queued and wakes an idle worker to generate it.
+func (q *ReportQueue) Enqueue(report store.Report) (store.Report, error) {
+	report, err := q.reportStore.QueueReport(report)
+	if err != nil {
+		return store.Report{}, err
+	}
+
+	q.notify()
+	return report, nil
+}
+
+// Regenerate queues report to be generated again from the current expenses.
+// Its file is replaced once the new one is written and kept if that fails.
+func (q *ReportQueue) Regenerate(report store.Report) error {
+	if err := q.reportStore.RequeueReport(report.ID); err != nil {
+		return err
+	}
+
+	q.notify()
+	return nil
+}
+
+// Delete deletes report and then its file, so that a report is never listed
+// without its file. A file left behind is removed on the next start.
+func (q *ReportQueue) Delete(report store.Report) error {
+	if err := q.reportStore.DeleteReport(report.ID); err != nil {
+		return err
+	}
+
+	q.removeFile(report)
+	return nil
+}
+
+func (q *ReportQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run requeues reports left running by a previous run, reconciles reports
+// with their files and starts the workers. Every interval the workers look
+// for reports due for a retry and expired reports are cleaned up. It returns
+// once ctx is cancelled and every worker has finished the report it was
+// generating.
+func (q *ReportQueue) Run(ctx context.Context) {
+	if err := q.reportStore.RequeueRunningReports(); err != nil {
+		log.Printf("cannot requeue running reports: %v", err)
+	}
+
+	if err := q.Reconcile(); err != nil {
+		log.Printf("cannot reconcile reports with their files: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	for {
+		q.CleanUp(time.Now())
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *ReportQueue) work(ctx context.Context) {
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && q.RunNext(time.Now()) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// RunNext generates the next report due at now, if any, and reports whether
+// there was one. Every attempt writes a file of a new name, so that the file
+// of a report being regenerated is only removed once its replacement is
+// stored. A failed attempt is retried later, or fails the report for good
+// after maxReportAttempts, and never leaves a partial file behind.
+func (q *ReportQueue) RunNext(now time.Time) bool {
+	report, err := q.reportStore.ClaimNextReport(now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false
+	}
+	if err != nil {
+		log.Printf("cannot claim next report: %v", err)
+		return false
+	}
+
+	generated := report
+	generated.FileName = report.NewFileName(time.Now())
+
+	err = q.generate(generated)
+	if err == nil {
+		q.removeFile(report)
+		return true
+	}
+
+	log.Printf("cannot generate report %d (attempt %d): %v", report.ID, report.Attempts, err)
+	q.removeFile(generated)
+
+	if runAfter, ok := nextAttempt(report, now); ok {
+		err = q.reportStore.RetryReport(report.ID, err.Error(), runAfter)
+	} else {
+		err = q.reportStore.FailReport(report.ID, err.Error())
+	}
+	if err != nil {
+		log.Printf("cannot record failure of report %d: %v", report.ID, err)
+	}
+
+	return true
+}
+
+// nextAttempt returns when a report whose attempt failed at now should be
+// tried again, or false when it has run out of attempts.
+func nextAttempt(report store.Report, now time.Time) (time.Time, bool) {
+	if report.Attempts >= maxReportAttempts {
+		return time.Time{}, false
+	}
+	return now.Add(time.Duration(report.Attempts) * reportRetryDelay), true
+}
+
+// CleanUp deletes the reports that failed for good longer than
+// failedReportLifetime before now, along with any file they left, and the
+// reports generated longer than the retention period before now, along with
+// their files.
+func (q *ReportQueue) CleanUp(now time.Time) {
+	q.deleteBefore(store.ReportFailed, now.Add(-failedReportLifetime))
+
+	if q.retention > 0 {
+		q.deleteBefore(store.ReportDone, now.Add(-q.retention))
+	}
+}
+
+func (q *ReportQueue) deleteBefore(status store.ReportStatus, before time.Time) {
+	reports, err := q.reportStore.DeleteReportsBefore(status, before)
+	if err != nil {
+		log.Printf("cannot delete %s reports: %v", status, err)
+		return
+	}
+
+	for _, report := range reports {
+		q.removeFile(report)
+	}
+}
+
+// Reconcile removes the files no report refers to, such as those of reports
+// deleted while their file could not be, and queues the generated reports
+// whose file is missing to be generated again.
+func (q *ReportQueue) Reconcile() error {
+	reports, err := q.reportStore.GetAllReports()
+	if err != nil {
+		return err
+	}
+
+	names, err := q.fileStore.List()
+	if err != nil {
+		return err
+	}
+
+	orphans, missing := unmatchedReports(reports, names)
+
+	for _, name := range orphans {
+		if err := q.fileStore.Delete(name); err != nil && !errors.Is(err, store.ErrFileNotFound) {
+			log.Printf("cannot remove orphaned report file %s: %v", name, err)
+		}
+	}
+
+	for _, report := range missing {
+		if err := q.reportStore.RequeueReport(report.ID); err != nil {
+			log.Printf("cannot requeue report %d without a file: %v", report.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// unmatchedReports matches reports with the file names in their store. It
+// returns the names no report refers to and the generated reports whose file
+// is not among names. Pending and failed reports have no file to miss.
+func unmatchedReports(reports []store.Report, names []string) ([]string, []store.Report) {
+	referenced := map[string]bool{}
+	for _, r := range reports {
+		referenced[r.FileName] = true
+	}
+
+	existing := map[string]bool{}
+	var orphans []string
+	for _, name := range names {
+		existing[name] = true
+		if !referenced[name] {
+			orphans = append(orphans, name)
+		}
+	}
+
+	var missing []store.Report
+	for _, r := range reports {
+		if r.Status == store.ReportDone && !existing[r.FileName] {
+			missing = append(missing, r)
+		}
+	}
+
+	return orphans, missing
+}
+
+// generate loads the shares and payments report covers as of now, writes its
+// file in its format and records it as done with its total.
+func (q *ReportQueue) generate(report store.Report) error {
+	households, err := q.householdStore.GetHouseholdsByUserID(report.UserID)
+	if err != nil {
+		return err
+	}
+
+	var shares []store.ExpenseShare
+	var payments []store.Payment
+
+	if report.HouseholdID != nil {
+		i := slices.IndexFunc(households, func(h store.Household) bool { return h.ID == *report.HouseholdID })
+		if i < 0 {
+			return errors.New("requester is no longer a member of the household")
+		}
+		households = households[i : i+1]
+		report.Household = &households[0]
+
+		shares, err = q.expenseShareStore.GetSharesByHouseholdIDInPeriod(*report.HouseholdID, report.PeriodStart, report.PeriodEnd, report.PaymentStatus)
+		if err != nil {
+			return err
+		}
+
+		shares = filterShares(shares, report.Filter)
+		payments = sharePayments(shares)
+	} else {
+		shares, err = q.expenseShareStore.GetSharesByUserIDInPeriod(report.UserID, report.PeriodStart, report.PeriodEnd, report.PaymentStatus)
+		if err != nil {
+			return err
+		}
+
+		payments, err = q.paymentStore.GetPaymentsByUserID(report.UserID, report.PeriodStart, report.PeriodEnd)
+		if err != nil {
+			return err
+		}
+
+		shares = filterShares(shares, report.Filter)
+		payments = filterPayments(payments, report.Filter)
+	}
+
+	report.TotalExpenses, err = q.reportStore.ReportTotal(shares, report.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	report.GenerationDate = time.Now()
+
+	content := Content{
+		Report:   report,
+		Shares:   shares,
+		Payments: payments,
+		Filters:  describeFilter(report.Filter, households),
+	}
+
+	if err := GenerateReport(q.fileStore, content); err != nil {
+		return err
+	}
+
+	return q.reportStore.CompleteReport(report)
+}
+
+func (q *ReportQueue) removeFile(report store.Report) {
+	err := q.fileStore.Delete(report.FileName)
+	if err != nil && !errors.Is(err, store.ErrFileNotFound) {
+		log.Printf("cannot remove file of report %d: %v", report.ID, err)
+	}
+}
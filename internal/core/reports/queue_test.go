@@ -0,0 +1,127 @@
+package reports
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/db"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/dbstore"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/filestore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextAttempt(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC)
+
+	runAfter, ok := nextAttempt(store.Report{Attempts: 1}, now)
+	require.True(t, ok)
+	require.Equal(t, now.Add(reportRetryDelay), runAfter)
+
+	runAfter, ok = nextAttempt(store.Report{Attempts: 2}, now)
+	require.True(t, ok)
+	require.Equal(t, now.Add(2*reportRetryDelay), runAfter)
+
+	_, ok = nextAttempt(store.Report{Attempts: maxReportAttempts}, now)
+	require.False(t, ok)
+}
+
+func TestUnmatchedReports(t *testing.T) {
+	reports := []store.Report{
+		{ID: 1, FileName: "report_1_1.pdf", Status: store.ReportDone},
+		{ID: 2, FileName: "report_1_2.pdf", Status: store.ReportDone},
+		{ID: 3, FileName: "report_1_3.pdf", Status: store.ReportQueued},
+		{ID: 4, FileName: "report_1_4.pdf", Status: store.ReportFailed},
+	}
+
+	orphans, missing := unmatchedReports(reports, []string{"report_1_1.pdf", "report_1_5.pdf"})
+	require.Equal(t, []string{"report_1_5.pdf"}, orphans)
+	require.Equal(t, reports[1:2], missing)
+}
+
+func TestRunNext_Regenerate(t *testing.T) {
+	database := db.MustOpen(filepath.Join(t.TempDir(), "test.db"))
+	reportStore := dbstore.NewReportStore(dbstore.NewReportStoreParams{DB: database})
+	files := filestore.NewMemoryStore()
+
+	queue := NewReportQueue(ReportQueueParams{
+		ReportStore:       reportStore,
+		ExpenseShareStore: dbstore.NewExpenseShareStore(dbstore.NewExpenseShareStoreParams{DB: database}),
+		PaymentStore:      dbstore.NewPaymentStore(dbstore.NewPaymentStoreParams{DB: database}),
+		HouseholdStore:    dbstore.NewHouseholdStore(dbstore.NewHouseholdStoreParams{DB: database}),
+		FileStore:         files,
+	})
+
+	requireFile := func(reportID uint, status store.ReportStatus) store.Report {
+		report, err := reportStore.GetReportByID(reportID)
+		require.NoError(t, err)
+		require.Equal(t, status, report.Status)
+
+		names, err := files.List()
+		require.NoError(t, err)
+		require.Equal(t, []string{report.FileName}, names)
+		return report
+	}
+
+	queued, err := reportStore.QueueReport(store.Report{
+		UserID:      1,
+		PeriodStart: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC),
+		Format:      store.ReportCSV,
+	})
+	require.NoError(t, err)
+
+	require.True(t, queue.RunNext(time.Now()))
+	generated := requireFile(queued.ID, store.ReportDone)
+
+	// A regenerated report replaces its file.
+	require.NoError(t, queue.Regenerate(generated))
+	require.True(t, queue.RunNext(time.Now()))
+	regenerated := requireFile(queued.ID, store.ReportDone)
+	require.NotEqual(t, generated.FileName, regenerated.FileName)
+
+	// A failed regeneration keeps the last file, here because the requester
+	// is not a member of the household.
+	require.NoError(t, database.Model(&store.Report{}).Where("id = ?", queued.ID).Update("household_id", 99).Error)
+	require.NoError(t, queue.Regenerate(regenerated))
+	require.True(t, queue.RunNext(time.Now()))
+	failed := requireFile(queued.ID, store.ReportQueued)
+	require.Equal(t, regenerated.FileName, failed.FileName)
+	require.NotEmpty(t, failed.Error)
+}
+
+func TestCleanUp_Retention(t *testing.T) {
+	database := db.MustOpen(filepath.Join(t.TempDir(), "test.db"))
+	reportStore := dbstore.NewReportStore(dbstore.NewReportStoreParams{DB: database})
+	files := filestore.NewMemoryStore()
+
+	queue := NewReportQueue(ReportQueueParams{
+		ReportStore:       reportStore,
+		ExpenseShareStore: dbstore.NewExpenseShareStore(dbstore.NewExpenseShareStoreParams{DB: database}),
+		PaymentStore:      dbstore.NewPaymentStore(dbstore.NewPaymentStoreParams{DB: database}),
+		HouseholdStore:    dbstore.NewHouseholdStore(dbstore.NewHouseholdStoreParams{DB: database}),
+		FileStore:         files,
+		Retention:         time.Hour,
+	})
+
+	queued, err := reportStore.QueueReport(store.Report{UserID: 1, Format: store.ReportCSV})
+	require.NoError(t, err)
+	require.True(t, queue.RunNext(time.Now()))
+
+	// Reports expire by when they finished, not by when they were requested.
+	requestedOn := time.Now().AddDate(-1, 0, 0)
+	require.NoError(t, database.Model(&store.Report{}).Where("id = ?", queued.ID).Update("generation_date", requestedOn).Error)
+
+	queue.CleanUp(time.Now())
+	_, err = reportStore.GetReportByID(queued.ID)
+	require.NoError(t, err)
+
+	queue.CleanUp(time.Now().Add(2 * time.Hour))
+	_, err = reportStore.GetReportByID(queued.ID)
+	require.Error(t, err)
+
+	names, err := files.List()
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
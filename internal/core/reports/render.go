@@ -0,0 +1,58 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// Content is what a report shows: the report itself with its total, the
+// shares and payments it covers and a description of its filter.
+type Content struct {
+	Report   store.Report
+	Shares   []store.ExpenseShare
+	Payments []store.Payment
+	Filters  string
+}
+
+// Renderer writes the content of a report in one file format.
+type Renderer interface {
+	Render(w io.Writer, content Content) error
+	// ContentType is the media type of the files it writes.
+	ContentType() string
+}
+
+// renderers holds the renderer of every store.ReportFormat.
+var renderers = map[store.ReportFormat]Renderer{
+	store.ReportPDF:  pdfRenderer{},
+	store.ReportCSV:  csvRenderer{},
+	store.ReportXLSX: xlsxRenderer{},
+	store.ReportHTML: htmlRenderer{},
+}
+
+// RendererFor returns the renderer of format.
+func RendererFor(format store.ReportFormat) (Renderer, error) {
+	renderer, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown report format %q", format)
+	}
+	return renderer, nil
+}
+
+// GenerateReport writes content in the format of its report to the file of
+// the report in files.
+func GenerateReport(files store.FileStore, content Content) error {
+	renderer, err := RendererFor(content.Report.Format)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, content); err != nil {
+		return err
+	}
+
+	return files.Put(content.Report.FileName, &buf)
+}
@@ -0,0 +1,121 @@
+package reports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/filestore"
+	"github.com/stretchr/testify/require"
+)
+
+func testContent(format store.ReportFormat) Content {
+	return Content{
+		Report: store.Report{
+			UserID:         1,
+			PeriodStart:    time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:      time.Date(2026, time.September, 30, 0, 0, 0, 0, time.UTC),
+			TotalExpenses:  money.New(125750, "PLN"),
+			PaymentStatus:  "all",
+			GenerationDate: time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC),
+			Format:         format,
+			FileName:       "report_1_1." + string(format),
+		},
+		Shares:  testShares(),
+		Filters: "amount: at least 10 & <more>",
+	}
+}
+
+func TestGenerateReport(t *testing.T) {
+	files := filestore.NewMemoryStore()
+
+	for _, format := range store.ReportFormats {
+		require.NoError(t, GenerateReport(files, testContent(format)), format)
+	}
+
+	names, err := files.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"report_1_1.csv", "report_1_1.html", "report_1_1.pdf", "report_1_1.xlsx"}, names)
+
+	r, err := files.Get("report_1_1.pdf")
+	require.NoError(t, err)
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(content, []byte("%PDF")))
+
+	require.Error(t, GenerateReport(files, testContent("odt")))
+}
+
+func TestCSVRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, csvRenderer{}.Render(&buf, testContent(store.ReportCSV)))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	require.Equal(t, []string{"Date", "Household", "Name", "Category", "Amount", "Currency", "Paid"}, records[0])
+	require.Equal(t, []string{"2026-09-03", "Mieszkanie Łódź", "Zakupy w spożywczym", "Żywność", "45.50", "PLN", "no"}, records[1])
+}
+
+func TestCSVRenderer_Formula(t *testing.T) {
+	content := testContent(store.ReportCSV)
+	content.Shares[0].Expense.Name = `=HYPERLINK("http://example.com","Zakupy")`
+	content.Shares[0].Expense.Category.Name = "@SUM(A1)"
+
+	var buf bytes.Buffer
+	require.NoError(t, csvRenderer{}.Render(&buf, content))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"2026-09-03", "Mieszkanie Łódź", `'=HYPERLINK("http://example.com","Zakupy")`, "'@SUM(A1)", "45.50", "PLN", "no",
+	}, records[1])
+}
+
+func TestXLSXRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, xlsxRenderer{}.Render(&buf, testContent(store.ReportXLSX)))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(data)
+	}
+
+	require.Contains(t, parts, "[Content_Types].xml")
+	require.Contains(t, parts["xl/workbook.xml"], `<sheet name="Expenses by category" sheetId="2" r:id="rId2"/>`)
+	require.Contains(t, parts["xl/worksheets/sheet1.xml"], "amount: at least 10 &amp; &lt;more&gt;")
+	require.Contains(t, parts["xl/worksheets/sheet4.xml"], `<c r="E2"><v>45.50</v></c>`)
+	require.Len(t, zr.File, 4+5)
+
+	require.Equal(t, "A", xlsxColumn(0))
+	require.Equal(t, "Z", xlsxColumn(25))
+	require.Equal(t, "AA", xlsxColumn(26))
+	require.Equal(t, "BA", xlsxColumn(52))
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, htmlRenderer{}.Render(&buf, testContent(store.ReportHTML)))
+
+	page := buf.String()
+	require.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+	require.Contains(t, page, "<h1>Expense report</h1>")
+	require.Contains(t, page, "Filters: amount: at least 10 &amp; &lt;more&gt;")
+	require.Contains(t, page, `<td class="numeric">45.50</td>`)
+	require.Contains(t, page, "<h2>Payment history</h2>")
+}
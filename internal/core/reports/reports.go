@@ -1,8 +1,13 @@
 package reports
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"time"
 
 	templBasic "github.com/a-h/templ"
@@ -10,19 +15,26 @@ import (
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
 )
 
 type GetReportsHandler struct {
-	reportStore store.ReportStore
+	reportStore         store.ReportStore
+	householdStore      store.HouseholdStore
+	reportScheduleStore store.ReportScheduleStore
 }
 
 type GetReportsHandlerParams struct {
-	ReportStore store.ReportStore
+	ReportStore         store.ReportStore
+	HouseholdStore      store.HouseholdStore
+	ReportScheduleStore store.ReportScheduleStore
 }
 
 func NewGetReportsHandler(params GetReportsHandlerParams) *GetReportsHandler {
 	return &GetReportsHandler{
-		reportStore: params.ReportStore,
+		reportStore:         params.ReportStore,
+		householdStore:      params.HouseholdStore,
+		reportScheduleStore: params.ReportScheduleStore,
 	}
 }
 
@@ -40,9 +52,21 @@ func (h *GetReportsHandler) GetReports(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	households, err := h.householdStore.GetHouseholdsByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load households", http.StatusInternalServerError)
+		return
+	}
+
+	schedules, err := h.reportScheduleStore.GetReportSchedulesByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load report schedules", http.StatusInternalServerError)
+		return
+	}
+
 	isHX := r.Header.Get("HX-Request") == "true"
 
-	c := templ.Reports(isHX, reports)
+	c := templ.Reports(isHX, reports, schedules, households)
 
 	var out templBasic.Component
 	if isHX {
@@ -60,20 +84,28 @@ func (h *GetReportsHandler) GetReports(w http.ResponseWriter, r *http.Request) {
 }
 
 type GetReportHandler struct {
-	reportStore store.ReportStore
+	reportStore     store.ReportStore
+	membershipStore store.MembershipStore
+	fileStore       store.FileStore
 }
 
 type GetReportHandlerParams struct {
-	ReportStore store.ReportStore
+	ReportStore     store.ReportStore
+	MembershipStore store.MembershipStore
+	FileStore       store.FileStore
 }
 
 func NewGetReportHandler(params GetReportHandlerParams) *GetReportHandler {
 	return &GetReportHandler{
-		reportStore: params.ReportStore,
+		reportStore:     params.ReportStore,
+		membershipStore: params.MembershipStore,
+		fileStore:       params.FileStore,
 	}
 }
 
-func (h *GetReportHandler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
+// DownloadReport streams the file of a generated report. PDF and HTML
+// reports open in the browser, spreadsheets are downloaded.
+func (h *GetReportHandler) DownloadReport(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUser(r.Context())
 	if user == nil {
 		http.Redirect(w, r, "/", http.StatusFound)
@@ -88,29 +120,172 @@ func (h *GetReportHandler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if report.UserID != user.ID {
+	if canView, _ := reportAccess(h.membershipStore, report, user.ID); !canView {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if report.Status != store.ReportDone {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+
+	renderer, err := RendererFor(report.Format)
+	if err != nil {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+
+	file, err := h.fileStore.Get(report.FileName)
+	if errors.Is(err, store.ErrFileNotFound) {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load report", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	disposition := "attachment"
+	if report.Format == store.ReportPDF || report.Format == store.ReportHTML {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, report.FileName))
+	io.Copy(w, file)
+}
+
+// GetReportStatus renders the row of a report in a list of reports. Rows of
+// pending reports poll it until their report is generated or has failed.
+func (h *GetReportHandler) GetReportStatus(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	reportID, err := strconv.ParseUint(chi.URLParam(r, "reportID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid report ID", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportStore.GetReportByID(uint(reportID))
+	if err != nil {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+
+	canView, canManage := reportAccess(h.membershipStore, report, user.ID)
+	if !canView {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	err = templ.ReportRow(report, canManage).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+}
+
+// reportAccess reports whether userID may see and download report, and
+// whether they may delete and regenerate it. Users manage their own personal
+// reports. Every member of a household sees its household reports, and
+// members who may generate household reports manage them.
+func reportAccess(membershipStore store.MembershipStore, report store.Report, userID uint) (canView, canManage bool) {
+	if report.HouseholdID == nil {
+		return report.UserID == userID, report.UserID == userID
+	}
+
+	membership, err := membershipStore.GetMembership(*report.HouseholdID, userID)
+	if err != nil {
+		return false, false
+	}
+
+	return true, membership.Can(store.PermHouseholdReports)
+}
+
+type GetHouseholdReportsHandler struct {
+	reportStore   store.ReportStore
+	categoryStore store.CategoryStore
+}
+
+type GetHouseholdReportsHandlerParams struct {
+	ReportStore   store.ReportStore
+	CategoryStore store.CategoryStore
+}
+
+func NewGetHouseholdReportsHandler(params GetHouseholdReportsHandlerParams) *GetHouseholdReportsHandler {
+	return &GetHouseholdReportsHandler{
+		reportStore:   params.ReportStore,
+		categoryStore: params.CategoryStore,
+	}
+}
+
+// GetHouseholdReports lists the household reports of the household, with the
+// form to generate one for members who may.
+func (h *GetHouseholdReportsHandler) GetHouseholdReports(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	path := filepath.Join("./files/reports", fileName)
-	http.ServeFile(w, r, path)
+	reports, err := h.reportStore.GetReportsByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "Failed to load reports", http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := h.categoryStore.GetCategoriesByHouseholdID(membership.HouseholdID)
+	if err != nil {
+		http.Error(w, "cannot fetch categories", http.StatusInternalServerError)
+		return
+	}
+
+	err = templ.HouseholdReports(reports, categories, membership).Render(r.Context(), w)
+
+	if err != nil {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
 }
 
 type PostReportHandler struct {
-	reportStore store.ReportStore
+	reportStore     store.ReportStore
+	householdStore  store.HouseholdStore
+	membershipStore store.MembershipStore
+	reportQueue     *ReportQueue
 }
 
 type PostReportHandlerParams struct {
-	ReportStore store.ReportStore
+	ReportStore     store.ReportStore
+	HouseholdStore  store.HouseholdStore
+	MembershipStore store.MembershipStore
+	ReportQueue     *ReportQueue
 }
 
 func NewPostReportsHandler(params PostReportHandlerParams) *PostReportHandler {
 	return &PostReportHandler{
-		reportStore: params.ReportStore,
+		reportStore:     params.ReportStore,
+		householdStore:  params.HouseholdStore,
+		membershipStore: params.MembershipStore,
+		reportQueue:     params.ReportQueue,
 	}
 }
 
+// PostGenerateReport queues a personal report of the shares of the user in
+// the period. The reports page shows it as pending until it is generated.
 func (h *PostReportHandler) PostGenerateReport(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUser(r.Context())
 	if user == nil {
@@ -118,52 +293,248 @@ func (h *PostReportHandler) PostGenerateReport(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
+	households, err := h.householdStore.GetHouseholdsByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load households", http.StatusInternalServerError)
 		return
 	}
 
-	fromStr := r.FormValue("period_start")
-	toStr := r.FormValue("period_end")
-	paymentStatus := r.FormValue("payment_status")
+	report, ok := reportForm(w, r, households)
+	if !ok {
+		return
+	}
+	report.UserID = user.ID
 
-	switch paymentStatus {
-	case "all", "paid", "unpaid":
-	default:
-		paymentStatus = "all"
+	if _, err := h.reportQueue.Enqueue(report); err != nil {
+		http.Error(w, "Failed to queue report", http.StatusInternalServerError)
+		return
 	}
 
-	from, err := time.Parse("2006-01-02", fromStr)
-	if err != nil {
-		http.Error(w, "Invalid start date", http.StatusBadRequest)
+	w.Header().Set("HX-Redirect", "/reports")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostGenerateHouseholdReport queues a report of every share in the household
+// in the period, with totals per member and the payments made towards those
+// shares.
+func (h *PostReportHandler) PostGenerateHouseholdReport(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	membership := middleware.GetMembership(r.Context())
+	if membership == nil || !membership.Can(store.PermHouseholdReports) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	to, err := time.Parse("2006-01-02", toStr)
+	households, err := h.householdStore.GetHouseholdsByUserID(user.ID)
 	if err != nil {
-		http.Error(w, "Invalid end date", http.StatusBadRequest)
+		http.Error(w, "Failed to load households", http.StatusInternalServerError)
 		return
 	}
 
-	if from.After(to) {
-		http.Error(w, "Start date must be before end date", http.StatusBadRequest)
+	i := slices.IndexFunc(households, func(h store.Household) bool { return h.ID == membership.HouseholdID })
+	if i < 0 {
+		http.Error(w, "Household not found", http.StatusNotFound)
 		return
 	}
 
-	to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	report, ok := reportForm(w, r, households[i:i+1])
+	if !ok {
+		return
+	}
+	report.UserID = user.ID
+	report.HouseholdID = &households[i].ID
 
-	report, err := h.reportStore.CreateReport(user.ID, from, to, paymentStatus)
-	if err != nil {
-		http.Error(w, "Failed to generate report", http.StatusInternalServerError)
+	if _, err := h.reportQueue.Enqueue(report); err != nil {
+		http.Error(w, "Failed to queue report", http.StatusInternalServerError)
 		return
 	}
 
-	_, err = GenerateReportPDF(report)
-	if err != nil {
-		http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+	w.Header().Set("HX-Redirect", "/households")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostDeleteReport deletes a report along with its file.
+func (h *PostReportHandler) PostDeleteReport(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
 		return
 	}
 
-	w.Header().Set("HX-Redirect", "/reports")
+	report, ok := h.managedReport(w, r, user.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.reportQueue.Delete(report); err != nil {
+		http.Error(w, "Failed to delete report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", reportsPage(report))
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostRegenerateReport queues a report to be generated again for the same
+// period and filter, from the expenses as they are now. It also retries a
+// report that failed for good.
+func (h *PostReportHandler) PostRegenerateReport(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	report, ok := h.managedReport(w, r, user.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.reportQueue.Regenerate(report); err != nil {
+		http.Error(w, "Failed to queue report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", reportsPage(report))
 	w.WriteHeader(http.StatusOK)
 }
+
+// managedReport loads the report in the URL, which userID must be allowed to
+// manage and which must not be pending. On failure it writes the error and
+// returns false.
+func (h *PostReportHandler) managedReport(w http.ResponseWriter, r *http.Request, userID uint) (store.Report, bool) {
+	reportID, err := strconv.ParseUint(chi.URLParam(r, "reportID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid report ID", http.StatusBadRequest)
+		return store.Report{}, false
+	}
+
+	report, err := h.reportStore.GetReportByID(uint(reportID))
+	if err != nil {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return store.Report{}, false
+	}
+
+	canView, canManage := reportAccess(h.membershipStore, report, userID)
+	if !canView {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return store.Report{}, false
+	}
+	if !canManage {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return store.Report{}, false
+	}
+
+	if report.Status.IsPending() {
+		w.WriteHeader(http.StatusConflict)
+		c := templAlerts.Error("Report is being generated", "Wait until the report is generated and try again.")
+		c.Render(r.Context(), w)
+		return store.Report{}, false
+	}
+
+	return report, true
+}
+
+// reportsPage is the page that lists report.
+func reportsPage(report store.Report) string {
+	if report.HouseholdID != nil {
+		return "/households"
+	}
+	return "/reports"
+}
+
+// reportForm reads the period, payment status, filter and file format of a
+// report form, where households are the households the filter can select
+// from. The end date is inclusive. On failure it writes the error and returns
+// false.
+func reportForm(w http.ResponseWriter, r *http.Request, households []store.Household) (store.Report, bool) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return store.Report{}, false
+	}
+
+	paymentStatus, format, err := parseReportOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return store.Report{}, false
+	}
+
+	from, err := time.Parse("2006-01-02", r.FormValue("period_start"))
+	if err != nil {
+		http.Error(w, "Invalid start date", http.StatusBadRequest)
+		return store.Report{}, false
+	}
+
+	to, err := time.Parse("2006-01-02", r.FormValue("period_end"))
+	if err != nil {
+		http.Error(w, "Invalid end date", http.StatusBadRequest)
+		return store.Report{}, false
+	}
+
+	if from.After(to) {
+		http.Error(w, "Start date must be before end date", http.StatusBadRequest)
+		return store.Report{}, false
+	}
+
+	filter, err := parseReportFilter(r, households)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Report failed", err.Error())
+		c.Render(r.Context(), w)
+		return store.Report{}, false
+	}
+
+	return store.Report{
+		PeriodStart:   from,
+		PeriodEnd:     to.AddDate(0, 0, 1).Add(-time.Nanosecond),
+		PaymentStatus: paymentStatus,
+		Filter:        filter,
+		Format:        format,
+	}, true
+}
+
+// parseReportOptions reads the payment status and file format of a parsed
+// report form. An unknown payment status covers all shares and the format
+// defaults to PDF.
+func parseReportOptions(r *http.Request) (string, store.ReportFormat, error) {
+	paymentStatus := r.FormValue("payment_status")
+
+	switch paymentStatus {
+	case "all", "paid", "unpaid":
+	default:
+		paymentStatus = "all"
+	}
+
+	format := store.ReportFormat(r.FormValue("format"))
+	if format == "" {
+		format = store.ReportPDF
+	}
+	if !format.IsValid() {
+		return "", "", errors.New("Invalid format")
+	}
+
+	return paymentStatus, format, nil
+}
+
+// sharePayments collects the payments made towards shares, oldest first, each
+// with its share so the PDF can name the expense.
+func sharePayments(shares []store.ExpenseShare) []store.Payment {
+	var payments []store.Payment
+	for _, s := range shares {
+		for _, p := range s.Payments {
+			p.ExpenseShare = s
+			payments = append(payments, p)
+		}
+	}
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].PaidOn.Before(payments[j].PaidOn)
+	})
+
+	return payments
+}
@@ -0,0 +1,92 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// ReportScheduler queues the reports of report schedules as they fall due,
+// for the ReportQueue to generate. It runs inside the server process;
+// occurrences missed while the server was down are caught up on the next
+// run.
+type ReportScheduler struct {
+	reportStore         store.ReportStore
+	reportScheduleStore store.ReportScheduleStore
+	interval            time.Duration
+}
+
+type ReportSchedulerParams struct {
+	ReportStore         store.ReportStore
+	ReportScheduleStore store.ReportScheduleStore
+	Interval            time.Duration
+}
+
+func NewReportScheduler(params ReportSchedulerParams) *ReportScheduler {
+	return &ReportScheduler{
+		reportStore:         params.ReportStore,
+		reportScheduleStore: params.ReportScheduleStore,
+		interval:            params.Interval,
+	}
+}
+
+// Run queues due reports right away and then every interval until ctx is
+// cancelled.
+func (s *ReportScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue queues the report of every occurrence due at or before now. A
+// schedule that fails is logged and retried from the same occurrence on the
+// next run, so no month is ever skipped.
+func (s *ReportScheduler) RunDue(ctx context.Context, now time.Time) {
+	due, err := s.reportScheduleStore.GetDueReportSchedules(now)
+	if err != nil {
+		log.Printf("cannot fetch due report schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range due {
+		if err := s.catchUp(ctx, schedule, now); err != nil {
+			log.Printf("cannot queue report of schedule %d: %v", schedule.ID, err)
+		}
+	}
+}
+
+// catchUp queues the reports of the due occurrences of schedule one at a
+// time, oldest first.
+func (s *ReportScheduler) catchUp(ctx context.Context, schedule store.ReportSchedule, now time.Time) error {
+	sched, err := schedule.Schedule()
+	if err != nil {
+		return err
+	}
+
+	for occurrence := schedule.NextRunOn; !occurrence.After(now); {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		next := sched.Next(occurrence)
+		if err := s.reportStore.QueueScheduledReport(schedule.Report(occurrence), next); err != nil {
+			return fmt.Errorf("occurrence %s: %w", occurrence.Format(time.DateOnly), err)
+		}
+
+		occurrence = next
+	}
+
+	return nil
+}
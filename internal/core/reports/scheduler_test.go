@@ -0,0 +1,62 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportScheduleReport(t *testing.T) {
+	schedule := store.ReportSchedule{ID: 4, UserID: 2, Day: 1, PaymentStatus: "all", Format: store.ReportCSV}
+
+	report := schedule.Report(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), report.PeriodStart)
+	require.Equal(t, time.Date(2025, time.December, 31, 23, 59, 59, 999999999, time.UTC), report.PeriodEnd)
+	require.Equal(t, uint(2), report.UserID)
+	require.Equal(t, store.ReportCSV, report.Format)
+	require.True(t, report.IsAutomatic())
+	require.Equal(t, uint(4), *report.ScheduleID)
+
+	report = schedule.Report(time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), report.PeriodStart)
+	require.Equal(t, time.Date(2026, time.February, 28, 23, 59, 59, 999999999, time.UTC), report.PeriodEnd)
+}
+
+// scheduledReportStore records the reports queued by a ReportScheduler.
+type scheduledReportStore struct {
+	store.ReportStore
+	queued    []store.Report
+	nextRunOn []time.Time
+}
+
+func (s *scheduledReportStore) QueueScheduledReport(report store.Report, nextRunOn time.Time) error {
+	s.queued = append(s.queued, report)
+	s.nextRunOn = append(s.nextRunOn, nextRunOn)
+	return nil
+}
+
+func TestReportSchedulerCatchUp(t *testing.T) {
+	reports := &scheduledReportStore{}
+	scheduler := NewReportScheduler(ReportSchedulerParams{ReportStore: reports})
+
+	schedule := store.ReportSchedule{
+		ID:        1,
+		Day:       1,
+		NextRunOn: time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}
+	now := time.Date(2026, time.October, 18, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, scheduler.catchUp(context.Background(), schedule, now))
+
+	require.Len(t, reports.queued, 3)
+	require.Equal(t, time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), reports.queued[0].PeriodStart)
+	require.Equal(t, time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC), reports.queued[2].PeriodStart)
+	require.Equal(t, []time.Time{
+		time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+	}, reports.nextRunOn)
+}
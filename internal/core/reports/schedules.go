@@ -0,0 +1,121 @@
+package reports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	templAlerts "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/templ/alerts"
+)
+
+type PostReportScheduleHandler struct {
+	reportScheduleStore store.ReportScheduleStore
+	householdStore      store.HouseholdStore
+}
+
+type PostReportScheduleHandlerParams struct {
+	ReportScheduleStore store.ReportScheduleStore
+	HouseholdStore      store.HouseholdStore
+}
+
+func NewPostReportScheduleHandler(params PostReportScheduleHandlerParams) *PostReportScheduleHandler {
+	return &PostReportScheduleHandler{
+		reportScheduleStore: params.ReportScheduleStore,
+		householdStore:      params.HouseholdStore,
+	}
+}
+
+// PostCreateReportSchedule schedules a personal report of the previous month
+// every month on the chosen day, with the payment status, filter and format
+// of the form. The first report is generated on the next such day.
+func (h *PostReportScheduleHandler) PostCreateReportSchedule(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	households, err := h.householdStore.GetHouseholdsByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to load households", http.StatusInternalServerError)
+		return
+	}
+
+	paymentStatus, format, err := parseReportOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseReportFilter(r, households)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Schedule failed", err.Error())
+		c.Render(r.Context(), w)
+		return
+	}
+
+	schedule := store.ReportSchedule{
+		UserID:        user.ID,
+		PaymentStatus: paymentStatus,
+		Filter:        filter,
+		Format:        format,
+	}
+	schedule.Day, _ = strconv.Atoi(r.FormValue("day"))
+
+	sched, err := schedule.Schedule()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		c := templAlerts.Error("Schedule failed", "Day of month must be between 1 and 31")
+		c.Render(r.Context(), w)
+		return
+	}
+
+	schedule.NextRunOn = sched.Next(time.Now())
+
+	if _, err := h.reportScheduleStore.CreateReportSchedule(schedule); err != nil {
+		http.Error(w, "Failed to create report schedule", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/reports")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PostDeleteReportSchedule stops a report schedule of the user. The reports
+// it already generated are kept.
+func (h *PostReportScheduleHandler) PostDeleteReportSchedule(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	scheduleID, err := strconv.ParseUint(chi.URLParam(r, "scheduleID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid report schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.reportScheduleStore.GetReportScheduleByID(uint(scheduleID))
+	if err != nil || schedule.UserID != user.ID {
+		http.Error(w, "Report schedule not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.reportScheduleStore.DeleteReportSchedule(schedule.ID); err != nil {
+		http.Error(w, "Failed to stop report schedule", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/reports")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,193 @@
+package reports
+
+import (
+	"strconv"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// table is one section of a report as rows of text, for the formats that lay
+// a report out as plain tables rather than pages. Amounts are plain decimals
+// with their currency in a column of its own, so that spreadsheets can sum
+// them.
+type table struct {
+	title   string
+	columns []column
+	rows    [][]string
+}
+
+type column struct {
+	title   string
+	numeric bool
+}
+
+// reportTables lays out the same sections as the PDF: a summary, the
+// subtotals per category and per household, or per member in a household
+// report, every share and the payment history.
+func reportTables(content Content) []table {
+	tables := []table{summaryTable(content), categoryTable(content.Shares)}
+
+	if content.Report.HouseholdID != nil {
+		tables = append(tables, memberTable(content.Shares))
+	} else {
+		tables = append(tables, householdTable(content.Shares))
+	}
+
+	return append(tables, shareTable(content), paymentTable(content))
+}
+
+// reportTitle names a report after its household, if it has one.
+func reportTitle(report store.Report) string {
+	if report.Household != nil {
+		return "Household report: " + report.Household.Name
+	}
+	return "Expense report"
+}
+
+func reportPeriod(report store.Report) string {
+	return report.PeriodStart.Format("02.01.2006") + " - " + report.PeriodEnd.Format("02.01.2006")
+}
+
+func summaryTable(content Content) table {
+	report := content.Report
+
+	return table{
+		title:   "Summary",
+		columns: []column{{title: "Field"}, {title: "Value"}},
+		rows: [][]string{
+			{"Report", reportTitle(report)},
+			{"Period", reportPeriod(report)},
+			{"Total expenses", report.TotalExpenses.String()},
+			{"Payment status", report.PaymentStatus},
+			{"Filters", content.Filters},
+			{"Generated", report.GenerationDate.Format("02.01.2006 15:04")},
+		},
+	}
+}
+
+func categoryTable(shares []store.ExpenseShare) table {
+	return subtotalTable("Expenses by category", "Category", categorySubtotals(shares))
+}
+
+func householdTable(shares []store.ExpenseShare) table {
+	return subtotalTable("Expenses by household", "Household", householdSubtotals(shares))
+}
+
+func subtotalTable(title, label string, totals []subtotal) table {
+	t := table{
+		title:   title,
+		columns: []column{{title: label}, {title: "Shares", numeric: true}, {title: "Amount", numeric: true}, {title: "Currency"}},
+	}
+
+	for _, s := range totals {
+		t.rows = append(t.rows, []string{s.label, strconv.Itoa(s.count), s.amount.Amount(), s.amount.Currency})
+	}
+
+	return t
+}
+
+func memberTable(shares []store.ExpenseShare) table {
+	t := table{
+		title: "Expenses by member",
+		columns: []column{
+			{title: "Member"},
+			{title: "Shares", numeric: true},
+			{title: "Total", numeric: true},
+			{title: "Settled", numeric: true},
+			{title: "Outstanding", numeric: true},
+			{title: "Currency"},
+		},
+	}
+
+	for _, m := range memberTotals(shares) {
+		t.rows = append(t.rows, []string{
+			m.username,
+			strconv.Itoa(m.count),
+			m.amount.Amount(),
+			m.settled.Amount(),
+			m.outstanding.Amount(),
+			m.amount.Currency,
+		})
+	}
+
+	return t
+}
+
+// shareTable lists every share of the report, with the member of each share
+// instead of its household in a household report.
+func shareTable(content Content) table {
+	byMember := content.Report.HouseholdID != nil
+
+	owner := "Household"
+	if byMember {
+		owner = "Member"
+	}
+
+	t := table{
+		title: "Expenses",
+		columns: []column{
+			{title: "Date"},
+			{title: owner},
+			{title: "Name"},
+			{title: "Category"},
+			{title: "Amount", numeric: true},
+			{title: "Currency"},
+			{title: "Paid"},
+		},
+	}
+
+	for _, s := range content.Shares {
+		name := s.Expense.Household.Name
+		if byMember {
+			name = s.User.Username
+		}
+
+		paid := "no"
+		if s.Paid {
+			paid = "yes"
+		}
+
+		t.rows = append(t.rows, []string{
+			s.Expense.CreatedOn.Format("2006-01-02"),
+			name,
+			s.Expense.Name,
+			s.Expense.Category.FullName(),
+			s.Amount.Amount(),
+			s.Amount.Currency,
+			paid,
+		})
+	}
+
+	return t
+}
+
+func paymentTable(content Content) table {
+	t := table{
+		title: "Payment history",
+		columns: []column{
+			{title: "Date"},
+			{title: "From"},
+			{title: "To"},
+			{title: "Expense"},
+			{title: "Category"},
+			{title: "Amount", numeric: true},
+			{title: "Currency"},
+			{title: "Method"},
+		},
+	}
+
+	for _, p := range content.Payments {
+		t.rows = append(t.rows, []string{
+			p.PaidOn.Format("2006-01-02"),
+			p.Payer.Username,
+			p.Payee.Username,
+			p.ExpenseShare.Expense.Name,
+			p.ExpenseShare.Expense.Category.FullName(),
+			p.Amount.Amount(),
+			p.Amount.Currency,
+			string(p.Method),
+		})
+	}
+
+	return t
+}
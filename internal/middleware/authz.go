@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// HouseholdResolver finds the household a request is about.
+type HouseholdResolver func(r *http.Request) (uint, error)
+
+// HouseholdFromURL reads the household ID from a route parameter.
+func HouseholdFromURL(param string) HouseholdResolver {
+	return func(r *http.Request) (uint, error) {
+		return parseID(chi.URLParam(r, param))
+	}
+}
+
+// HouseholdFromForm reads the household ID from a form field.
+func HouseholdFromForm(field string) HouseholdResolver {
+	return func(r *http.Request) (uint, error) {
+		return parseID(r.FormValue(field))
+	}
+}
+
+// HouseholdFromExpense looks up the household of the expense whose ID is in a
+// route parameter.
+func HouseholdFromExpense(expenseStore store.ExpenseStore, param string) HouseholdResolver {
+	return func(r *http.Request) (uint, error) {
+		expenseID, err := parseID(chi.URLParam(r, param))
+		if err != nil {
+			return 0, err
+		}
+
+		expense, err := expenseStore.GetExpenseByID(expenseID)
+		if err != nil {
+			return 0, err
+		}
+
+		return expense.HouseholdID, nil
+	}
+}
+
+func parseID(s string) (uint, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("invalid id")
+	}
+	return uint(id), nil
+}
+
+// AuthzMiddleware guards household-scoped routes with the memberships of the
+// logged-in user.
+type AuthzMiddleware struct {
+	membershipStore store.MembershipStore
+}
+
+func NewAuthzMiddleware(membershipStore store.MembershipStore) *AuthzMiddleware {
+	return &AuthzMiddleware{
+		membershipStore: membershipStore,
+	}
+}
+
+type membershipContextKeyType struct{}
+
+var membershipContextKey = membershipContextKeyType{}
+
+// RequireMember lets a request through only when the logged-in user is a
+// member of the household found by resolve and their role grants every one of
+// perms. Every other request, including one without a logged-in user or for a
+// household that cannot be found, is answered with 403 Forbidden. The
+// membership is added to the request context for the handler.
+func (m *AuthzMiddleware) RequireMember(resolve HouseholdResolver, perms ...store.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUser(r.Context())
+			if user == nil {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			householdID, err := resolve(r)
+			if err != nil {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			membership, err := m.membershipStore.GetMembership(householdID, user.ID)
+			if err != nil {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			for _, p := range perms {
+				if !membership.Can(p) {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), membershipContextKey, &membership)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin lets a request through only when the logged-in user is one of
+// adminIDs, the administrators who may change data shared by every household,
+// such as exchange rates. Every other request is answered with 403 Forbidden.
+func RequireAdmin(adminIDs []uint) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !IsAdmin(r.Context(), adminIDs) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsAdmin reports whether the logged-in user is one of adminIDs.
+func IsAdmin(ctx context.Context, adminIDs []uint) bool {
+	user := GetUser(ctx)
+	return user != nil && slices.Contains(adminIDs, user.ID)
+}
+
+// GetMembership returns the membership checked by RequireMember, or nil on
+// routes without it.
+func GetMembership(ctx context.Context) *store.Membership {
+	membership, ok := ctx.Value(membershipContextKey).(*store.Membership)
+
+	if !ok {
+		return nil
+	}
+
+	return membership
+}
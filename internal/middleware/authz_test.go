@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	storemock "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("record not found")
+
+func TestRequireMember(t *testing.T) {
+	membershipStore := &storemock.MembershipStoreMock{}
+	membershipStore.On("GetMembership", uint(1), uint(1)).Return(store.Membership{UserID: 1, HouseholdID: 1, Role: store.RoleOwner}, nil)
+	membershipStore.On("GetMembership", uint(1), uint(5)).Return(store.Membership{UserID: 5, HouseholdID: 1, Role: store.RoleViewer}, nil)
+	membershipStore.On("GetMembership", mock.Anything, mock.Anything).Return(store.Membership{}, errNotFound)
+
+	authz := NewAuthzMiddleware(membershipStore)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetMembership(r.Context()) == nil {
+			http.Error(w, "no membership in context", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := chi.NewRouter()
+	r.With(authz.RequireMember(HouseholdFromURL("id"))).Get("/household/{id}", ok)
+	r.With(authz.RequireMember(HouseholdFromURL("id"), store.PermAddExpense)).Post("/household/{id}", ok)
+	r.With(authz.RequireMember(HouseholdFromForm("household_id"))).Post("/form", ok)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		form   url.Values
+		userID uint
+		want   int
+	}{
+		{name: "member", method: http.MethodGet, path: "/household/1", userID: 1, want: http.StatusOK},
+		{name: "viewer", method: http.MethodGet, path: "/household/1", userID: 5, want: http.StatusOK},
+		{name: "outsider", method: http.MethodGet, path: "/household/1", userID: 3, want: http.StatusForbidden},
+		{name: "anonymous", method: http.MethodGet, path: "/household/1", want: http.StatusForbidden},
+		{name: "invalid id", method: http.MethodGet, path: "/household/abc", userID: 1, want: http.StatusForbidden},
+		{name: "with permission", method: http.MethodPost, path: "/household/1", userID: 1, want: http.StatusOK},
+		{name: "without permission", method: http.MethodPost, path: "/household/1", userID: 5, want: http.StatusForbidden},
+		{name: "household from form", method: http.MethodPost, path: "/form", form: url.Values{"household_id": {"1"}}, userID: 1, want: http.StatusOK},
+		{name: "household missing from form", method: http.MethodPost, path: "/form", userID: 1, want: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			if tc.userID != 0 {
+				ctx := context.WithValue(req.Context(), userContextKey, &store.User{ID: tc.userID})
+				req = req.WithContext(ctx)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	handler := RequireAdmin([]uint{4})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		name string
+		user *store.User
+		want int
+	}{
+		{name: "administrator", user: &store.User{ID: 4}, want: http.StatusOK},
+		{name: "other user", user: &store.User{ID: 1}, want: http.StatusForbidden},
+		{name: "anonymous", want: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/exchange-rates", nil)
+			if tc.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), userContextKey, tc.user))
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for amounts entered without an explicit currency and
+// for totals that span several currencies.
+const DefaultCurrency = "PLN"
+
+// RateScale is the fixed-point scale of exchange rates, so a rate of 4.3215 is
+// stored as 4321500.
+const RateScale = 1_000_000
+
+// Currencies lists the currency codes households and expenses can use.
+var Currencies = []string{"PLN", "EUR", "USD", "GBP", "CHF", "CZK", "SEK", "NOK", "DKK", "HUF", "JPY"}
+
+func IsSupported(currency string) bool {
+	return slices.Contains(Currencies, currency)
+}
+
+var (
+	ErrInvalidAmount = errors.New("invalid amount")
+	ErrPrecision     = errors.New("too many decimal places")
+)
+
+// Money is an amount in the minor units of its currency, e.g. grosze for PLN.
+// All arithmetic on amounts is done on Minor so that splitting and summing
+// never gains or loses a fraction of a unit.
+//
+// Money is stored by GORM as two columns when embedded with a prefix:
+//
+//	Amount money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+type Money struct {
+	Minor    int64  `json:"minor"`
+	Currency string `json:"currency" gorm:"size:3"`
+}
+
+func New(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// FromFloat converts a float amount in major units, rounding to the nearest
+// minor unit. It is meant for constants, legacy data and chart input, not for
+// parsing user input.
+func FromFloat(amount float64, currency string) Money {
+	return New(int64(math.Round(amount*math.Pow10(Exponent(currency)))), currency)
+}
+
+// Parse reads a non-negative decimal amount with at most as many decimal
+// places as the currency allows.
+func Parse(s string, currency string) (Money, error) {
+	minor, err := ParseDecimal(s, Exponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return New(minor, currency), nil
+}
+
+// ParseDecimal parses a non-negative decimal number with at most places
+// fractional digits and returns it scaled by 10^places. An empty string is
+// read as zero.
+func ParseDecimal(s string, places int) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > places {
+		return 0, fmt.Errorf("%w in %q", ErrPrecision, s)
+	}
+	fraction += strings.Repeat("0", places-len(fraction))
+
+	value, err := strconv.ParseUint(whole+fraction, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	return int64(value), nil
+}
+
+// ParseRate reads a positive exchange rate with at most six decimal places and
+// returns it scaled by RateScale.
+func ParseRate(s string) (int64, error) {
+	rate, err := ParseDecimal(s, 6)
+	if err != nil {
+		return 0, err
+	}
+	if rate == 0 {
+		return 0, fmt.Errorf("%w: rate must be positive", ErrInvalidAmount)
+	}
+	return rate, nil
+}
+
+// FormatRate formats a rate scaled by RateScale without trailing zeros.
+func FormatRate(rate int64) string {
+	s := fmt.Sprintf("%d.%06d", rate/RateScale, rate%RateScale)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// InvertRate returns the rate of the opposite direction, rounded to the
+// nearest RateScale unit.
+func InvertRate(rate int64) int64 {
+	return roundDiv(big.NewInt(RateScale*RateScale), big.NewInt(rate)).Int64()
+}
+
+// Exponent returns the number of minor-unit digits of currency.
+func Exponent(currency string) int {
+	switch currency {
+	case "JPY", "KRW", "HUF", "ISK":
+		return 0
+	}
+	return 2
+}
+
+// Float returns the amount in major units. Use it only where an approximate
+// value is fine, such as chart data.
+func (m Money) Float() float64 {
+	return float64(m.Minor) / math.Pow10(Exponent(m.Currency))
+}
+
+// Amount formats the amount in major units without the currency code, in the
+// same form Parse accepts.
+func (m Money) Amount() string {
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	exp := Exponent(m.Currency)
+	if exp == 0 {
+		return fmt.Sprintf("%s%d", sign, minor)
+	}
+
+	scale := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/scale, exp, minor%scale)
+}
+
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount()
+	}
+	return m.Amount() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// Add returns m + o. Both amounts must be in the same currency; a zero Money
+// without a currency takes the currency of the other operand.
+func (m Money) Add(o Money) Money {
+	return New(m.Minor+o.Minor, m.sameCurrency(o))
+}
+
+// Sub returns m - o under the same rules as Add.
+func (m Money) Sub(o Money) Money {
+	return New(m.Minor-o.Minor, m.sameCurrency(o))
+}
+
+func (m Money) sameCurrency(o Money) string {
+	switch {
+	case m.Currency == o.Currency:
+		return m.Currency
+	case m.Currency == "" && m.Minor == 0:
+		return o.Currency
+	case o.Currency == "" && o.Minor == 0:
+		return m.Currency
+	}
+	panic(fmt.Sprintf("money: currency mismatch %s and %s", m.Currency, o.Currency))
+}
+
+// Convert returns m expressed in currency, where rate is the price of one unit
+// of m.Currency in currency scaled by RateScale. The result is rounded to the
+// nearest minor unit, halves away from zero.
+func (m Money) Convert(rate int64, currency string) Money {
+	if m.Currency == currency {
+		return m
+	}
+
+	num := big.NewInt(m.Minor)
+	num.Mul(num, big.NewInt(rate))
+	num.Mul(num, pow10(Exponent(currency)))
+
+	den := big.NewInt(RateScale)
+	den.Mul(den, pow10(Exponent(m.Currency)))
+
+	return New(roundDiv(num, den).Int64(), currency)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundDiv divides num by a positive den, rounding halves away from zero.
+func roundDiv(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	r.Abs(r).Mul(r, big.NewInt(2))
+	if r.Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+
+	return q
+}
+
+// Allocate splits m proportionally to weights. Every part is first rounded
+// down; the minor units left over are then handed out one at a time, in slice
+// order, to the parts with a non-zero weight. Callers decide who receives the
+// remainder by ordering weights accordingly. Weights must not be negative. The
+// parts always add up to m; they are computed with big.Int, so large weights
+// cannot overflow.
+func (m Money) Allocate(weights []int64) []Money {
+	sum := new(big.Int)
+	for _, w := range weights {
+		sum.Add(sum, big.NewInt(w))
+	}
+
+	parts := make([]Money, len(weights))
+	for i := range parts {
+		parts[i].Currency = m.Currency
+	}
+
+	if sum.Sign() == 0 {
+		return parts
+	}
+
+	remainder := m.Minor
+	for i, w := range weights {
+		part := new(big.Int).Mul(big.NewInt(m.Minor), big.NewInt(w))
+		parts[i].Minor = part.Quo(part, sum).Int64()
+		remainder -= parts[i].Minor
+	}
+
+	for i := 0; remainder > 0; i = (i + 1) % len(weights) {
+		if weights[i] == 0 {
+			continue
+		}
+		parts[i].Minor++
+		remainder--
+	}
+
+	return parts
+}
+
+// Sum adds up amounts, which must share a currency. The sum of no amounts is
+// zero in currency.
+func Sum(currency string, amounts []Money) Money {
+	total := New(0, currency)
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
@@ -0,0 +1,124 @@
+package money
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{in: "10", currency: "PLN", want: 1000},
+		{in: "10.5", currency: "PLN", want: 1050},
+		{in: "10.05", currency: "PLN", want: 1005},
+		{in: " 0.01 ", currency: "PLN", want: 1},
+		{in: "", currency: "PLN", want: 0},
+		{in: "1500", currency: "JPY", want: 1500},
+		{in: "10.005", currency: "PLN", wantErr: ErrPrecision},
+		{in: "10.5", currency: "JPY", wantErr: ErrPrecision},
+		{in: "-1", currency: "PLN", wantErr: ErrInvalidAmount},
+		{in: "abc", currency: "PLN", wantErr: ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if tt.wantErr != nil {
+			require.ErrorIs(t, err, tt.wantErr, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		require.Equal(t, New(tt.want, tt.currency), got, tt.in)
+	}
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "12.34 PLN", New(1234, "PLN").String())
+	require.Equal(t, "0.05 EUR", New(5, "EUR").String())
+	require.Equal(t, "-1.50 PLN", New(-150, "PLN").String())
+	require.Equal(t, "1500 JPY", New(1500, "JPY").String())
+	require.Equal(t, "7.00", New(700, "").String())
+}
+
+func TestAdd_CurrencyMismatchPanics(t *testing.T) {
+	require.Equal(t, New(300, "PLN"), Money{}.Add(New(300, "PLN")))
+	require.Panics(t, func() { New(100, "PLN").Add(New(100, "EUR")) })
+}
+
+func TestAllocate_PartsAddUpToTotal(t *testing.T) {
+	property := func(total uint32, weights []uint16) bool {
+		if len(weights) == 0 {
+			return true
+		}
+
+		w := make([]int64, len(weights))
+		var sum int64
+		for i, v := range weights {
+			w[i] = int64(v)
+			sum += w[i]
+		}
+
+		amount := New(int64(total), "PLN")
+		parts := amount.Allocate(w)
+		if sum == 0 {
+			return Sum("PLN", parts).IsZero()
+		}
+
+		for i, p := range parts {
+			if p.Minor < 0 || (w[i] == 0 && p.Minor != 0) {
+				return false
+			}
+		}
+
+		return Sum("PLN", parts) == amount
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 5000}))
+}
+
+func TestAllocate_RemainderInSliceOrder(t *testing.T) {
+	pln := func(minor ...int64) []Money {
+		parts := make([]Money, len(minor))
+		for i, m := range minor {
+			parts[i] = New(m, "PLN")
+		}
+		return parts
+	}
+
+	require.Equal(t, pln(3334, 3333, 3333), New(10000, "PLN").Allocate([]int64{1, 1, 1}))
+	require.Equal(t, pln(0, 3334, 3333, 3333), New(10000, "PLN").Allocate([]int64{0, 1, 1, 1}))
+	require.Equal(t, pln(3334, 3334, 3333), New(10001, "PLN").Allocate([]int64{1, 1, 1}))
+}
+
+func TestAllocate_LargeWeightsDoNotOverflow(t *testing.T) {
+	parts := New(math.MaxInt64, "PLN").Allocate([]int64{math.MaxInt64, math.MaxInt64})
+	require.Equal(t, []Money{New(math.MaxInt64/2+1, "PLN"), New(math.MaxInt64/2, "PLN")}, parts)
+
+	parts = New(10000, "PLN").Allocate([]int64{math.MaxInt64, 1})
+	require.Equal(t, []Money{New(10000, "PLN"), New(0, "PLN")}, parts)
+}
+
+func TestConvert(t *testing.T) {
+	rate, err := ParseRate("4.3215")
+	require.NoError(t, err)
+	require.Equal(t, int64(4321500), rate)
+	require.Equal(t, "4.3215", FormatRate(rate))
+
+	require.Equal(t, New(4322, "PLN"), New(1000, "EUR").Convert(rate, "PLN"))
+	require.Equal(t, New(-4322, "PLN"), New(-1000, "EUR").Convert(rate, "PLN"))
+	require.Equal(t, New(1000, "EUR"), New(1000, "EUR").Convert(rate, "EUR"))
+
+	jpy, err := ParseRate("0.0265")
+	require.NoError(t, err)
+	require.Equal(t, New(3975, "PLN"), New(1500, "JPY").Convert(jpy, "PLN"))
+	require.Equal(t, New(1500, "JPY"), New(3975, "PLN").Convert(InvertRate(jpy), "JPY"))
+
+	_, err = ParseRate("0")
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
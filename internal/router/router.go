@@ -0,0 +1,203 @@
+// Package router registers every route of the app together with the
+// middleware that guards it, so that the access policy is defined in one place
+// and can be tested without the handlers behind it.
+package router
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	m "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// Handlers are the handlers of the routes, one per route.
+type Handlers struct {
+	GetIndex     http.HandlerFunc
+	GetHome      http.HandlerFunc
+	GetRegister  http.HandlerFunc
+	PostRegister http.HandlerFunc
+	GetLogin     http.HandlerFunc
+	PostLogin    http.HandlerFunc
+	PostLogout   http.HandlerFunc
+
+	GetHouseholds         http.HandlerFunc
+	GetHouseholdMembers   http.HandlerFunc
+	GetHouseholdExpenses  http.HandlerFunc
+	GetBalances           http.HandlerFunc
+	PostSettle            http.HandlerFunc
+	PostInvite            http.HandlerFunc
+	PostChangeRole        http.HandlerFunc
+	PostRemoveMember      http.HandlerFunc
+	PostTransferOwnership http.HandlerFunc
+	PostLeave             http.HandlerFunc
+	PostHousehold         http.HandlerFunc
+
+	GetInvitations        http.HandlerFunc
+	GetInvitation         http.HandlerFunc
+	PostAcceptInvitation  http.HandlerFunc
+	PostDeclineInvitation http.HandlerFunc
+
+	GetExpenses                http.HandlerFunc
+	GetExpensesChart           http.HandlerFunc
+	PostExpense                http.HandlerFunc
+	PostPayExpenseShare        http.HandlerFunc
+	GetEditExpense             http.HandlerFunc
+	PostEditExpense            http.HandlerFunc
+	PostDeleteExpense          http.HandlerFunc
+	GetRecurringExpenses       http.HandlerFunc
+	PostRecurringExpense       http.HandlerFunc
+	PostDeleteRecurringExpense http.HandlerFunc
+	GetImportExpenses          http.HandlerFunc
+	PostImportExpenses         http.HandlerFunc
+
+	GetBudgets        http.HandlerFunc
+	GetBudgetWarnings http.HandlerFunc
+	GetBudgetCheck    http.HandlerFunc
+	PostBudget        http.HandlerFunc
+	PostDeleteBudget  http.HandlerFunc
+
+	GetCategories      http.HandlerFunc
+	PostCategory       http.HandlerFunc
+	PostUpdateCategory http.HandlerFunc
+
+	ExportHousehold http.HandlerFunc
+	ExportUser      http.HandlerFunc
+
+	GetExchangeRates        http.HandlerFunc
+	PostExchangeRate        http.HandlerFunc
+	PostImportExchangeRates http.HandlerFunc
+
+	GetReports                  http.HandlerFunc
+	DownloadReport              http.HandlerFunc
+	GetReportStatus             http.HandlerFunc
+	PostGenerateReport          http.HandlerFunc
+	PostRegenerateReport        http.HandlerFunc
+	PostDeleteReport            http.HandlerFunc
+	PostCreateReportSchedule    http.HandlerFunc
+	PostDeleteReportSchedule    http.HandlerFunc
+	GetHouseholdReports         http.HandlerFunc
+	PostGenerateHouseholdReport http.HandlerFunc
+}
+
+type Params struct {
+	AuthMiddleware  *m.AuthMiddleware
+	MembershipStore store.MembershipStore
+	ExpenseStore    store.ExpenseStore
+	AdminIDs        []uint
+	StaticDir       string
+	Handlers        Handlers
+}
+
+// New returns a router with the static files under /static/ and every route
+// of the app, each behind the middleware that checks who may use it.
+func New(params Params) *chi.Mux {
+	r := chi.NewRouter()
+	h := params.Handlers
+
+	fileServer := http.FileServer(http.Dir(params.StaticDir))
+
+	r.Get("/static/*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000")
+		http.StripPrefix("/static/", fileServer).ServeHTTP(w, r)
+	}))
+
+	authz := m.NewAuthzMiddleware(params.MembershipStore)
+	householdMember := authz.RequireMember(m.HouseholdFromURL("id"))
+	householdManager := authz.RequireMember(m.HouseholdFromURL("id"), store.PermManageMembers)
+	householdOwner := authz.RequireMember(m.HouseholdFromURL("id"), store.PermTransferOwnership)
+	householdBudgeter := authz.RequireMember(m.HouseholdFromURL("id"), store.PermManageBudgets)
+	householdCategorizer := authz.RequireMember(m.HouseholdFromURL("id"), store.PermManageCategories)
+	householdExpenseAdder := authz.RequireMember(m.HouseholdFromURL("id"), store.PermAddExpense)
+	householdReporter := authz.RequireMember(m.HouseholdFromURL("id"), store.PermHouseholdReports)
+	householdSettler := authz.RequireMember(m.HouseholdFromURL("id"), store.PermMarkOthersPaid)
+	expenseMember := authz.RequireMember(m.HouseholdFromExpense(params.ExpenseStore, "id"))
+	admin := m.RequireAdmin(params.AdminIDs)
+
+	r.Group(func(r chi.Router) {
+		r.Use(
+			middleware.Logger,
+			params.AuthMiddleware.AddUserToContext,
+		)
+
+		//BASIC
+		r.Get("/", h.GetIndex)
+		r.Get("/home", h.GetHome)
+
+		//AUTH
+		r.Get("/register", h.GetRegister)
+		r.Post("/register", h.PostRegister)
+		r.Get("/login", h.GetLogin)
+		r.Post("/login", h.PostLogin)
+		r.Post("/logout", h.PostLogout)
+
+		//HOUSEHOLDS
+		r.Get("/households", h.GetHouseholds)
+		r.With(householdMember).Get("/household/{id}/members", h.GetHouseholdMembers)
+		r.With(householdMember).Get("/household/{id}/expenses", h.GetHouseholdExpenses)
+		r.With(householdMember).Get("/household/{id}/balances", h.GetBalances)
+		r.With(householdSettler).Post("/household/{id}/settle", h.PostSettle)
+		r.With(householdManager).Post("/household/{id}/invitations", h.PostInvite)
+		r.With(householdManager).Post("/household/{id}/members/{userID}/role", h.PostChangeRole)
+		r.With(householdManager).Post("/household/{id}/members/{userID}/remove", h.PostRemoveMember)
+		r.With(householdOwner).Post("/household/{id}/members/{userID}/transfer", h.PostTransferOwnership)
+		r.With(householdMember).Post("/household/{id}/leave", h.PostLeave)
+		r.Post("/household", h.PostHousehold)
+
+		//INVITATIONS
+		r.Get("/invitations", h.GetInvitations)
+		r.Get("/invitations/{token}", h.GetInvitation)
+		r.Post("/invitations/{token}/accept", h.PostAcceptInvitation)
+		r.Post("/invitations/{token}/decline", h.PostDeclineInvitation)
+
+		//EXPENSES
+		r.Get("/expenses", h.GetExpenses)
+		r.Get("/expenses/chart", h.GetExpensesChart)
+		r.With(authz.RequireMember(m.HouseholdFromForm("household_id"), store.PermAddExpense)).Post("/expense", h.PostExpense)
+		r.With(expenseMember).Post("/expense/{id}/pay", h.PostPayExpenseShare)
+		r.With(expenseMember).Get("/expense/{id}/edit", h.GetEditExpense)
+		r.With(expenseMember).Post("/expense/{id}/edit", h.PostEditExpense)
+		r.With(expenseMember).Post("/expense/{id}/delete", h.PostDeleteExpense)
+		r.With(householdMember).Get("/household/{id}/recurring", h.GetRecurringExpenses)
+		r.With(householdExpenseAdder).Post("/household/{id}/recurring", h.PostRecurringExpense)
+		r.With(householdMember).Post("/household/{id}/recurring/{recurringID}/delete", h.PostDeleteRecurringExpense)
+		r.With(householdExpenseAdder).Get("/household/{id}/import", h.GetImportExpenses)
+		r.With(householdExpenseAdder).Post("/household/{id}/import", h.PostImportExpenses)
+
+		//BUDGETS
+		r.With(householdMember).Get("/household/{id}/budgets", h.GetBudgets)
+		r.Get("/budgets/warnings", h.GetBudgetWarnings)
+		r.With(authz.RequireMember(m.HouseholdFromForm("household_id"))).Get("/budget/check", h.GetBudgetCheck)
+		r.With(householdBudgeter).Post("/household/{id}/budgets", h.PostBudget)
+		r.With(householdBudgeter).Post("/household/{id}/budgets/{budgetID}/delete", h.PostDeleteBudget)
+
+		//CATEGORIES
+		r.With(householdMember).Get("/household/{id}/categories", h.GetCategories)
+		r.With(householdCategorizer).Post("/household/{id}/categories", h.PostCategory)
+		r.With(householdCategorizer).Post("/household/{id}/categories/{categoryID}", h.PostUpdateCategory)
+
+		//EXPORTS
+		r.With(householdMember).Get("/household/{id}/export", h.ExportHousehold)
+		r.Get("/export", h.ExportUser)
+
+		//EXCHANGE RATES
+		r.Get("/exchange-rates", h.GetExchangeRates)
+		r.With(admin).Post("/exchange-rates", h.PostExchangeRate)
+		r.With(admin).Post("/exchange-rates/import", h.PostImportExchangeRates)
+
+		//REPORTS
+		r.Get("/reports", h.GetReports)
+		r.Get("/reports/files/{file}", h.DownloadReport)
+		r.Get("/reports/{reportID}/status", h.GetReportStatus)
+		r.Post("/report", h.PostGenerateReport)
+		r.Post("/reports/{reportID}/regenerate", h.PostRegenerateReport)
+		r.Post("/reports/{reportID}/delete", h.PostDeleteReport)
+		r.Post("/reports/schedules", h.PostCreateReportSchedule)
+		r.Post("/reports/schedules/{scheduleID}/delete", h.PostDeleteReportSchedule)
+		r.With(householdMember).Get("/household/{id}/reports", h.GetHouseholdReports)
+		r.With(householdReporter).Post("/household/{id}/report", h.PostGenerateHouseholdReport)
+	})
+
+	return r
+}
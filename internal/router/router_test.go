@@ -0,0 +1,212 @@
+package router
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	m "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/middleware"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	storemock "github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("record not found")
+
+const (
+	anonymous     uint = 0
+	owner         uint = 1
+	member        uint = 2
+	outsider      uint = 3
+	admin         uint = 4
+	viewer        uint = 5
+	administrator uint = 6
+)
+
+// newTestRouter returns the router of the app with a handler on every route
+// that only answers 200 OK, so that a request is refused only by the
+// middleware in front of it.
+func newTestRouter() http.Handler {
+	sessionStore := &storemock.SessionStoreMock{}
+	for _, id := range []uint{owner, member, outsider, admin, viewer, administrator} {
+		sessionStore.On("GetUserFromSession", "session", strconv.Itoa(int(id))).Return(&store.User{ID: id}, nil)
+	}
+
+	membershipStore := &storemock.MembershipStoreMock{}
+	membershipStore.On("GetMembership", uint(1), owner).Return(store.Membership{UserID: owner, HouseholdID: 1, Role: store.RoleOwner}, nil)
+	membershipStore.On("GetMembership", uint(1), member).Return(store.Membership{UserID: member, HouseholdID: 1, Role: store.RoleMember}, nil)
+	membershipStore.On("GetMembership", uint(1), admin).Return(store.Membership{UserID: admin, HouseholdID: 1, Role: store.RoleAdmin}, nil)
+	membershipStore.On("GetMembership", uint(1), viewer).Return(store.Membership{UserID: viewer, HouseholdID: 1, Role: store.RoleViewer}, nil)
+	membershipStore.On("GetMembership", mock.Anything, mock.Anything).Return(store.Membership{}, errNotFound)
+
+	expenseStore := &storemock.ExpenseStoreMock{}
+	expenseStore.On("GetExpenseByID", uint(10)).Return(store.Expense{ID: 10, HouseholdID: 1}, nil)
+	expenseStore.On("GetExpenseByID", mock.Anything).Return(store.Expense{}, errNotFound)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var handlers Handlers
+	fields := reflect.ValueOf(&handlers).Elem()
+	for i := range fields.NumField() {
+		fields.Field(i).Set(reflect.ValueOf(ok))
+	}
+
+	return New(Params{
+		AuthMiddleware:  m.NewAuthMiddleware(sessionStore, "session"),
+		MembershipStore: membershipStore,
+		ExpenseStore:    expenseStore,
+		AdminIDs:        []uint{administrator},
+		Handlers:        handlers,
+	})
+}
+
+func TestRoutes(t *testing.T) {
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		form   url.Values
+		userID uint
+		want   int
+	}{
+		{name: "members as owner", method: http.MethodGet, path: "/household/1/members", userID: owner, want: http.StatusOK},
+		{name: "members as member", method: http.MethodGet, path: "/household/1/members", userID: member, want: http.StatusOK},
+		{name: "members as viewer", method: http.MethodGet, path: "/household/1/members", userID: viewer, want: http.StatusOK},
+		{name: "members as outsider", method: http.MethodGet, path: "/household/1/members", userID: outsider, want: http.StatusForbidden},
+		{name: "members anonymous", method: http.MethodGet, path: "/household/1/members", userID: anonymous, want: http.StatusForbidden},
+		{name: "members of other household", method: http.MethodGet, path: "/household/2/members", userID: owner, want: http.StatusForbidden},
+		{name: "members invalid id", method: http.MethodGet, path: "/household/abc/members", userID: owner, want: http.StatusForbidden},
+
+		{name: "expenses as member", method: http.MethodGet, path: "/household/1/expenses", userID: member, want: http.StatusOK},
+		{name: "expenses as outsider", method: http.MethodGet, path: "/household/1/expenses", userID: outsider, want: http.StatusForbidden},
+		{name: "expenses anonymous", method: http.MethodGet, path: "/household/1/expenses", userID: anonymous, want: http.StatusForbidden},
+
+		{name: "balances as member", method: http.MethodGet, path: "/household/1/balances", userID: member, want: http.StatusOK},
+		{name: "balances as outsider", method: http.MethodGet, path: "/household/1/balances", userID: outsider, want: http.StatusForbidden},
+
+		{name: "settle as owner", method: http.MethodPost, path: "/household/1/settle", userID: owner, want: http.StatusOK},
+		{name: "settle as admin", method: http.MethodPost, path: "/household/1/settle", userID: admin, want: http.StatusOK},
+		{name: "settle as member", method: http.MethodPost, path: "/household/1/settle", userID: member, want: http.StatusForbidden},
+		{name: "settle as viewer", method: http.MethodPost, path: "/household/1/settle", userID: viewer, want: http.StatusForbidden},
+		{name: "settle as outsider", method: http.MethodPost, path: "/household/1/settle", userID: outsider, want: http.StatusForbidden},
+		{name: "settle anonymous", method: http.MethodPost, path: "/household/1/settle", userID: anonymous, want: http.StatusForbidden},
+
+		{name: "invite as owner", method: http.MethodPost, path: "/household/1/invitations", userID: owner, want: http.StatusOK},
+		{name: "invite as admin", method: http.MethodPost, path: "/household/1/invitations", userID: admin, want: http.StatusOK},
+		{name: "invite as member", method: http.MethodPost, path: "/household/1/invitations", userID: member, want: http.StatusForbidden},
+		{name: "invite as viewer", method: http.MethodPost, path: "/household/1/invitations", userID: viewer, want: http.StatusForbidden},
+		{name: "invite as outsider", method: http.MethodPost, path: "/household/1/invitations", userID: outsider, want: http.StatusForbidden},
+
+		{name: "change role as owner", method: http.MethodPost, path: "/household/1/members/2/role", userID: owner, want: http.StatusOK},
+		{name: "change role as admin", method: http.MethodPost, path: "/household/1/members/2/role", userID: admin, want: http.StatusOK},
+		{name: "change role as member", method: http.MethodPost, path: "/household/1/members/2/role", userID: member, want: http.StatusForbidden},
+
+		{name: "remove member as owner", method: http.MethodPost, path: "/household/1/members/2/remove", userID: owner, want: http.StatusOK},
+		{name: "remove member as admin", method: http.MethodPost, path: "/household/1/members/2/remove", userID: admin, want: http.StatusOK},
+		{name: "remove member as member", method: http.MethodPost, path: "/household/1/members/1/remove", userID: member, want: http.StatusForbidden},
+		{name: "remove member anonymous", method: http.MethodPost, path: "/household/1/members/2/remove", userID: anonymous, want: http.StatusForbidden},
+
+		{name: "transfer ownership as owner", method: http.MethodPost, path: "/household/1/members/2/transfer", userID: owner, want: http.StatusOK},
+		{name: "transfer ownership as admin", method: http.MethodPost, path: "/household/1/members/2/transfer", userID: admin, want: http.StatusForbidden},
+		{name: "transfer ownership as member", method: http.MethodPost, path: "/household/1/members/2/transfer", userID: member, want: http.StatusForbidden},
+
+		{name: "leave as member", method: http.MethodPost, path: "/household/1/leave", userID: member, want: http.StatusOK},
+		{name: "leave as outsider", method: http.MethodPost, path: "/household/1/leave", userID: outsider, want: http.StatusForbidden},
+
+		{name: "add expense as owner", method: http.MethodPost, path: "/expense", form: url.Values{"household_id": {"1"}}, userID: owner, want: http.StatusOK},
+		{name: "add expense as member", method: http.MethodPost, path: "/expense", form: url.Values{"household_id": {"1"}}, userID: member, want: http.StatusOK},
+		{name: "add expense as viewer", method: http.MethodPost, path: "/expense", form: url.Values{"household_id": {"1"}}, userID: viewer, want: http.StatusForbidden},
+		{name: "add expense as outsider", method: http.MethodPost, path: "/expense", form: url.Values{"household_id": {"1"}}, userID: outsider, want: http.StatusForbidden},
+		{name: "add expense without household", method: http.MethodPost, path: "/expense", userID: owner, want: http.StatusForbidden},
+
+		{name: "pay share as member", method: http.MethodPost, path: "/expense/10/pay", userID: member, want: http.StatusOK},
+		{name: "pay share as outsider", method: http.MethodPost, path: "/expense/10/pay", userID: outsider, want: http.StatusForbidden},
+		{name: "pay share anonymous", method: http.MethodPost, path: "/expense/10/pay", userID: anonymous, want: http.StatusForbidden},
+		{name: "pay share of missing expense", method: http.MethodPost, path: "/expense/99/pay", userID: owner, want: http.StatusForbidden},
+
+		{name: "edit form as member", method: http.MethodGet, path: "/expense/10/edit", userID: member, want: http.StatusOK},
+		{name: "edit form as outsider", method: http.MethodGet, path: "/expense/10/edit", userID: outsider, want: http.StatusForbidden},
+		{name: "edit expense as member", method: http.MethodPost, path: "/expense/10/edit", userID: member, want: http.StatusOK},
+		{name: "edit expense as outsider", method: http.MethodPost, path: "/expense/10/edit", userID: outsider, want: http.StatusForbidden},
+		{name: "delete expense as outsider", method: http.MethodPost, path: "/expense/10/delete", userID: outsider, want: http.StatusForbidden},
+		{name: "delete missing expense", method: http.MethodPost, path: "/expense/99/delete", userID: owner, want: http.StatusForbidden},
+
+		{name: "recurring as viewer", method: http.MethodGet, path: "/household/1/recurring", userID: viewer, want: http.StatusOK},
+		{name: "recurring as outsider", method: http.MethodGet, path: "/household/1/recurring", userID: outsider, want: http.StatusForbidden},
+		{name: "add recurring as member", method: http.MethodPost, path: "/household/1/recurring", userID: member, want: http.StatusOK},
+		{name: "add recurring as viewer", method: http.MethodPost, path: "/household/1/recurring", userID: viewer, want: http.StatusForbidden},
+		{name: "add recurring as outsider", method: http.MethodPost, path: "/household/1/recurring", userID: outsider, want: http.StatusForbidden},
+		{name: "stop recurring as member", method: http.MethodPost, path: "/household/1/recurring/7/delete", userID: member, want: http.StatusOK},
+		{name: "stop recurring as outsider", method: http.MethodPost, path: "/household/1/recurring/7/delete", userID: outsider, want: http.StatusForbidden},
+
+		{name: "budgets as viewer", method: http.MethodGet, path: "/household/1/budgets", userID: viewer, want: http.StatusOK},
+		{name: "budgets as outsider", method: http.MethodGet, path: "/household/1/budgets", userID: outsider, want: http.StatusForbidden},
+		{name: "budget check as member", method: http.MethodGet, path: "/budget/check?household_id=1", userID: member, want: http.StatusOK},
+		{name: "budget check as outsider", method: http.MethodGet, path: "/budget/check?household_id=1", userID: outsider, want: http.StatusForbidden},
+		{name: "add budget as owner", method: http.MethodPost, path: "/household/1/budgets", userID: owner, want: http.StatusOK},
+		{name: "add budget as admin", method: http.MethodPost, path: "/household/1/budgets", userID: admin, want: http.StatusOK},
+		{name: "add budget as member", method: http.MethodPost, path: "/household/1/budgets", userID: member, want: http.StatusForbidden},
+		{name: "add budget as viewer", method: http.MethodPost, path: "/household/1/budgets", userID: viewer, want: http.StatusForbidden},
+		{name: "delete budget as admin", method: http.MethodPost, path: "/household/1/budgets/3/delete", userID: admin, want: http.StatusOK},
+		{name: "delete budget as member", method: http.MethodPost, path: "/household/1/budgets/3/delete", userID: member, want: http.StatusForbidden},
+
+		{name: "categories as viewer", method: http.MethodGet, path: "/household/1/categories", userID: viewer, want: http.StatusOK},
+		{name: "categories as outsider", method: http.MethodGet, path: "/household/1/categories", userID: outsider, want: http.StatusForbidden},
+		{name: "add category as admin", method: http.MethodPost, path: "/household/1/categories", userID: admin, want: http.StatusOK},
+		{name: "add category as member", method: http.MethodPost, path: "/household/1/categories", userID: member, want: http.StatusForbidden},
+		{name: "archive category as owner", method: http.MethodPost, path: "/household/1/categories/4", userID: owner, want: http.StatusOK},
+		{name: "archive category as viewer", method: http.MethodPost, path: "/household/1/categories/4", userID: viewer, want: http.StatusForbidden},
+
+		{name: "import form as member", method: http.MethodGet, path: "/household/1/import", userID: member, want: http.StatusOK},
+		{name: "import form as viewer", method: http.MethodGet, path: "/household/1/import", userID: viewer, want: http.StatusForbidden},
+		{name: "import as member", method: http.MethodPost, path: "/household/1/import", userID: member, want: http.StatusOK},
+		{name: "import as viewer", method: http.MethodPost, path: "/household/1/import", userID: viewer, want: http.StatusForbidden},
+		{name: "import as outsider", method: http.MethodPost, path: "/household/1/import", userID: outsider, want: http.StatusForbidden},
+
+		{name: "export as viewer", method: http.MethodGet, path: "/household/1/export?format=csv", userID: viewer, want: http.StatusOK},
+		{name: "export as outsider", method: http.MethodGet, path: "/household/1/export", userID: outsider, want: http.StatusForbidden},
+		{name: "export as anonymous", method: http.MethodGet, path: "/household/1/export", userID: anonymous, want: http.StatusForbidden},
+
+		{name: "household reports as viewer", method: http.MethodGet, path: "/household/1/reports", userID: viewer, want: http.StatusOK},
+		{name: "household reports as outsider", method: http.MethodGet, path: "/household/1/reports", userID: outsider, want: http.StatusForbidden},
+		{name: "household report as member", method: http.MethodPost, path: "/household/1/report", userID: member, want: http.StatusOK},
+		{name: "household report as owner", method: http.MethodPost, path: "/household/1/report", userID: owner, want: http.StatusOK},
+		{name: "household report as viewer", method: http.MethodPost, path: "/household/1/report", userID: viewer, want: http.StatusForbidden},
+		{name: "household report as outsider", method: http.MethodPost, path: "/household/1/report", userID: outsider, want: http.StatusForbidden},
+
+		{name: "exchange rates as member", method: http.MethodGet, path: "/exchange-rates", userID: member, want: http.StatusOK},
+		{name: "add exchange rate as administrator", method: http.MethodPost, path: "/exchange-rates", userID: administrator, want: http.StatusOK},
+		{name: "add exchange rate as household owner", method: http.MethodPost, path: "/exchange-rates", userID: owner, want: http.StatusForbidden},
+		{name: "add exchange rate anonymous", method: http.MethodPost, path: "/exchange-rates", userID: anonymous, want: http.StatusForbidden},
+		{name: "import exchange rates as administrator", method: http.MethodPost, path: "/exchange-rates/import", userID: administrator, want: http.StatusOK},
+		{name: "import exchange rates as household owner", method: http.MethodPost, path: "/exchange-rates/import", userID: owner, want: http.StatusForbidden},
+	}
+
+	router := newTestRouter()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			if tc.userID != anonymous {
+				session := base64.StdEncoding.EncodeToString([]byte("session:" + strconv.Itoa(int(tc.userID))))
+				req.AddCookie(&http.Cookie{Name: "session", Value: session})
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
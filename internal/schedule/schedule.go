@@ -0,0 +1,238 @@
+// Package schedule computes when recurring expenses fall due. All times are
+// evaluated in UTC, so that occurrences stored in the database compare equal
+// to the ones computed again later.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a rule for a series of occurrences.
+type Schedule interface {
+	// Next returns the first occurrence strictly after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+}
+
+var ErrInvalid = errors.New("invalid schedule")
+
+// Monthly falls due at midnight on the given day of every month. Months that
+// are too short fall due on their last day instead.
+func Monthly(day int) (Schedule, error) {
+	if day < 1 || day > 31 {
+		return nil, fmt.Errorf("%w: day of month must be between 1 and 31", ErrInvalid)
+	}
+	return monthly{day: day}, nil
+}
+
+// Weekly falls due at midnight on the given weekday of every week.
+func Weekly(weekday time.Weekday) (Schedule, error) {
+	if weekday < time.Sunday || weekday > time.Saturday {
+		return nil, fmt.Errorf("%w: unknown weekday", ErrInvalid)
+	}
+	return weekly{weekday: weekday}, nil
+}
+
+// Yearly falls due at midnight on the given day every year. 29 February falls
+// due on 28 February in common years.
+func Yearly(month time.Month, day int) (Schedule, error) {
+	if month < time.January || month > time.December {
+		return nil, fmt.Errorf("%w: month must be between 1 and 12", ErrInvalid)
+	}
+	if day < 1 || day > daysIn(2024, month) {
+		return nil, fmt.Errorf("%w: %s has no day %d", ErrInvalid, month, day)
+	}
+	return yearly{month: month, day: day}, nil
+}
+
+type monthly struct {
+	day int
+}
+
+func (s monthly) Next(t time.Time) time.Time {
+	t = t.UTC()
+	for i := 0; i < 2; i++ {
+		year, month, _ := t.AddDate(0, i, 1-t.Day()).Date()
+		candidate := time.Date(year, month, min(s.day, daysIn(year, month)), 0, 0, 0, 0, time.UTC)
+		if candidate.After(t) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+type weekly struct {
+	weekday time.Weekday
+}
+
+func (s weekly) Next(t time.Time) time.Time {
+	day := midnight(t.UTC()).AddDate(0, 0, 1)
+	for day.Weekday() != s.weekday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+type yearly struct {
+	month time.Month
+	day   int
+}
+
+func (s yearly) Next(t time.Time) time.Time {
+	t = t.UTC()
+	for year := t.Year(); year <= t.Year()+1; year++ {
+		candidate := time.Date(year, s.month, min(s.day, daysIn(year, s.month)), 0, 0, 0, 0, time.UTC)
+		if candidate.After(t) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// Cron parses a standard five-field cron expression: minute, hour, day of
+// month, month and day of week. Fields accept *, numbers, ranges (1-5), lists
+// (1,15) and steps (*/2, 1-10/3). Day of week is 0-7 with both 0 and 7 being
+// Sunday. As in cron, when both day fields are restricted a day matching
+// either of them falls due.
+//
+// Minute and hour must each be a single value, so that an expression falls
+// due at most once a day, as every occurrence becomes an expense.
+func Cron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: cron expression needs 5 fields", ErrInvalid)
+	}
+
+	limits := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+	var sets [5]map[int]bool
+	for i, field := range fields {
+		set, err := parseField(field, limits[i][0], limits[i][1])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	if len(sets[0]) != 1 || len(sets[1]) != 1 {
+		return nil, fmt.Errorf("%w: cron expression must fall due at a single minute and hour, at most once a day", ErrInvalid)
+	}
+
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return cron{
+		minutes:     sets[0],
+		hours:       sets[1],
+		daysOfMonth: sets[2],
+		months:      sets[3],
+		daysOfWeek:  sets[4],
+		anyDOM:      fields[2] == "*",
+		anyDOW:      fields[4] == "*",
+	}, nil
+}
+
+type cron struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek map[int]bool
+	anyDOM, anyDOW                                  bool
+}
+
+// cronHorizon bounds the search for the next occurrence, so that expressions
+// that never match, like 30 February, end.
+const cronHorizon = 5 * 366 * 24 * time.Hour
+
+func (s cron) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	end := t.Add(cronHorizon)
+
+	for t.Before(end) {
+		switch {
+		case !s.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(t):
+			t = midnight(t).AddDate(0, 0, 1)
+		case !s.hours[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s cron) matchesDay(t time.Time) bool {
+	dom := s.daysOfMonth[t.Day()]
+	dow := s.daysOfWeek[int(t.Weekday())]
+
+	switch {
+	case s.anyDOM && s.anyDOW:
+		return true
+	case s.anyDOM:
+		return dow
+	case s.anyDOW:
+		return dom
+	}
+	return dom || dow
+}
+
+func parseField(field string, lo, hi int) (map[int]bool, error) {
+	set := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: bad step in %q", ErrInvalid, part)
+			}
+			step = n
+		}
+
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return nil, fmt.Errorf("%w: bad value %q", ErrInvalid, part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(b); err != nil {
+					return nil, fmt.Errorf("%w: bad value %q", ErrInvalid, part)
+				}
+			} else if hasStep {
+				to = hi
+			}
+		}
+
+		if from < lo || to > hi || from > to {
+			return nil, fmt.Errorf("%w: %q is out of range %d-%d", ErrInvalid, part, lo, hi)
+		}
+
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
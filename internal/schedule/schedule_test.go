@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	mustMonthly := func(day int) Schedule {
+		s, err := Monthly(day)
+		require.NoError(t, err)
+		return s
+	}
+	mustYearly := func(month time.Month, day int) Schedule {
+		s, err := Yearly(month, day)
+		require.NoError(t, err)
+		return s
+	}
+	mustCron := func(expr string) Schedule {
+		s, err := Cron(expr)
+		require.NoError(t, err)
+		return s
+	}
+	weekly, err := Weekly(time.Monday)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "monthly",
+			schedule: mustMonthly(10),
+			after:    date(2026, time.January, 5, 12, 0),
+			want:     []time.Time{date(2026, time.January, 10, 0, 0), date(2026, time.February, 10, 0, 0)},
+		},
+		{
+			name:     "monthly on the day itself moves to next month",
+			schedule: mustMonthly(10),
+			after:    date(2026, time.January, 10, 0, 0),
+			want:     []time.Time{date(2026, time.February, 10, 0, 0)},
+		},
+		{
+			name:     "monthly clamps to short months",
+			schedule: mustMonthly(31),
+			after:    date(2026, time.January, 31, 0, 0),
+			want:     []time.Time{date(2026, time.February, 28, 0, 0), date(2026, time.March, 31, 0, 0), date(2026, time.April, 30, 0, 0)},
+		},
+		{
+			name:     "weekly",
+			schedule: weekly,
+			after:    date(2026, time.October, 18, 9, 0), // a Sunday
+			want:     []time.Time{date(2026, time.October, 19, 0, 0), date(2026, time.October, 26, 0, 0)},
+		},
+		{
+			name:     "yearly leap day",
+			schedule: mustYearly(time.February, 29),
+			after:    date(2027, time.January, 1, 0, 0),
+			want:     []time.Time{date(2027, time.February, 28, 0, 0), date(2028, time.February, 29, 0, 0)},
+		},
+		{
+			name:     "cron every weekday at 9",
+			schedule: mustCron("0 9 * * 1-5"),
+			after:    date(2026, time.October, 16, 9, 0), // a Friday
+			want:     []time.Time{date(2026, time.October, 19, 9, 0), date(2026, time.October, 20, 9, 0)},
+		},
+		{
+			name:     "cron steps and lists",
+			schedule: mustCron("30 8 1-20/10 1,10 *"),
+			after:    date(2026, time.January, 1, 8, 0),
+			want:     []time.Time{date(2026, time.January, 1, 8, 30), date(2026, time.January, 11, 8, 30), date(2026, time.October, 1, 8, 30), date(2026, time.October, 11, 8, 30)},
+		},
+		{
+			name:     "cron day of month or day of week",
+			schedule: mustCron("0 0 15 * 0"),
+			after:    date(2026, time.October, 12, 0, 0),
+			want:     []time.Time{date(2026, time.October, 15, 0, 0), date(2026, time.October, 18, 0, 0)},
+		},
+		{
+			name:     "cron that never matches",
+			schedule: mustCron("0 0 30 2 *"),
+			after:    date(2026, time.January, 1, 0, 0),
+			want:     []time.Time{{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []time.Time
+			at := tt.after
+			for range tt.want {
+				at = tt.schedule.Next(at)
+				got = append(got, at)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	_, err := Monthly(0)
+	require.ErrorIs(t, err, ErrInvalid)
+
+	_, err = Yearly(time.February, 30)
+	require.ErrorIs(t, err, ErrInvalid)
+
+	for _, expr := range []string{"", "* * * *", "60 9 * * *", "0 9 0 * *", "*/0 9 * * *", "0 9 5-1 * *", "a 9 * * *"} {
+		_, err := Cron(expr)
+		require.ErrorIs(t, err, ErrInvalid, expr)
+	}
+}
+
+func TestCron_MoreThanDaily(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "*/30 9 * * *", "0 * * * *", "0 8,20 * * *", "0-1 9 * * *"} {
+		_, err := Cron(expr)
+		require.ErrorIs(t, err, ErrInvalid, expr)
+	}
+
+	_, err := Cron("0 9 * * *")
+	require.NoError(t, err)
+}
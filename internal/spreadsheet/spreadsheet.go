@@ -0,0 +1,34 @@
+// Package spreadsheet keeps text written to CSV files from being run as a
+// formula when the file is opened in a spreadsheet.
+package spreadsheet
+
+import (
+	"strconv"
+	"strings"
+)
+
+// formulaPrefixes are the characters spreadsheets start a formula with, or
+// skip before one.
+const formulaPrefixes = "=+-@\t\r"
+
+// Cell returns s so that a spreadsheet shows it as text: a cell starting with
+// a formula character is prefixed with a single quote. Numbers, negative ones
+// included, are returned as they are.
+func Cell(s string) string {
+	if s == "" || !strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
+
+// Row returns row with every cell passed through Cell.
+func Row(row []string) []string {
+	safe := make([]string, len(row))
+	for i, s := range row {
+		safe[i] = Cell(s)
+	}
+	return safe
+}
@@ -0,0 +1,30 @@
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCell(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"", ""},
+		{"Groceries", "Groceries"},
+		{"a=b", "a=b"},
+		{"=HYPERLINK(\"http://example.com\",\"x\")", "'=HYPERLINK(\"http://example.com\",\"x\")"},
+		{"+1+cmd", "'+1+cmd"},
+		{"-1+cmd", "'-1+cmd"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"-12.50", "-12.50"},
+		{"+3", "+3"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, Cell(tt.cell), tt.cell)
+	}
+}
@@ -27,7 +27,12 @@ func MustOpen(dbName string) *gorm.DB {
 		panic(err)
 	}
 
-	err = db.AutoMigrate(&store.User{}, &store.Session{}, &store.Household{}, &store.Membership{}, &store.Expense{}, &store.ExpenseShare{}, &store.Report{})
+	err = db.AutoMigrate(&store.User{}, &store.Session{}, &store.Household{}, &store.Membership{}, &store.Expense{}, &store.ExpensePayer{}, &store.ExpenseShare{}, &store.Report{}, &store.ExchangeRate{}, &store.Payment{}, &store.Invitation{}, &store.ExpenseAudit{}, &store.RecurringExpense{}, &store.RecurringExpenseSplit{}, &store.Budget{}, &store.Category{}, &store.ReportSchedule{})
+	if err != nil {
+		panic(err)
+	}
+
+	err = migrate(db)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,326 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+// schemaMigration records a data migration that has already been applied, so
+// each one runs exactly once per database.
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey"`
+	AppliedOn time.Time
+}
+
+type migration struct {
+	id  string
+	run func(tx *gorm.DB) error
+}
+
+// migrations run in order after AutoMigrate has brought the schema up to date.
+// Append new entries at the end and never reorder or rename existing ones.
+var migrations = []migration{
+	{id: "0001_rebalance_expense_shares", run: rebalanceExpenseShares},
+	{id: "0002_money_minor_units", run: convertAmountsToMinorUnits},
+	{id: "0003_base_currency", run: fillBaseCurrency},
+	{id: "0004_legacy_payments", run: recordLegacyPayments},
+	{id: "0005_expense_payers", run: recordCreatorsAsPayers},
+	{id: "0006_household_categories", run: seedHouseholdCategories},
+	{id: "0007_report_status", run: completeLegacyReports},
+	{id: "0008_report_format", run: fillReportFormat},
+	{id: "0009_report_finished_at", run: fillReportFinishedAt},
+}
+
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		var applied int64
+		if err := db.Model(&schemaMigration{}).Where("id = ?", m.id).Count(&applied).Error; err != nil {
+			return err
+		}
+
+		if applied > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.id, AppliedOn: time.Now()}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// legacyExpense and legacyExpenseShare read the float columns that amounts were
+// stored in before the switch to money.Money.
+type legacyExpense struct {
+	ID          uint
+	Amount      float64
+	CreatedByID uint
+}
+
+func (legacyExpense) TableName() string {
+	return "expenses"
+}
+
+type legacyExpenseShare struct {
+	ID     uint
+	UserID uint
+	Amount float64
+}
+
+func (legacyExpenseShare) TableName() string {
+	return "expense_shares"
+}
+
+// rebalanceExpenseShares fixes shares created by the old rounded-up equal split,
+// whose sum could exceed the expense amount by a few cents. The shares of every
+// unbalanced expense are re-allocated in proportion to their old amounts, with
+// the leftover cents going to the creator first and then by user ID.
+func rebalanceExpenseShares(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn("expense_shares", "amount") {
+		return nil
+	}
+
+	var expenses []legacyExpense
+	if err := tx.Find(&expenses).Error; err != nil {
+		return err
+	}
+
+	for _, expense := range expenses {
+		var shares []legacyExpenseShare
+		if err := tx.Where("expense_id = ?", expense.ID).Find(&shares).Error; err != nil {
+			return err
+		}
+
+		if len(shares) == 0 {
+			continue
+		}
+
+		sort.SliceStable(shares, func(i, j int) bool {
+			if (shares[i].UserID == expense.CreatedByID) != (shares[j].UserID == expense.CreatedByID) {
+				return shares[i].UserID == expense.CreatedByID
+			}
+			return shares[i].UserID < shares[j].UserID
+		})
+
+		weights := make([]int64, len(shares))
+		var sum int64
+		for i, share := range shares {
+			weights[i] = money.FromFloat(share.Amount, money.DefaultCurrency).Minor
+			sum += weights[i]
+		}
+
+		total := money.FromFloat(expense.Amount, money.DefaultCurrency)
+		if sum == total.Minor {
+			continue
+		}
+
+		parts := total.Allocate(weights)
+		for i, share := range shares {
+			err := tx.Model(&legacyExpenseShare{}).
+				Where("id = ?", share.ID).
+				Update("amount", parts[i].Float()).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertAmountsToMinorUnits moves every float amount column into the
+// <name>_minor and <name>_currency columns of money.Money and then drops the
+// float column. Rounding to the nearest minor unit is lossless because every
+// stored amount already had at most two decimal places.
+func convertAmountsToMinorUnits(tx *gorm.DB) error {
+	columns := []struct {
+		table  string
+		column string
+	}{
+		{table: "expenses", column: "amount"},
+		{table: "expense_shares", column: "amount"},
+		{table: "reports", column: "total_expenses"},
+	}
+
+	for _, c := range columns {
+		if !tx.Migrator().HasColumn(c.table, c.column) {
+			continue
+		}
+
+		err := tx.Exec(fmt.Sprintf(
+			"UPDATE %[1]s SET %[2]s_minor = CAST(ROUND(COALESCE(%[2]s, 0) * 100) AS INTEGER), %[2]s_currency = ?",
+			c.table, c.column,
+		), money.DefaultCurrency).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", c.table, c.column)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillBaseCurrency gives households created before multi-currency support the
+// default base currency, and records that their expenses were entered in it.
+func fillBaseCurrency(tx *gorm.DB) error {
+	err := tx.Exec(
+		"UPDATE households SET base_currency = ? WHERE base_currency IS NULL OR base_currency = ''",
+		money.DefaultCurrency,
+	).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(
+		"UPDATE expenses SET original_amount_minor = amount_minor, original_amount_currency = amount_currency " +
+			"WHERE original_amount_currency IS NULL OR original_amount_currency = ''",
+	).Error
+}
+
+// recordLegacyPayments gives every share that was marked paid before payments
+// were recorded a single payment of its full amount, so that the Paid flag
+// stays consistent with the payment history. The real payment date is unknown
+// and the expense date is used instead.
+func recordLegacyPayments(tx *gorm.DB) error {
+	return tx.Exec(
+		"INSERT INTO payments (expense_share_id, payer_id, payee_id, amount_minor, amount_currency, paid_on, method, note) "+
+			"SELECT s.id, s.user_id, e.created_by_id, s.amount_minor, s.amount_currency, e.created_on, ?, ? "+
+			"FROM expense_shares s JOIN expenses e ON e.id = s.expense_id "+
+			"WHERE s.paid = ? AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.expense_share_id = s.id)",
+		store.PaymentOther, "Recorded before payment history", true,
+	).Error
+}
+
+// recordCreatorsAsPayers makes whoever entered an expense before payers were
+// recorded its only payer, which is what balances assumed until then. Their
+// own share is covered by that payment; every other share covers nothing.
+func recordCreatorsAsPayers(tx *gorm.DB) error {
+	err := tx.Exec(
+		"INSERT INTO expense_payers (expense_id, user_id, amount_minor, amount_currency) " +
+			"SELECT e.id, e.created_by_id, e.amount_minor, e.amount_currency FROM expenses e " +
+			"WHERE NOT EXISTS (SELECT 1 FROM expense_payers p WHERE p.expense_id = e.id)",
+	).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Exec(
+		"UPDATE expense_shares SET covered_minor = 0, covered_currency = amount_currency " +
+			"WHERE covered_currency IS NULL OR covered_currency = ''",
+	).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(
+		"UPDATE expense_shares SET covered_minor = amount_minor, paid = ? "+
+			"WHERE user_id = (SELECT e.created_by_id FROM expenses e WHERE e.id = expense_shares.expense_id)",
+		true,
+	).Error
+}
+
+// seedHouseholdCategories gives every household the default categories and
+// points expenses, recurring expenses and budgets at the category named by
+// their old fixed category, then drops the old column. Rows whose category is
+// unknown end up in Other.
+func seedHouseholdCategories(tx *gorm.DB) error {
+	var householdIDs []uint
+	err := tx.Table("households").
+		Where("NOT EXISTS (SELECT 1 FROM categories c WHERE c.household_id = households.id)").
+		Pluck("id", &householdIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, householdID := range householdIDs {
+		categories := make([]store.Category, len(store.DefaultCategories))
+		for i, c := range store.DefaultCategories {
+			c.HouseholdID = householdID
+			categories[i] = c
+		}
+
+		if err := tx.Create(&categories).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"expenses", "recurring_expenses", "budgets"} {
+		if !tx.Migrator().HasColumn(table, "category") {
+			continue
+		}
+
+		err := tx.Exec(fmt.Sprintf(
+			"UPDATE %[1]s SET category_id = (SELECT c.id FROM categories c "+
+				"WHERE c.household_id = %[1]s.household_id AND c.parent_id IS NULL AND lower(c.name) = lower(%[1]s.category)) "+
+				"WHERE category_id IS NULL OR category_id = 0",
+			table,
+		)).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(fmt.Sprintf(
+			"UPDATE %[1]s SET category_id = (SELECT c.id FROM categories c "+
+				"WHERE c.household_id = %[1]s.household_id AND c.parent_id IS NULL AND c.name = ?) "+
+				"WHERE category_id IS NULL OR category_id = 0",
+			table,
+		), "Other").Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN category", table)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completeLegacyReports marks reports generated before the report queue as
+// done, as their PDF was written while they were requested.
+func completeLegacyReports(tx *gorm.DB) error {
+	return tx.Exec(
+		"UPDATE reports SET status = ?, attempts = 1 WHERE status IS NULL OR status = ''",
+		store.ReportDone,
+	).Error
+}
+
+// fillReportFormat records that reports generated before other formats were
+// supported are PDFs.
+func fillReportFormat(tx *gorm.DB) error {
+	return tx.Exec(
+		"UPDATE reports SET format = ? WHERE format IS NULL OR format = ''",
+		store.ReportPDF,
+	).Error
+}
+
+// fillReportFinishedAt records when the reports generated or failed before it
+// was recorded finished. Their generation date is the closest known time.
+func fillReportFinishedAt(tx *gorm.DB) error {
+	return tx.Exec(
+		"UPDATE reports SET finished_at = generation_date WHERE finished_at IS NULL AND status IN (?, ?)",
+		store.ReportDone, store.ReportFailed,
+	).Error
+}
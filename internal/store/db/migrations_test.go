@@ -0,0 +1,199 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type legacyExpenseRow struct {
+	ID          uint
+	Name        string
+	Amount      float64
+	CreatedOn   time.Time
+	CreatedByID uint
+	HouseholdID uint
+}
+
+func (legacyExpenseRow) TableName() string {
+	return "expenses"
+}
+
+type legacyExpenseShareRow struct {
+	ID        uint
+	ExpenseID uint
+	UserID    uint
+	Amount    float64
+	Paid      bool
+}
+
+func (legacyExpenseShareRow) TableName() string {
+	return "expense_shares"
+}
+
+func TestMigrate_LegacyFloatAmounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	legacy, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, legacy.AutoMigrate(&legacyExpenseRow{}, &legacyExpenseShareRow{}))
+
+	expense := legacyExpenseRow{Name: "Groceries", Amount: 100, CreatedOn: time.Now(), CreatedByID: 2}
+	require.NoError(t, legacy.Create(&expense).Error)
+
+	for _, userID := range []uint{1, 2, 3} {
+		share := legacyExpenseShareRow{ExpenseID: expense.ID, UserID: userID, Amount: 33.34, Paid: userID == 3}
+		require.NoError(t, legacy.Create(&share).Error)
+	}
+
+	rent := legacyExpenseRow{Name: "Rent", Amount: 2499.99, CreatedOn: time.Now(), CreatedByID: 1}
+	require.NoError(t, legacy.Create(&rent).Error)
+
+	db := MustOpen(path)
+
+	require.False(t, db.Migrator().HasColumn("expenses", "amount"))
+	require.False(t, db.Migrator().HasColumn("expense_shares", "amount"))
+
+	var expenses []store.Expense
+	require.NoError(t, db.Order("id").Find(&expenses).Error)
+	require.Len(t, expenses, 2)
+	require.Equal(t, money.New(10000, "PLN"), expenses[0].Amount)
+	require.Equal(t, money.New(249999, "PLN"), expenses[1].Amount)
+
+	var shares []store.ExpenseShare
+	require.NoError(t, db.Order("user_id").Find(&shares).Error)
+	require.Len(t, shares, 3)
+	require.Equal(t, money.New(3333, "PLN"), shares[0].Amount)
+	require.Equal(t, money.New(3334, "PLN"), shares[1].Amount)
+	require.Equal(t, money.New(3333, "PLN"), shares[2].Amount)
+
+	var payments []store.Payment
+	require.NoError(t, db.Find(&payments).Error)
+	require.Len(t, payments, 1)
+	require.Equal(t, shares[2].ID, payments[0].ExpenseShareID)
+	require.Equal(t, uint(3), payments[0].PayerID)
+	require.Equal(t, uint(2), payments[0].PayeeID)
+	require.Equal(t, money.New(3333, "PLN"), payments[0].Amount)
+
+	var payers []store.ExpensePayer
+	require.NoError(t, db.Order("expense_id").Find(&payers).Error)
+	require.Len(t, payers, 2)
+	require.Equal(t, uint(2), payers[0].UserID)
+	require.Equal(t, money.New(10000, "PLN"), payers[0].Amount)
+	require.Equal(t, uint(1), payers[1].UserID)
+	require.Equal(t, money.New(249999, "PLN"), payers[1].Amount)
+
+	require.Equal(t, money.New(0, "PLN"), shares[0].Covered)
+	require.Equal(t, money.New(3334, "PLN"), shares[1].Covered)
+	require.True(t, shares[1].Paid)
+}
+
+type legacyHouseholdRow struct {
+	ID   uint
+	Name string
+}
+
+func (legacyHouseholdRow) TableName() string {
+	return "households"
+}
+
+type legacyCategoryExpenseRow struct {
+	ID          uint
+	Name        string
+	Category    string
+	HouseholdID uint
+}
+
+func (legacyCategoryExpenseRow) TableName() string {
+	return "expenses"
+}
+
+type legacyBudgetRow struct {
+	ID          uint
+	HouseholdID uint
+	Category    string
+}
+
+func (legacyBudgetRow) TableName() string {
+	return "budgets"
+}
+
+func TestMigrate_LegacyCategories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	legacy, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, legacy.AutoMigrate(&legacyHouseholdRow{}, &legacyCategoryExpenseRow{}, &legacyBudgetRow{}))
+
+	for _, name := range []string{"Flat", "Cottage"} {
+		require.NoError(t, legacy.Create(&legacyHouseholdRow{Name: name}).Error)
+	}
+
+	require.NoError(t, legacy.Create(&legacyCategoryExpenseRow{Name: "Groceries", Category: "food", HouseholdID: 1}).Error)
+	require.NoError(t, legacy.Create(&legacyCategoryExpenseRow{Name: "Firewood", Category: "utilities", HouseholdID: 2}).Error)
+	require.NoError(t, legacy.Create(&legacyCategoryExpenseRow{Name: "Mystery", Category: "", HouseholdID: 2}).Error)
+	require.NoError(t, legacy.Create(&legacyBudgetRow{HouseholdID: 2, Category: "rent"}).Error)
+
+	db := MustOpen(path)
+
+	require.False(t, db.Migrator().HasColumn("expenses", "category"))
+	require.False(t, db.Migrator().HasColumn("budgets", "category"))
+
+	var categories []store.Category
+	require.NoError(t, db.Where("household_id = ?", 2).Order("id").Find(&categories).Error)
+	require.Len(t, categories, len(store.DefaultCategories))
+
+	byName := map[string]uint{}
+	for _, c := range categories {
+		byName[c.Name] = c.ID
+	}
+
+	var expenses []store.Expense
+	require.NoError(t, db.Preload("Category").Order("id").Find(&expenses).Error)
+	require.Len(t, expenses, 3)
+	require.Equal(t, "Food", expenses[0].Category.Name)
+	require.Equal(t, uint(1), expenses[0].Category.HouseholdID)
+	require.Equal(t, byName["Utilities"], expenses[1].CategoryID)
+	require.Equal(t, byName["Other"], expenses[2].CategoryID)
+
+	var budget store.Budget
+	require.NoError(t, db.First(&budget).Error)
+	require.Equal(t, byName["Rent"], budget.CategoryID)
+}
+
+type legacyReportRow struct {
+	ID             uint
+	UserID         uint
+	FileName       string
+	GenerationDate time.Time
+}
+
+func (legacyReportRow) TableName() string {
+	return "reports"
+}
+
+func TestMigrate_LegacyReports(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	legacy, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, legacy.AutoMigrate(&legacyReportRow{}))
+	generatedOn := time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, legacy.Create(&legacyReportRow{UserID: 1, FileName: "report_1_1.pdf", GenerationDate: generatedOn}).Error)
+
+	db := MustOpen(path)
+
+	var report store.Report
+	require.NoError(t, db.First(&report).Error)
+	require.Equal(t, store.ReportDone, report.Status)
+	require.Equal(t, 1, report.Attempts)
+	require.Equal(t, store.ReportPDF, report.Format)
+	require.True(t, generatedOn.Equal(*report.FinishedAt))
+	require.False(t, report.IsAutomatic())
+}
@@ -0,0 +1,64 @@
+package dbstore
+
+import (
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+type BudgetStore struct {
+	db *gorm.DB
+}
+
+type NewBudgetStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewBudgetStore(params NewBudgetStoreParams) *BudgetStore {
+	return &BudgetStore{
+		db: params.DB,
+	}
+}
+
+func (s *BudgetStore) CreateBudget(budget store.Budget) (uint, error) {
+	err := s.db.Omit("Household", "Category", "CreatedBy").Create(&budget).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return budget.ID, nil
+}
+
+func (s *BudgetStore) GetBudgetByID(budgetID uint) (store.Budget, error) {
+	var budget store.Budget
+	err := s.db.First(&budget, budgetID).Error
+	return budget, err
+}
+
+func (s *BudgetStore) GetBudgetsByHouseholdID(householdID uint) ([]store.Budget, error) {
+	var budgets []store.Budget
+	err := s.db.
+		Where("household_id = ?", householdID).
+		Preload("Household").
+		Preload("Category.Parent").
+		Order("id").
+		Find(&budgets).Error
+	return budgets, err
+}
+
+// GetBudgetsByUserID returns the budgets of every household the user is a
+// member of.
+func (s *BudgetStore) GetBudgetsByUserID(userID uint) ([]store.Budget, error) {
+	var budgets []store.Budget
+	err := s.db.
+		Joins("JOIN memberships ON memberships.household_id = budgets.household_id").
+		Where("memberships.user_id = ?", userID).
+		Preload("Household").
+		Preload("Category.Parent").
+		Order("budgets.household_id, budgets.id").
+		Find(&budgets).Error
+	return budgets, err
+}
+
+func (s *BudgetStore) DeleteBudget(budgetID uint) error {
+	return s.db.Delete(&store.Budget{}, budgetID).Error
+}
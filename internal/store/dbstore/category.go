@@ -0,0 +1,72 @@
+package dbstore
+
+import (
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+type CategoryStore struct {
+	db *gorm.DB
+}
+
+type NewCategoryStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewCategoryStore(params NewCategoryStoreParams) *CategoryStore {
+	return &CategoryStore{
+		db: params.DB,
+	}
+}
+
+func (s *CategoryStore) CreateCategory(category store.Category) (uint, error) {
+	err := s.db.Omit("Parent").Create(&category).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return category.ID, nil
+}
+
+func (s *CategoryStore) GetCategoryByID(categoryID uint) (store.Category, error) {
+	var category store.Category
+	err := s.db.Preload("Parent").First(&category, categoryID).Error
+	return category, err
+}
+
+// GetCategoriesByHouseholdID returns every category of the household,
+// archived ones included, each top-level category followed by its
+// subcategories.
+func (s *CategoryStore) GetCategoriesByHouseholdID(householdID uint) ([]store.Category, error) {
+	var categories []store.Category
+	err := s.db.
+		Where("household_id = ?", householdID).
+		Preload("Parent").
+		Order("COALESCE(parent_id, id), parent_id IS NOT NULL, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// UpdateCategory saves the name, colour, parent and archived flag of
+// category.
+func (s *CategoryStore) UpdateCategory(category store.Category) error {
+	return s.db.Model(&store.Category{}).
+		Where("id = ?", category.ID).
+		Updates(map[string]any{
+			"name":      category.Name,
+			"colour":    category.Colour,
+			"parent_id": category.ParentID,
+			"archived":  category.Archived,
+		}).Error
+}
+
+// seedCategories gives a household the default categories.
+func seedCategories(tx *gorm.DB, householdID uint) error {
+	categories := make([]store.Category, len(store.DefaultCategories))
+	for i, c := range store.DefaultCategories {
+		c.HouseholdID = householdID
+		categories[i] = c
+	}
+
+	return tx.Create(&categories).Error
+}
@@ -0,0 +1,80 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateStore struct {
+	db *gorm.DB
+}
+
+type NewExchangeRateStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewExchangeRateStore(params NewExchangeRateStoreParams) *ExchangeRateStore {
+	return &ExchangeRateStore{
+		db: params.DB,
+	}
+}
+
+// SaveExchangeRates stores rates in one transaction. A rate for a pair and day
+// that already exists is overwritten, so re-importing a file is safe.
+func (s *ExchangeRateStore) SaveExchangeRates(rates []store.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_currency"}, {Name: "to_currency"}, {Name: "valid_on"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate"}),
+	}).Create(&rates).Error
+}
+
+func (s *ExchangeRateStore) GetExchangeRates() ([]store.ExchangeRate, error) {
+	var rates []store.ExchangeRate
+	err := s.db.Order("valid_on desc, from_currency, to_currency").Find(&rates).Error
+	return rates, err
+}
+
+// Convert expresses amount in currency using the latest rate valid on the
+// given day. A rate recorded only for the opposite direction is inverted.
+func (s *ExchangeRateStore) Convert(amount money.Money, currency string, on time.Time) (money.Money, error) {
+	if amount.Currency == currency {
+		return amount, nil
+	}
+
+	rate, err := s.latestRate(amount.Currency, currency, on)
+	if err == nil {
+		return amount.Convert(rate.Rate, currency), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return money.Money{}, err
+	}
+
+	rate, err = s.latestRate(currency, amount.Currency, on)
+	if err == nil {
+		return amount.Convert(money.InvertRate(rate.Rate), currency), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return money.Money{}, err
+	}
+
+	return money.Money{}, fmt.Errorf("%w from %s to %s on %s", store.ErrNoExchangeRate, amount.Currency, currency, on.Format("2006-01-02"))
+}
+
+func (s *ExchangeRateStore) latestRate(from, to string, on time.Time) (store.ExchangeRate, error) {
+	var rate store.ExchangeRate
+	err := s.db.
+		Where("from_currency = ? AND to_currency = ? AND valid_on <= ?", from, to, on.UTC()).
+		Order("valid_on desc").
+		First(&rate).Error
+	return rate, err
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"gorm.io/gorm"
 )
@@ -22,17 +23,30 @@ func NewExpenseStore(params NewExpenseStoreParams) *ExpenseStore {
 	}
 }
 
-func (s *ExpenseStore) CreateExpense(name string, amount float64, category store.ExpenseCategory, createdOn time.Time, householdID, createdByID uint) (uint, error) {
+// CreateExpense saves the expense together with who paid for it.
+func (s *ExpenseStore) CreateExpense(name string, amount money.Money, originalAmount money.Money, categoryID uint, splitMode store.SplitMode, createdOn time.Time, householdID, createdByID uint, payers []store.ExpensePayer) (uint, error) {
 	expense := store.Expense{
-		Name:        name,
-		Amount:      amount,
-		Category:    category,
-		CreatedOn:   createdOn,
-		HouseholdID: householdID,
-		CreatedByID: createdByID,
+		Name:           name,
+		Amount:         amount,
+		OriginalAmount: originalAmount,
+		CategoryID:     categoryID,
+		SplitMode:      splitMode,
+		CreatedOn:      createdOn,
+		HouseholdID:    householdID,
+		CreatedByID:    createdByID,
 	}
 
-	err := s.db.Create(&expense).Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&expense).Error; err != nil {
+			return err
+		}
+
+		if err := createPayers(tx, expense.ID, payers); err != nil {
+			return err
+		}
+
+		return recordAudit(tx, expense, createdByID, store.AuditCreated, expense.Amount.String())
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -40,6 +54,19 @@ func (s *ExpenseStore) CreateExpense(name string, amount float64, category store
 	return expense.ID, nil
 }
 
+// ImportExpenses saves every imported expense with its payers and shares in a
+// single transaction, so a failing row leaves nothing behind.
+func (s *ExpenseStore) ImportExpenses(expenses []store.ImportedExpense) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, e := range expenses {
+			if err := createExpenseWithShares(tx, e.Expense, e.Shares, "imported, "+e.Expense.Amount.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *ExpenseStore) NameExists(name string) (bool, error) {
 	var expense store.Expense
 	err := s.db.Select("id").Where("name = ?", name).First(&expense).Error
@@ -55,11 +82,176 @@ func (s *ExpenseStore) NameExists(name string) (bool, error) {
 	return true, err
 }
 
+func (s *ExpenseStore) GetExpenseByID(expenseID uint) (store.Expense, error) {
+	var expense store.Expense
+	err := s.db.
+		Preload("Category.Parent").
+		Preload("Payers", orderByUser).
+		Preload("Payers.User").
+		First(&expense, expenseID).Error
+	return expense, err
+}
+
 func (s *ExpenseStore) GetExpensesByHouseholdID(householdID uint) ([]store.Expense, error) {
 	var expenses []store.Expense
 	err := s.db.
 		Where("household_id = ?", householdID).
 		Preload("CreatedBy").
+		Preload("Category.Parent").
+		Preload("Payers", orderByUser).
+		Preload("Payers.User").
 		Find(&expenses).Error
 	return expenses, err
 }
+
+// UpdateExpense saves the edited fields and payers of expense and the new
+// amounts of shares in one transaction, then recomputes the Paid flag of every
+// share. Shares that are not in shares keep their amounts.
+func (s *ExpenseStore) UpdateExpense(expense store.Expense, shares []store.ExpenseShare, editedByID uint, details string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&store.Expense{}).
+			Where("id = ?", expense.ID).
+			Updates(map[string]any{
+				"name":                     expense.Name,
+				"amount_minor":             expense.Amount.Minor,
+				"amount_currency":          expense.Amount.Currency,
+				"original_amount_minor":    expense.OriginalAmount.Minor,
+				"original_amount_currency": expense.OriginalAmount.Currency,
+				"category_id":              expense.CategoryID,
+				"split_mode":               expense.SplitMode,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("expense_id = ?", expense.ID).Delete(&store.ExpensePayer{}).Error; err != nil {
+			return err
+		}
+
+		if err := createPayers(tx, expense.ID, expense.Payers); err != nil {
+			return err
+		}
+
+		for _, share := range shares {
+			err := tx.Model(&store.ExpenseShare{}).
+				Where("id = ?", share.ID).
+				Updates(map[string]any{
+					"amount_minor":     share.Amount.Minor,
+					"amount_currency":  share.Amount.Currency,
+					"covered_minor":    share.Covered.Minor,
+					"covered_currency": share.Covered.Currency,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			if err := updatePaid(tx, share.ID); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(tx, expense, editedByID, store.AuditUpdated, details)
+	})
+}
+
+// DeleteExpense soft-deletes the expense and its shares. An expense with
+// recorded payments is kept and store.ErrExpenseHasPayments is returned, so
+// that no payment is left pointing at a share that no longer counts.
+func (s *ExpenseStore) DeleteExpense(expenseID uint, deletedByID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var expense store.Expense
+		if err := tx.First(&expense, expenseID).Error; err != nil {
+			return err
+		}
+
+		var payments int64
+		err := tx.Model(&store.Payment{}).
+			Joins("JOIN expense_shares ON expense_shares.id = payments.expense_share_id").
+			Where("expense_shares.expense_id = ?", expenseID).
+			Count(&payments).Error
+		if err != nil {
+			return err
+		}
+
+		if payments > 0 {
+			return store.ErrExpenseHasPayments
+		}
+
+		if err := tx.Where("expense_id = ?", expenseID).Delete(&store.ExpenseShare{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&expense).Error; err != nil {
+			return err
+		}
+
+		return recordAudit(tx, expense, deletedByID, store.AuditDeleted, expense.Amount.String())
+	})
+}
+
+// GetExpenseAuditsByHouseholdID returns the audit trail of a household, newest
+// first.
+func (s *ExpenseStore) GetExpenseAuditsByHouseholdID(householdID uint) ([]store.ExpenseAudit, error) {
+	var audits []store.ExpenseAudit
+	err := s.db.
+		Where("household_id = ?", householdID).
+		Preload("User").
+		Order("created_on desc, id desc").
+		Find(&audits).Error
+	return audits, err
+}
+
+func recordAudit(tx *gorm.DB, expense store.Expense, userID uint, action store.ExpenseAuditAction, details string) error {
+	return tx.Create(&store.ExpenseAudit{
+		ExpenseID:   expense.ID,
+		ExpenseName: expense.Name,
+		HouseholdID: expense.HouseholdID,
+		UserID:      userID,
+		Action:      action,
+		Details:     details,
+		CreatedOn:   time.Now(),
+	}).Error
+}
+
+// createExpenseWithShares saves an expense that was split up front, with its
+// payers and shares, and records its creation with details.
+func createExpenseWithShares(tx *gorm.DB, expense store.Expense, shares []store.ExpenseShare, details string) error {
+	payers := expense.Payers
+	expense.Payers = nil
+
+	if err := tx.Create(&expense).Error; err != nil {
+		return err
+	}
+
+	if err := createPayers(tx, expense.ID, payers); err != nil {
+		return err
+	}
+
+	for _, share := range shares {
+		share.ExpenseID = expense.ID
+		share.Paid = share.Covered.Minor >= share.Amount.Minor
+		if err := tx.Create(&share).Error; err != nil {
+			return err
+		}
+	}
+
+	return recordAudit(tx, expense, expense.CreatedByID, store.AuditCreated, details)
+}
+
+// createPayers saves payers as the payers of expenseID. IDs are cleared so the
+// payers of an edited expense can be passed back in after their rows were
+// deleted.
+func createPayers(tx *gorm.DB, expenseID uint, payers []store.ExpensePayer) error {
+	for _, p := range payers {
+		p.ID = 0
+		p.ExpenseID = expenseID
+		if err := tx.Omit("User").Create(&p).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orderByUser(db *gorm.DB) *gorm.DB {
+	return db.Order("user_id")
+}
@@ -1,6 +1,9 @@
 package dbstore
 
 import (
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"gorm.io/gorm"
 )
@@ -19,12 +22,16 @@ func NewExpenseShareStore(params NewExpenseShareStoreParams) *ExpenseShareStore
 	}
 }
 
-func (s *ExpenseShareStore) CreateExpenseShare(expenseID uint, userID uint, amount float64) error {
+// CreateExpenseShare saves a share of amount, of which covered was paid by
+// the user themselves as a payer of the expense. A fully covered share is
+// created paid.
+func (s *ExpenseShareStore) CreateExpenseShare(expenseID uint, userID uint, amount money.Money, covered money.Money) error {
 	return s.db.Create(&store.ExpenseShare{
 		ExpenseID: expenseID,
 		UserID:    userID,
 		Amount:    amount,
-		Paid:      false,
+		Covered:   covered,
+		Paid:      covered.Minor >= amount.Minor,
 	}).Error
 }
 
@@ -33,19 +40,41 @@ func (s *ExpenseShareStore) GetExpenseShare(expenseID uint, userID uint) (store.
 	err := s.db.
 		Preload("Expense").
 		Preload("Expense.Household").
+		Preload("Expense.Payers", orderByUser).
 		Preload("User").
+		Preload("Payments").
 		Where("expense_id = ? AND user_id = ?", expenseID, userID).
 		First(&share).Error
 	return share, err
 }
 
+func (s *ExpenseShareStore) GetSharesByExpenseID(expenseID uint) ([]store.ExpenseShare, error) {
+	var shares []store.ExpenseShare
+
+	err := s.db.
+		Preload("User").
+		Preload("Payments").
+		Where("expense_id = ?", expenseID).
+		Order("user_id").
+		Find(&shares).Error
+
+	return shares, err
+}
+
 func (s *ExpenseShareStore) GetExpensesByUserID(userID uint) ([]store.ExpenseShare, error) {
 	var shares []store.ExpenseShare
 
 	err := s.db.
 		Preload("Expense").
 		Preload("Expense.Household").
+		Preload("Expense.Category.Parent").
+		Preload("Expense.Payers", orderByUser).
+		Preload("Expense.Payers.User").
 		Preload("User").
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("paid_on")
+		}).
+		Preload("Payments.Payer").
 		Where("user_id = ?", userID).
 		Order("expense_id desc").
 		Find(&shares).Error
@@ -53,6 +82,69 @@ func (s *ExpenseShareStore) GetExpensesByUserID(userID uint) ([]store.ExpenseSha
 	return shares, err
 }
 
+// GetSharesByUserIDInPeriod returns the shares of userID in expenses created
+// between from and to, oldest first, narrowed to paid or unpaid shares by
+// paymentStatus "paid" or "unpaid".
+func (s *ExpenseShareStore) GetSharesByUserIDInPeriod(userID uint, from, to time.Time, paymentStatus string) ([]store.ExpenseShare, error) {
+	return s.sharesInPeriod(s.db.Where("expense_shares.user_id = ?", userID), from, to, paymentStatus)
+}
+
+// GetSharesByHouseholdIDInPeriod is GetSharesByUserIDInPeriod for the shares
+// of every member of a household, with the members and their payments
+// loaded.
+func (s *ExpenseShareStore) GetSharesByHouseholdIDInPeriod(householdID uint, from, to time.Time, paymentStatus string) ([]store.ExpenseShare, error) {
+	query := s.db.
+		Preload("User").
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("paid_on")
+		}).
+		Preload("Payments.Payer").
+		Preload("Payments.Payee").
+		Where("Expense.household_id = ?", householdID)
+
+	return s.sharesInPeriod(query, from, to, paymentStatus)
+}
+
+func (s *ExpenseShareStore) sharesInPeriod(query *gorm.DB, from, to time.Time, paymentStatus string) ([]store.ExpenseShare, error) {
+	var shares []store.ExpenseShare
+
+	query = query.
+		Joins("Expense").
+		Preload("Expense.Household").
+		Preload("Expense.Category.Parent").
+		Where("Expense.created_on BETWEEN ? AND ?", from, to)
+
+	switch paymentStatus {
+	case "paid":
+		query = query.Where("expense_shares.paid = ?", true)
+	case "unpaid":
+		query = query.Where("expense_shares.paid = ?", false)
+	}
+
+	err := query.
+		Order("Expense.created_on, expense_shares.id").
+		Find(&shares).Error
+
+	return shares, err
+}
+
+func (s *ExpenseShareStore) GetSharesByHouseholdID(householdID uint) ([]store.ExpenseShare, error) {
+	var shares []store.ExpenseShare
+
+	err := s.db.
+		Joins("Expense").
+		Preload("Expense.Category.Parent").
+		Preload("Expense.Payers", orderByUser).
+		Preload("Expense.Payers.User").
+		Preload("User").
+		Preload("Payments").
+		Where("Expense.household_id = ?", householdID).
+		Order("expense_shares.id").
+		Find(&shares).Error
+
+	return shares, err
+}
+
 func (s *ExpenseShareStore) UpdateExpenseShare(share store.ExpenseShare) error {
 	return s.db.Save(&share).Error
 }
@@ -21,14 +21,21 @@ func NewHouseholdStore(params NewHouseholdStoreParams) *HouseholdStore {
 	}
 }
 
-func (s *HouseholdStore) CreateHousehold(name string, description string, createdByID uint) (uint, error) {
+func (s *HouseholdStore) CreateHousehold(name string, description string, baseCurrency string, createdByID uint) (uint, error) {
 	household := store.Household{
-		Name:        name,
-		Description: description,
-		CreatedByID: createdByID,
+		Name:         name,
+		Description:  description,
+		BaseCurrency: baseCurrency,
+		CreatedByID:  createdByID,
 	}
 
-	err := s.db.Create(&household).Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&household).Error; err != nil {
+			return err
+		}
+
+		return seedCategories(tx, household.ID)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -36,12 +43,23 @@ func (s *HouseholdStore) CreateHousehold(name string, description string, create
 	return household.ID, nil
 }
 
+func (s *HouseholdStore) GetHouseholdByID(householdID uint) (store.Household, error) {
+	var household store.Household
+	err := s.db.Where("id = ?", householdID).First(&household).Error
+	return household, err
+}
+
 func (s *HouseholdStore) GetHouseholdsByUserID(userID uint) ([]store.Household, error) {
 	var households []store.Household
 	err := s.db.Joins("JOIN memberships ON memberships.household_id = households.id").
 		Where("memberships.user_id = ?", userID).
 		Preload("Memberships").
+		Preload("Memberships.User").
 		Preload("CreatedBy").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB {
+			return db.Where("archived = ?", false).Order("COALESCE(parent_id, id), parent_id IS NOT NULL, name")
+		}).
+		Preload("Categories.Parent").
 		Find(&households).Error
 
 	if err != nil {
@@ -53,7 +71,9 @@ func (s *HouseholdStore) GetHouseholdsByUserID(userID uint) ([]store.Household,
 
 func (s *HouseholdStore) GetOwnedHouseholdsByUserID(userID uint) ([]store.Household, error) {
 	var households []store.Household
-	err := s.db.Where("created_by_id = ?", userID).Find(&households).Error
+	err := s.db.Joins("JOIN memberships ON memberships.household_id = households.id").
+		Where("memberships.user_id = ? AND memberships.role = ?", userID, store.RoleOwner).
+		Find(&households).Error
 
 	if err != nil {
 		return nil, err
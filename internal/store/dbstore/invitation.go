@@ -0,0 +1,157 @@
+package dbstore
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+type InvitationStore struct {
+	db *gorm.DB
+}
+
+type NewInvitationStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewInvitationStore(params NewInvitationStoreParams) *InvitationStore {
+	return &InvitationStore{
+		db: params.DB,
+	}
+}
+
+func (s *InvitationStore) CreateInvitation(householdID uint, invitedByID uint, email string, expiresOn time.Time) (store.Invitation, error) {
+	invitation := store.Invitation{
+		Token:       uuid.New().String(),
+		Email:       normalizeEmail(email),
+		HouseholdID: householdID,
+		InvitedByID: invitedByID,
+		Status:      store.InvitationPending,
+		CreatedOn:   time.Now(),
+		ExpiresOn:   expiresOn,
+	}
+
+	err := s.db.Create(&invitation).Error
+
+	return invitation, err
+}
+
+func (s *InvitationStore) GetInvitationByToken(token string) (store.Invitation, error) {
+	var invitation store.Invitation
+
+	err := s.db.
+		Preload("Household").
+		Preload("InvitedBy").
+		Where("token = ?", token).
+		First(&invitation).Error
+
+	return invitation, err
+}
+
+func (s *InvitationStore) GetOpenInvitationsByEmail(email string) ([]store.Invitation, error) {
+	var invitations []store.Invitation
+
+	err := s.open(s.db).
+		Preload("Household").
+		Preload("InvitedBy").
+		Where("email = ?", normalizeEmail(email)).
+		Order("created_on").
+		Find(&invitations).Error
+
+	return invitations, err
+}
+
+func (s *InvitationStore) GetOpenInvitationsByHouseholdID(householdID uint) ([]store.Invitation, error) {
+	var invitations []store.Invitation
+
+	err := s.open(s.db).
+		Where("household_id = ?", householdID).
+		Order("created_on").
+		Find(&invitations).Error
+
+	return invitations, err
+}
+
+// AcceptInvitation uses the token to make userID a member of the household.
+// It returns store.ErrInvitationClosed when the token was already used or has
+// expired.
+func (s *InvitationStore) AcceptInvitation(token string, userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		invitation, err := s.openByToken(tx, token)
+		if err != nil {
+			return err
+		}
+
+		return acceptInvitation(tx, invitation, userID)
+	})
+}
+
+// DeclineInvitation uses the token without creating a membership.
+func (s *InvitationStore) DeclineInvitation(token string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		invitation, err := s.openByToken(tx, token)
+		if err != nil {
+			return err
+		}
+
+		return closeInvitation(tx, invitation, store.InvitationDeclined)
+	})
+}
+
+func (s *InvitationStore) open(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ? AND expires_on > ?", store.InvitationPending, time.Now())
+}
+
+func (s *InvitationStore) openByToken(tx *gorm.DB, token string) (store.Invitation, error) {
+	var invitation store.Invitation
+
+	err := s.open(tx).Where("token = ?", token).First(&invitation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return invitation, store.ErrInvitationClosed
+	}
+
+	return invitation, err
+}
+
+// acceptInvitation creates the membership unless the user already has one, for
+// example from a second invitation to the same household, and closes the
+// invitation.
+func acceptInvitation(tx *gorm.DB, invitation store.Invitation, userID uint) error {
+	var count int64
+
+	err := tx.Model(&store.Membership{}).
+		Where("household_id = ? AND user_id = ?", invitation.HouseholdID, userID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		err := tx.Create(&store.Membership{
+			UserID:      userID,
+			HouseholdID: invitation.HouseholdID,
+			Role:        store.RoleMember,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return closeInvitation(tx, invitation, store.InvitationAccepted)
+}
+
+func closeInvitation(tx *gorm.DB, invitation store.Invitation, status store.InvitationStatus) error {
+	now := time.Now()
+
+	return tx.Model(&store.Invitation{}).
+		Where("id = ?", invitation.ID).
+		Updates(map[string]any{"status": status, "responded_on": &now}).Error
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -37,3 +37,43 @@ func (s *MembershipStore) GetMembersByHouseholdID(householdID uint) ([]store.Mem
 
 	return memberships, err
 }
+
+func (s *MembershipStore) GetMembership(householdID uint, userID uint) (store.Membership, error) {
+	var membership store.Membership
+
+	err := s.db.
+		Where("household_id = ? AND user_id = ?", householdID, userID).
+		First(&membership).Error
+
+	return membership, err
+}
+
+func (s *MembershipStore) UpdateMembershipRole(householdID uint, userID uint, role string) error {
+	return s.db.
+		Model(&store.Membership{}).
+		Where("household_id = ? AND user_id = ?", householdID, userID).
+		Update("role", role).Error
+}
+
+func (s *MembershipStore) DeleteMembership(householdID uint, userID uint) error {
+	return s.db.
+		Where("household_id = ? AND user_id = ?", householdID, userID).
+		Delete(&store.Membership{}).Error
+}
+
+// TransferOwnership makes toUserID the owner of the household and turns the
+// previous owner into an admin, in one transaction.
+func (s *MembershipStore) TransferOwnership(householdID uint, fromUserID uint, toUserID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&store.Membership{}).
+			Where("household_id = ? AND user_id = ?", householdID, fromUserID).
+			Update("role", store.RoleAdmin).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&store.Membership{}).
+			Where("household_id = ? AND user_id = ?", householdID, toUserID).
+			Update("role", store.RoleOwner).Error
+	})
+}
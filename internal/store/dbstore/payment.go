@@ -0,0 +1,110 @@
+package dbstore
+
+import (
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+type PaymentStore struct {
+	db *gorm.DB
+}
+
+type NewPaymentStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewPaymentStore(params NewPaymentStoreParams) *PaymentStore {
+	return &PaymentStore{
+		db: params.DB,
+	}
+}
+
+// CreatePayments saves payments in a single transaction and marks every share
+// whose payments now cover its amount as paid. The payments are checked
+// against the amount of their share only once they are written, so that of
+// two payments recorded at the same time, e.g. a form submitted twice, the
+// one that would pay a share beyond its amount is rolled back with
+// store.ErrOverpayment.
+func (s *PaymentStore) CreatePayments(payments []store.Payment) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		shareIDs := make(map[uint]bool)
+
+		for i := range payments {
+			if err := tx.Create(&payments[i]).Error; err != nil {
+				return err
+			}
+			shareIDs[payments[i].ExpenseShareID] = true
+		}
+
+		for shareID := range shareIDs {
+			share, settled, err := shareSettled(tx, shareID)
+			if err != nil {
+				return err
+			}
+
+			if settled > share.Amount.Minor {
+				return store.ErrOverpayment
+			}
+
+			if err := updatePaid(tx, shareID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// updatePaid recomputes the Paid flag of a share from its payments and the
+// part its user covered as a payer.
+func updatePaid(tx *gorm.DB, shareID uint) error {
+	share, settled, err := shareSettled(tx, shareID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&store.ExpenseShare{}).
+		Where("id = ?", shareID).
+		Update("paid", settled >= share.Amount.Minor).Error
+}
+
+// shareSettled returns a share with its amount and covered part, and how much
+// of it, in minor units, is settled by what its user covered as a payer and
+// by payments.
+func shareSettled(tx *gorm.DB, shareID uint) (store.ExpenseShare, int64, error) {
+	var share store.ExpenseShare
+	if err := tx.Select("id", "amount_minor", "amount_currency", "covered_minor").First(&share, shareID).Error; err != nil {
+		return store.ExpenseShare{}, 0, err
+	}
+
+	var paid money.Money
+	err := tx.Model(&store.Payment{}).
+		Select("COALESCE(SUM(amount_minor), 0) AS minor").
+		Where("expense_share_id = ?", shareID).
+		Scan(&paid).Error
+	if err != nil {
+		return store.ExpenseShare{}, 0, err
+	}
+
+	return share, share.Covered.Minor + paid.Minor, nil
+}
+
+// GetPaymentsByUserID returns the payments a user made or received between
+// from and to, oldest first.
+func (s *PaymentStore) GetPaymentsByUserID(userID uint, from, to time.Time) ([]store.Payment, error) {
+	var payments []store.Payment
+
+	err := s.db.
+		Preload("Payer").
+		Preload("Payee").
+		Preload("ExpenseShare.Expense").
+		Preload("ExpenseShare.Expense.Category.Parent").
+		Where("(payer_id = ? OR payee_id = ?) AND paid_on BETWEEN ? AND ?", userID, userID, from, to).
+		Order("paid_on").
+		Find(&payments).Error
+
+	return payments, err
+}
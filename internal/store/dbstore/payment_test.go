@@ -0,0 +1,44 @@
+package dbstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePayments_Overpayment(t *testing.T) {
+	database := db.MustOpen(filepath.Join(t.TempDir(), "test.db"))
+	payments := NewPaymentStore(NewPaymentStoreParams{DB: database})
+
+	share := store.ExpenseShare{ExpenseID: 1, UserID: 2, Amount: money.New(1000, "PLN"), Covered: money.New(0, "PLN")}
+	require.NoError(t, database.Create(&share).Error)
+
+	payment := func(minor int64) []store.Payment {
+		return []store.Payment{{
+			ExpenseShareID: share.ID,
+			PayerID:        2,
+			PayeeID:        1,
+			Amount:         money.New(minor, "PLN"),
+			PaidOn:         time.Now(),
+			Method:         store.PaymentTransfer,
+		}}
+	}
+
+	require.NoError(t, payments.CreatePayments(payment(600)))
+
+	// The same payment submitted again would pay the share beyond its amount.
+	require.ErrorIs(t, payments.CreatePayments(payment(600)), store.ErrOverpayment)
+
+	var count int64
+	require.NoError(t, database.Model(&store.Payment{}).Where("expense_share_id = ?", share.ID).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	require.NoError(t, payments.CreatePayments(payment(400)))
+	require.NoError(t, database.First(&share, share.ID).Error)
+	require.True(t, share.Paid)
+}
@@ -0,0 +1,103 @@
+package dbstore
+
+import (
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+type RecurringExpenseStore struct {
+	db *gorm.DB
+}
+
+type NewRecurringExpenseStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewRecurringExpenseStore(params NewRecurringExpenseStoreParams) *RecurringExpenseStore {
+	return &RecurringExpenseStore{
+		db: params.DB,
+	}
+}
+
+// CreateRecurringExpense saves recurring together with its split values.
+func (s *RecurringExpenseStore) CreateRecurringExpense(recurring store.RecurringExpense) (uint, error) {
+	recurring.NextRunOn = recurring.NextRunOn.UTC()
+
+	err := s.db.Omit("PaidBy", "Household", "Category", "CreatedBy").Create(&recurring).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return recurring.ID, nil
+}
+
+func (s *RecurringExpenseStore) GetRecurringExpenseByID(recurringExpenseID uint) (store.RecurringExpense, error) {
+	var recurring store.RecurringExpense
+	err := s.db.
+		Preload("Splits").
+		First(&recurring, recurringExpenseID).Error
+	return recurring, err
+}
+
+func (s *RecurringExpenseStore) GetRecurringExpensesByHouseholdID(householdID uint) ([]store.RecurringExpense, error) {
+	var recurring []store.RecurringExpense
+	err := s.db.
+		Where("household_id = ?", householdID).
+		Preload("PaidBy").
+		Preload("CreatedBy").
+		Preload("Category.Parent").
+		Order("next_run_on, id").
+		Find(&recurring).Error
+	return recurring, err
+}
+
+// GetDueRecurringExpenses returns the recurring expenses with an occurrence
+// due at or before now.
+func (s *RecurringExpenseStore) GetDueRecurringExpenses(now time.Time) ([]store.RecurringExpense, error) {
+	var recurring []store.RecurringExpense
+	err := s.db.
+		Where("next_run_on <= ?", now.UTC()).
+		Preload("Splits").
+		Preload("Household").
+		Order("id").
+		Find(&recurring).Error
+	return recurring, err
+}
+
+// DeleteRecurringExpense stops a recurring expense. Expenses it already
+// materialized are kept.
+func (s *RecurringExpenseStore) DeleteRecurringExpense(recurringExpenseID uint) error {
+	return s.db.Delete(&store.RecurringExpense{}, recurringExpenseID).Error
+}
+
+// MaterializeRecurringExpense saves expense, an occurrence of the recurring
+// expense it names, with its payers and shares, and moves the recurring
+// expense on to nextRunOn, all in one transaction. An occurrence that already
+// exists, even deleted, is not created again, so the scheduler can safely
+// retry an occurrence after a crash.
+func (s *RecurringExpenseStore) MaterializeRecurringExpense(expense store.Expense, shares []store.ExpenseShare, nextRunOn time.Time) error {
+	occurrenceOn := expense.OccurrenceOn.UTC()
+	expense.OccurrenceOn = &occurrenceOn
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		err := tx.Unscoped().Model(&store.Expense{}).
+			Where("recurring_expense_id = ? AND occurrence_on = ?", expense.RecurringExpenseID, expense.OccurrenceOn).
+			Count(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		if existing == 0 {
+			if err := createExpenseWithShares(tx, expense, shares, "recurring, "+expense.Amount.String()); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&store.RecurringExpense{}).
+			Where("id = ?", expense.RecurringExpenseID).
+			Update("next_run_on", nextRunOn.UTC()).Error
+	})
+}
@@ -1,15 +1,16 @@
 package dbstore
 
 import (
-	"fmt"
 	"time"
 
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"gorm.io/gorm"
 )
 
 type ReportStore struct {
-	db *gorm.DB
+	db    *gorm.DB
+	rates *ExchangeRateStore
 }
 
 type NewReportStoreParams struct {
@@ -18,57 +19,257 @@ type NewReportStoreParams struct {
 
 func NewReportStore(params NewReportStoreParams) *ReportStore {
 	return &ReportStore{
-		db: params.DB,
+		db:    params.DB,
+		rates: NewExchangeRateStore(NewExchangeRateStoreParams{DB: params.DB}),
 	}
 }
 
-func (s *ReportStore) CreateReport(userID uint, from, to time.Time, paymentStatus string) (store.Report, error) {
-	var total float64
+// QueueReport records report as queued for generation, with a new file name
+// for it in its format. Its total is only known once it is generated.
+func (s *ReportStore) QueueReport(report store.Report) (store.Report, error) {
+	report = queuedReport(report, time.Now())
 
-	query := s.db.Model(&store.ExpenseShare{}).
-		Select("SUM(expense_shares.amount)").
-		Joins("JOIN expenses ON expenses.id = expense_shares.expense_id").
-		Where(
-			"expense_shares.user_id = ? AND expenses.created_on BETWEEN ? AND ?",
-			userID, from, to,
-		)
-
-	switch paymentStatus {
-	case "paid":
-		query = query.Where("expense_shares.paid = ?", true)
-	case "unpaid":
-		query = query.Where("expense_shares.paid = ?", false)
+	if err := s.db.Omit("User", "Household").Create(&report).Error; err != nil {
+		return store.Report{}, err
 	}
 
-	if err := query.Scan(&total).Error; err != nil {
-		return store.Report{}, err
+	return report, nil
+}
+
+// QueueScheduledReport queues report, an occurrence of the report schedule
+// it names, and moves the schedule on to nextRunOn, all in one transaction.
+// An occurrence that was already queued is not queued again, so the
+// scheduler can safely retry an occurrence after a crash.
+func (s *ReportStore) QueueScheduledReport(report store.Report, nextRunOn time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		err := tx.Model(&store.Report{}).
+			Where("schedule_id = ? AND period_start = ?", report.ScheduleID, report.PeriodStart).
+			Count(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		if existing == 0 {
+			report = queuedReport(report, time.Now())
+			if err := tx.Omit("User", "Household").Create(&report).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&store.ReportSchedule{}).
+			Where("id = ?", report.ScheduleID).
+			Update("next_run_on", nextRunOn.UTC()).Error
+	})
+}
+
+// queuedReport prepares report to be queued at now.
+func queuedReport(report store.Report, now time.Time) store.Report {
+	report.Status = store.ReportQueued
+	report.Attempts = 0
+	report.RunAfter = now
+	report.GenerationDate = now
+	report.TotalExpenses = money.New(0, money.DefaultCurrency)
+	report.FileName = report.NewFileName(now)
+
+	return report
+}
+
+// ClaimNextReport marks the oldest queued report due at now as running and
+// counts the attempt. It returns gorm.ErrRecordNotFound when no report is
+// due, or when another worker claimed it first.
+func (s *ReportStore) ClaimNextReport(now time.Time) (store.Report, error) {
+	var report store.Report
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Find rather than First, so that an empty queue is not logged as
+		// an error on every poll.
+		found := tx.Where("status = ? AND run_after <= ?", store.ReportQueued, now).
+			Order("run_after, id").
+			Limit(1).
+			Find(&report)
+		if found.Error != nil {
+			return found.Error
+		}
+		if found.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		result := tx.Model(&store.Report{}).
+			Where("id = ? AND status = ?", report.ID, store.ReportQueued).
+			Updates(map[string]any{"status": store.ReportRunning, "attempts": gorm.Expr("attempts + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		report.Status = store.ReportRunning
+		report.Attempts++
+		return nil
+	})
+
+	return report, err
+}
+
+// ReportTotal sums the shares a report covers in one currency, converted at
+// the rates valid on on when they come from households with different base
+// currencies.
+func (s *ReportStore) ReportTotal(shares []store.ExpenseShare, on time.Time) (money.Money, error) {
+	return s.sumInOneCurrency(currencyTotals(shares), on)
+}
+
+// CompleteReport records that the file of report is written, with its name,
+// total and generation date, and that it finished now.
+func (s *ReportStore) CompleteReport(report store.Report) error {
+	return s.db.Model(&store.Report{}).Where("id = ?", report.ID).Updates(map[string]any{
+		"status":                  store.ReportDone,
+		"finished_at":             time.Now(),
+		"file_name":               report.FileName,
+		"total_expenses_minor":    report.TotalExpenses.Minor,
+		"total_expenses_currency": report.TotalExpenses.Currency,
+		"generation_date":         report.GenerationDate,
+		"error":                   "",
+	}).Error
+}
+
+// RetryReport puts a report whose attempt failed with message back in the
+// queue, to be run again from runAfter on.
+func (s *ReportStore) RetryReport(reportID uint, message string, runAfter time.Time) error {
+	return s.db.Model(&store.Report{}).Where("id = ?", reportID).Updates(map[string]any{
+		"status":    store.ReportQueued,
+		"run_after": runAfter,
+		"error":     message,
+	}).Error
+}
+
+// FailReport records that a report failed for good with message now.
+func (s *ReportStore) FailReport(reportID uint, message string) error {
+	return s.db.Model(&store.Report{}).Where("id = ?", reportID).Updates(map[string]any{
+		"status":      store.ReportFailed,
+		"finished_at": time.Now(),
+		"error":       message,
+	}).Error
+}
+
+// RequeueRunningReports puts reports that were running when the server
+// stopped back in the queue. It must only be called before any worker starts.
+func (s *ReportStore) RequeueRunningReports() error {
+	return s.db.Model(&store.Report{}).
+		Where("status = ?", store.ReportRunning).
+		Update("status", store.ReportQueued).Error
+}
+
+// RequeueReport puts a report back in the queue to be generated again from
+// scratch, with a fresh set of attempts.
+func (s *ReportStore) RequeueReport(reportID uint) error {
+	return s.db.Model(&store.Report{}).Where("id = ?", reportID).Updates(map[string]any{
+		"status":      store.ReportQueued,
+		"attempts":    0,
+		"run_after":   time.Now(),
+		"finished_at": nil,
+		"error":       "",
+	}).Error
+}
+
+func (s *ReportStore) DeleteReport(reportID uint) error {
+	return s.db.Delete(&store.Report{}, reportID).Error
+}
+
+// DeleteReportsBefore deletes the reports with status that finished, being
+// generated or failing for good, before before. It returns them so their
+// files can be removed.
+func (s *ReportStore) DeleteReportsBefore(status store.ReportStatus, before time.Time) ([]store.Report, error) {
+	var reports []store.Report
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("status = ? AND finished_at < ?", status, before).Find(&reports).Error
+		if err != nil || len(reports) == 0 {
+			return err
+		}
+
+		return tx.Delete(&reports).Error
+	})
+
+	return reports, err
+}
+
+// GetAllReports returns every report, personal and household alike.
+func (s *ReportStore) GetAllReports() ([]store.Report, error) {
+	var reports []store.Report
+	err := s.db.Order("id").Find(&reports).Error
+	return reports, err
+}
+
+// currencyTotals sums shares per currency, in the order currencies first
+// appear.
+func currencyTotals(shares []store.ExpenseShare) []money.Money {
+	var totals []money.Money
+	index := map[string]int{}
+
+	for _, share := range shares {
+		i, ok := index[share.Amount.Currency]
+		if !ok {
+			i = len(totals)
+			index[share.Amount.Currency] = i
+			totals = append(totals, money.New(0, share.Amount.Currency))
+		}
+		totals[i] = totals[i].Add(share.Amount)
 	}
 
-	fileName := fmt.Sprintf("report_%d_%d.pdf", userID, time.Now().Unix())
+	return totals
+}
 
-	report := store.Report{
-		UserID:         userID,
-		PeriodStart:    from,
-		PeriodEnd:      to,
-		TotalExpenses:  total,
-		PaymentStatus:  paymentStatus,
-		GenerationDate: time.Now(),
-		FileName:       fileName,
+// sumInOneCurrency adds up per-currency totals. Shares from households with
+// different base currencies are converted to money.DefaultCurrency at the rates
+// valid on the given day.
+func (s *ReportStore) sumInOneCurrency(totals []money.Money, on time.Time) (money.Money, error) {
+	switch len(totals) {
+	case 0:
+		return money.New(0, money.DefaultCurrency), nil
+	case 1:
+		return totals[0], nil
 	}
 
-	if err := s.db.Create(&report).Error; err != nil {
-		return store.Report{}, err
+	sum := money.New(0, money.DefaultCurrency)
+	for _, t := range totals {
+		converted, err := s.rates.Convert(t, money.DefaultCurrency, on)
+		if err != nil {
+			return money.Money{}, err
+		}
+		sum = sum.Add(converted)
 	}
 
-	return report, nil
+	return sum, nil
 }
 
+// GetReportsByUser returns the personal reports of userID, newest first.
 func (s *ReportStore) GetReportsByUser(userID uint) ([]store.Report, error) {
 	var reports []store.Report
-	err := s.db.Where("user_id = ?", userID).Order("generation_date desc").Find(&reports).Error
+	err := s.db.Where("user_id = ? AND household_id IS NULL", userID).Order("generation_date desc").Find(&reports).Error
+	return reports, err
+}
+
+// GetReportsByHouseholdID returns the household reports of householdID with
+// who generated them, newest first.
+func (s *ReportStore) GetReportsByHouseholdID(householdID uint) ([]store.Report, error) {
+	var reports []store.Report
+	err := s.db.
+		Preload("User").
+		Where("household_id = ?", householdID).
+		Order("generation_date desc").
+		Find(&reports).Error
 	return reports, err
 }
 
+// GetReportByID returns the report with who generated it.
+func (s *ReportStore) GetReportByID(reportID uint) (store.Report, error) {
+	var report store.Report
+	err := s.db.Preload("User").Where("id = ?", reportID).First(&report).Error
+	return report, err
+}
+
 func (s *ReportStore) GetReportByFileName(fileName string) (store.Report, error) {
 	var report store.Report
 	err := s.db.Where("file_name = ?", fileName).First(&report).Error
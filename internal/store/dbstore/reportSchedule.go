@@ -0,0 +1,65 @@
+package dbstore
+
+import (
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"gorm.io/gorm"
+)
+
+type ReportScheduleStore struct {
+	db *gorm.DB
+}
+
+type NewReportScheduleStoreParams struct {
+	DB *gorm.DB
+}
+
+func NewReportScheduleStore(params NewReportScheduleStoreParams) *ReportScheduleStore {
+	return &ReportScheduleStore{
+		db: params.DB,
+	}
+}
+
+func (s *ReportScheduleStore) CreateReportSchedule(schedule store.ReportSchedule) (uint, error) {
+	schedule.NextRunOn = schedule.NextRunOn.UTC()
+
+	err := s.db.Omit("User").Create(&schedule).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return schedule.ID, nil
+}
+
+func (s *ReportScheduleStore) GetReportScheduleByID(scheduleID uint) (store.ReportSchedule, error) {
+	var schedule store.ReportSchedule
+	err := s.db.First(&schedule, scheduleID).Error
+	return schedule, err
+}
+
+func (s *ReportScheduleStore) GetReportSchedulesByUserID(userID uint) ([]store.ReportSchedule, error) {
+	var schedules []store.ReportSchedule
+	err := s.db.
+		Where("user_id = ?", userID).
+		Order("next_run_on, id").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// GetDueReportSchedules returns the report schedules with an occurrence due
+// at or before now.
+func (s *ReportScheduleStore) GetDueReportSchedules(now time.Time) ([]store.ReportSchedule, error) {
+	var schedules []store.ReportSchedule
+	err := s.db.
+		Where("next_run_on <= ?", now.UTC()).
+		Order("id").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// DeleteReportSchedule stops a report schedule. Reports it already generated
+// are kept.
+func (s *ReportScheduleStore) DeleteReportSchedule(scheduleID uint) error {
+	return s.db.Delete(&store.ReportSchedule{}, scheduleID).Error
+}
@@ -0,0 +1,58 @@
+package filestore
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func testFileStore(t *testing.T, files store.FileStore) {
+	names, err := files.List()
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	require.NoError(t, files.Put("b.pdf", strings.NewReader("first")))
+	require.NoError(t, files.Put("a.pdf", strings.NewReader("other")))
+	require.NoError(t, files.Put("b.pdf", strings.NewReader("second")))
+
+	r, err := files.Get("b.pdf")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "second", string(content))
+
+	names, err = files.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.pdf", "b.pdf"}, names)
+
+	require.NoError(t, files.Delete("a.pdf"))
+	require.ErrorIs(t, files.Delete("a.pdf"), store.ErrFileNotFound)
+
+	_, err = files.Get("a.pdf")
+	require.ErrorIs(t, err, store.ErrFileNotFound)
+
+	for _, name := range []string{"", "..", "../b.pdf", "dir/b.pdf"} {
+		require.ErrorIs(t, files.Put(name, strings.NewReader("x")), store.ErrInvalidFileName, name)
+		_, err := files.Get(name)
+		require.ErrorIs(t, err, store.ErrInvalidFileName, name)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	testFileStore(t, NewLocalStore(NewLocalStoreParams{Dir: dir}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestMemoryStore(t *testing.T) {
+	testFileStore(t, NewMemoryStore())
+}
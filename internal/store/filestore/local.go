@@ -0,0 +1,116 @@
+package filestore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// tempPrefix marks files that are still being written, which List skips.
+const tempPrefix = ".tmp-"
+
+// LocalStore keeps files in a directory on the local disk.
+type LocalStore struct {
+	dir string
+}
+
+type NewLocalStoreParams struct {
+	Dir string
+}
+
+func NewLocalStore(params NewLocalStoreParams) *LocalStore {
+	return &LocalStore{
+		dir: params.Dir,
+	}
+}
+
+// Put writes content to a temporary file first and renames it into place, so
+// a file is never seen half written, even when writing it fails.
+func (s *LocalStore) Put(name string, content io.Reader) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, tempPrefix+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+func (s *LocalStore) Get(name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, store.ErrFileNotFound
+	}
+
+	return f, err
+}
+
+func (s *LocalStore) Delete(name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return store.ErrFileNotFound
+	}
+
+	return err
+}
+
+// List returns the names of the files in the directory, sorted. A directory
+// that does not exist yet holds no files.
+func (s *LocalStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), tempPrefix) {
+			names = append(names, e.Name())
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// validName rejects names that would reach outside the directory of the
+// store.
+func validName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, tempPrefix) {
+		return store.ErrInvalidFileName
+	}
+	return nil
+}
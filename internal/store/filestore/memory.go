@@ -0,0 +1,81 @@
+package filestore
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
+)
+
+// MemoryStore keeps files in memory. It is meant for tests and loses every
+// file when the process exits.
+type MemoryStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		files: map[string][]byte{},
+	}
+}
+
+func (s *MemoryStore) Put(name string, content io.Reader) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[name] = data
+	return nil
+}
+
+func (s *MemoryStore) Get(name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[name]
+	if !ok {
+		return nil, store.ErrFileNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MemoryStore) Delete(name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.files[name]; !ok {
+		return store.ErrFileNotFound
+	}
+
+	delete(s.files, name)
+	return nil
+}
+
+// List returns the names of the files in the store, sorted.
+func (s *MemoryStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(s.files)), nil
+}
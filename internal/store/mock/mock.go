@@ -1,6 +1,9 @@
 package mock
 
 import (
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
 	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/store"
 	"github.com/stretchr/testify/mock"
 )
@@ -57,3 +60,168 @@ func (m *SessionStoreMock) DeleteSession(userID uint) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
+
+type MembershipStoreMock struct {
+	mock.Mock
+}
+
+func (m *MembershipStoreMock) CreateMembership(userID uint, householdID uint, role string) error {
+	args := m.Called(userID, householdID, role)
+	return args.Error(0)
+}
+
+func (m *MembershipStoreMock) GetMembersByHouseholdID(householdID uint) ([]store.Membership, error) {
+	args := m.Called(householdID)
+	return args.Get(0).([]store.Membership), args.Error(1)
+}
+
+func (m *MembershipStoreMock) GetMembership(householdID uint, userID uint) (store.Membership, error) {
+	args := m.Called(householdID, userID)
+	return args.Get(0).(store.Membership), args.Error(1)
+}
+
+func (m *MembershipStoreMock) UpdateMembershipRole(householdID uint, userID uint, role string) error {
+	args := m.Called(householdID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MembershipStoreMock) DeleteMembership(householdID uint, userID uint) error {
+	args := m.Called(householdID, userID)
+	return args.Error(0)
+}
+
+func (m *MembershipStoreMock) TransferOwnership(householdID uint, fromUserID uint, toUserID uint) error {
+	args := m.Called(householdID, fromUserID, toUserID)
+	return args.Error(0)
+}
+
+type InvitationStoreMock struct {
+	mock.Mock
+}
+
+func (m *InvitationStoreMock) CreateInvitation(householdID uint, invitedByID uint, email string, expiresOn time.Time) (store.Invitation, error) {
+	args := m.Called(householdID, invitedByID, email, expiresOn)
+	return args.Get(0).(store.Invitation), args.Error(1)
+}
+
+func (m *InvitationStoreMock) GetInvitationByToken(token string) (store.Invitation, error) {
+	args := m.Called(token)
+	return args.Get(0).(store.Invitation), args.Error(1)
+}
+
+func (m *InvitationStoreMock) GetOpenInvitationsByEmail(email string) ([]store.Invitation, error) {
+	args := m.Called(email)
+	return args.Get(0).([]store.Invitation), args.Error(1)
+}
+
+func (m *InvitationStoreMock) GetOpenInvitationsByHouseholdID(householdID uint) ([]store.Invitation, error) {
+	args := m.Called(householdID)
+	return args.Get(0).([]store.Invitation), args.Error(1)
+}
+
+func (m *InvitationStoreMock) AcceptInvitation(token string, userID uint) error {
+	args := m.Called(token, userID)
+	return args.Error(0)
+}
+
+func (m *InvitationStoreMock) DeclineInvitation(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+type ExpenseStoreMock struct {
+	mock.Mock
+}
+
+func (m *ExpenseStoreMock) CreateExpense(name string, amount money.Money, originalAmount money.Money, categoryID uint, splitMode store.SplitMode, createdOn time.Time, householdID, createdByID uint, payers []store.ExpensePayer) (uint, error) {
+	args := m.Called(name, amount, originalAmount, categoryID, splitMode, createdOn, householdID, createdByID, payers)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *ExpenseStoreMock) NameExists(name string) (bool, error) {
+	args := m.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ExpenseStoreMock) GetExpenseByID(expenseID uint) (store.Expense, error) {
+	args := m.Called(expenseID)
+	return args.Get(0).(store.Expense), args.Error(1)
+}
+
+func (m *ExpenseStoreMock) GetExpensesByHouseholdID(householdID uint) ([]store.Expense, error) {
+	args := m.Called(householdID)
+	return args.Get(0).([]store.Expense), args.Error(1)
+}
+
+func (m *ExpenseStoreMock) UpdateExpense(expense store.Expense, shares []store.ExpenseShare, editedByID uint, details string) error {
+	args := m.Called(expense, shares, editedByID, details)
+	return args.Error(0)
+}
+
+func (m *ExpenseStoreMock) DeleteExpense(expenseID uint, deletedByID uint) error {
+	args := m.Called(expenseID, deletedByID)
+	return args.Error(0)
+}
+
+func (m *ExpenseStoreMock) GetExpenseAuditsByHouseholdID(householdID uint) ([]store.ExpenseAudit, error) {
+	args := m.Called(householdID)
+	return args.Get(0).([]store.ExpenseAudit), args.Error(1)
+}
+
+func (m *ExpenseStoreMock) ImportExpenses(expenses []store.ImportedExpense) error {
+	args := m.Called(expenses)
+	return args.Error(0)
+}
+
+type RecurringExpenseStoreMock struct {
+	mock.Mock
+}
+
+func (m *RecurringExpenseStoreMock) CreateRecurringExpense(recurring store.RecurringExpense) (uint, error) {
+	args := m.Called(recurring)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *RecurringExpenseStoreMock) GetRecurringExpenseByID(recurringExpenseID uint) (store.RecurringExpense, error) {
+	args := m.Called(recurringExpenseID)
+	return args.Get(0).(store.RecurringExpense), args.Error(1)
+}
+
+func (m *RecurringExpenseStoreMock) GetRecurringExpensesByHouseholdID(householdID uint) ([]store.RecurringExpense, error) {
+	args := m.Called(householdID)
+	return args.Get(0).([]store.RecurringExpense), args.Error(1)
+}
+
+func (m *RecurringExpenseStoreMock) GetDueRecurringExpenses(now time.Time) ([]store.RecurringExpense, error) {
+	args := m.Called(now)
+	return args.Get(0).([]store.RecurringExpense), args.Error(1)
+}
+
+func (m *RecurringExpenseStoreMock) DeleteRecurringExpense(recurringExpenseID uint) error {
+	args := m.Called(recurringExpenseID)
+	return args.Error(0)
+}
+
+func (m *RecurringExpenseStoreMock) MaterializeRecurringExpense(expense store.Expense, shares []store.ExpenseShare, nextRunOn time.Time) error {
+	args := m.Called(expense, shares, nextRunOn)
+	return args.Error(0)
+}
+
+type ExchangeRateStoreMock struct {
+	mock.Mock
+}
+
+func (m *ExchangeRateStoreMock) SaveExchangeRates(rates []store.ExchangeRate) error {
+	args := m.Called(rates)
+	return args.Error(0)
+}
+
+func (m *ExchangeRateStoreMock) GetExchangeRates() ([]store.ExchangeRate, error) {
+	args := m.Called()
+	return args.Get(0).([]store.ExchangeRate), args.Error(1)
+}
+
+func (m *ExchangeRateStoreMock) Convert(amount money.Money, currency string, on time.Time) (money.Money, error) {
+	args := m.Called(amount, currency, on)
+	return args.Get(0).(money.Money), args.Error(1)
+}
@@ -1,6 +1,17 @@
 package store
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/money"
+	"github.com/s30899-pj/HomePiggyBank_byt2025-26_52c/internal/schedule"
+	"gorm.io/gorm"
+)
 
 type User struct {
 	ID       uint   `gorm:"primaryKey" json:"id"`
@@ -17,12 +28,14 @@ type Session struct {
 }
 
 type Household struct {
-	ID          uint         `gorm:"primaryKey" json:"id"`
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	CreatedByID uint         `json:"created_by_id"`
-	CreatedBy   User         `gorm:"foreignKey:CreatedByID" json:"created_by"`
-	Memberships []Membership `gorm:"foreignKey:HouseholdID" json:"memberships"`
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description"`
+	BaseCurrency string       `gorm:"size:3;default:PLN" json:"base_currency"`
+	CreatedByID  uint         `json:"created_by_id"`
+	CreatedBy    User         `gorm:"foreignKey:CreatedByID" json:"created_by"`
+	Memberships  []Membership `gorm:"foreignKey:HouseholdID" json:"memberships"`
+	Categories   []Category   `gorm:"foreignKey:HouseholdID" json:"categories"`
 }
 
 type Membership struct {
@@ -34,66 +47,766 @@ type Membership struct {
 	Role        string    `json:"role"`
 }
 
-type ExpenseCategory string
+// Membership roles, from the most to the least privileged. The owner is the
+// member who created the household or was handed it over; there is exactly
+// one. A viewer can follow the household but not change anything in it.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// Roles lists every role, most privileged first.
+var Roles = []string{RoleOwner, RoleAdmin, RoleMember, RoleViewer}
+
+// Permission is something a member may be allowed to do in a household.
+type Permission string
 
 const (
-	CategoryFood          ExpenseCategory = "food"
-	CategoryRent          ExpenseCategory = "rent"
-	CategoryUtilities     ExpenseCategory = "utilities"
-	CategoryTransport     ExpenseCategory = "transport"
-	CategoryEntertainment ExpenseCategory = "entertainment"
-	CategoryHealth        ExpenseCategory = "health"
-	CategoryShopping      ExpenseCategory = "shopping"
-	CategoryOther         ExpenseCategory = "other"
+	PermAddExpense         Permission = "add_expense"
+	PermEditOthersExpenses Permission = "edit_others_expenses"
+	PermMarkOthersPaid     Permission = "mark_others_paid"
+	PermManageMembers      Permission = "manage_members"
+	PermManageBudgets      Permission = "manage_budgets"
+	PermManageCategories   Permission = "manage_categories"
+	PermHouseholdReports   Permission = "household_reports"
+	PermTransferOwnership  Permission = "transfer_ownership"
 )
 
-func (c ExpenseCategory) IsValid() bool {
-	switch c {
-	case CategoryFood,
-		CategoryRent,
-		CategoryUtilities,
-		CategoryTransport,
-		CategoryEntertainment,
-		CategoryHealth,
-		CategoryShopping,
-		CategoryOther:
+// Permissions lists every permission in the order they are shown to users.
+var Permissions = []Permission{
+	PermAddExpense,
+	PermEditOthersExpenses,
+	PermMarkOthersPaid,
+	PermManageMembers,
+	PermManageBudgets,
+	PermManageCategories,
+	PermHouseholdReports,
+	PermTransferOwnership,
+}
+
+// rolePermissions is the permission matrix. Anything a role is not listed
+// for is denied; acting on one's own expenses and shares needs no permission
+// beyond membership.
+var rolePermissions = map[string][]Permission{
+	RoleOwner:  Permissions,
+	RoleAdmin:  {PermAddExpense, PermEditOthersExpenses, PermMarkOthersPaid, PermManageMembers, PermManageBudgets, PermManageCategories, PermHouseholdReports},
+	RoleMember: {PermAddExpense, PermHouseholdReports},
+	RoleViewer: {},
+}
+
+func (p Permission) Label() string {
+	switch p {
+	case PermAddExpense:
+		return "Add expenses"
+	case PermEditOthersExpenses:
+		return "Edit or delete others' expenses"
+	case PermMarkOthersPaid:
+		return "Mark others' shares paid"
+	case PermManageMembers:
+		return "Manage members"
+	case PermManageBudgets:
+		return "Manage budgets"
+	case PermManageCategories:
+		return "Manage categories"
+	case PermHouseholdReports:
+		return "Generate household reports"
+	case PermTransferOwnership:
+		return "Transfer ownership"
+	}
+	return string(p)
+}
+
+// IsValidRole reports whether role is one of Roles.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleCan reports whether the permission matrix grants p to role.
+func RoleCan(role string, p Permission) bool {
+	return slices.Contains(rolePermissions[role], p)
+}
+
+// Outranks reports whether role a is strictly more privileged than role b.
+// Members can only manage members they outrank.
+func Outranks(a, b string) bool {
+	ia, ib := slices.Index(Roles, a), slices.Index(Roles, b)
+	return ia >= 0 && (ib < 0 || ia < ib)
+}
+
+// Can reports whether the member's role grants p.
+func (m Membership) Can(p Permission) bool {
+	return RoleCan(m.Role, p)
+}
+
+// CanEditExpense reports whether the member may edit or delete e: their own
+// expenses while they may still add expenses, anyone's with
+// PermEditOthersExpenses.
+func (m Membership) CanEditExpense(e Expense) bool {
+	return m.canEdit(e.HouseholdID, e.CreatedByID)
+}
+
+// CanEditRecurringExpense applies the rules of CanEditExpense to a recurring
+// expense.
+func (m Membership) CanEditRecurringExpense(r RecurringExpense) bool {
+	return m.canEdit(r.HouseholdID, r.CreatedByID)
+}
+
+func (m Membership) canEdit(householdID, createdByID uint) bool {
+	if m.HouseholdID != householdID {
+		return false
+	}
+	if m.UserID == createdByID && m.Can(PermAddExpense) {
+		return true
+	}
+	return m.Can(PermEditOthersExpenses)
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+// Invitation asks the owner of Email to join a household. The token is the
+// only way to answer it, it can be used once and only until ExpiresOn. Email
+// does not have to belong to a registered user yet: pending invitations are
+// turned into memberships when that address signs up.
+type Invitation struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Token       string           `gorm:"uniqueIndex" json:"-"`
+	Email       string           `gorm:"index" json:"email"`
+	HouseholdID uint             `json:"household_id"`
+	Household   Household        `gorm:"foreignKey:HouseholdID" json:"household"`
+	InvitedByID uint             `json:"invited_by_id"`
+	InvitedBy   User             `gorm:"foreignKey:InvitedByID" json:"invited_by"`
+	Status      InvitationStatus `gorm:"default:pending" json:"status"`
+	CreatedOn   time.Time        `json:"created_on"`
+	ExpiresOn   time.Time        `json:"expires_on"`
+	RespondedOn *time.Time       `json:"responded_on"`
+}
+
+// IsOpen reports whether the invitation can still be accepted or declined.
+func (i Invitation) IsOpen(now time.Time) bool {
+	return i.Status == InvitationPending && now.Before(i.ExpiresOn)
+}
+
+// ErrInvitationClosed is returned when answering an invitation that was
+// already used or has expired.
+var ErrInvitationClosed = errors.New("invitation is no longer open")
+
+// Category is an expense category of a household. A category with a ParentID
+// is a subcategory; subcategories are one level deep and belong to the same
+// household as their parent. Archived categories keep their expenses but are
+// no longer offered for new ones.
+type Category struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	HouseholdID uint      `gorm:"index" json:"household_id"`
+	ParentID    *uint     `gorm:"index" json:"parent_id"`
+	Parent      *Category `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Name        string    `json:"name"`
+	Colour      string    `gorm:"size:7" json:"colour"`
+	Archived    bool      `json:"archived"`
+}
+
+// DefaultCategories are seeded into every household. Expenses used to pick
+// from a fixed list of categories, stored as the lower-cased names of these.
+var DefaultCategories = []Category{
+	{Name: "Food", Colour: "#36a2eb"},
+	{Name: "Rent", Colour: "#ff6384"},
+	{Name: "Utilities", Colour: "#ffce56"},
+	{Name: "Transport", Colour: "#4bc0c0"},
+	{Name: "Entertainment", Colour: "#9966ff"},
+	{Name: "Health", Colour: "#ff9f40"},
+	{Name: "Shopping", Colour: "#ff63ff"},
+	{Name: "Other", Colour: "#c7c7c7"},
+}
+
+// FullName is the name of the category, prefixed with the name of its parent
+// when that is loaded.
+func (c Category) FullName() string {
+	if c.Parent != nil {
+		return c.Parent.Name + " / " + c.Name
+	}
+	return c.Name
+}
+
+// IsValidColour reports whether colour is a #rrggbb hex colour.
+func IsValidColour(colour string) bool {
+	if len(colour) != 7 || colour[0] != '#' {
+		return false
+	}
+	for _, c := range colour[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+type SplitMode string
+
+const (
+	SplitEqual      SplitMode = "equal"
+	SplitExact      SplitMode = "exact"
+	SplitPercentage SplitMode = "percentage"
+	SplitShares     SplitMode = "shares"
+)
+
+func (m SplitMode) IsValid() bool {
+	switch m {
+	case SplitEqual,
+		SplitExact,
+		SplitPercentage,
+		SplitShares:
 		return true
 	}
 	return false
 }
 
+type PaymentMethod string
+
+const (
+	PaymentCash       PaymentMethod = "cash"
+	PaymentTransfer   PaymentMethod = "transfer"
+	PaymentCard       PaymentMethod = "card"
+	PaymentSettlement PaymentMethod = "settlement"
+	PaymentOther      PaymentMethod = "other"
+)
+
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case PaymentCash,
+		PaymentTransfer,
+		PaymentCard,
+		PaymentSettlement,
+		PaymentOther:
+		return true
+	}
+	return false
+}
+
+// Expense.Amount is in the household base currency and is what shares are
+// split from; OriginalAmount is the amount in the currency it was entered in.
+// CreatedBy is who entered the expense, Payers are who paid for it. Expenses
+// materialized from a recurring expense record which occurrence they are, and
+// each occurrence exists at most once.
 type Expense struct {
-	ID          uint            `gorm:"primaryKey" json:"id"`
-	Name        string          `json:"name"`
-	Amount      float64         `json:"amount"`
-	Category    ExpenseCategory `json:"category"`
-	CreatedOn   time.Time       `json:"created_on"`
-	CreatedByID uint            `json:"created_by_id"`
-	CreatedBy   User            `gorm:"foreignKey:CreatedByID" json:"created_by"`
-	HouseholdID uint            `json:"household_id"`
-	Household   Household       `gorm:"foreignKey:HouseholdID" json:"household"`
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	Name               string         `json:"name"`
+	Amount             money.Money    `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	OriginalAmount     money.Money    `gorm:"embedded;embeddedPrefix:original_amount_" json:"original_amount"`
+	CategoryID         uint           `gorm:"index" json:"category_id"`
+	Category           Category       `gorm:"foreignKey:CategoryID" json:"category"`
+	SplitMode          SplitMode      `json:"split_mode"`
+	CreatedOn          time.Time      `json:"created_on"`
+	CreatedByID        uint           `json:"created_by_id"`
+	CreatedBy          User           `gorm:"foreignKey:CreatedByID" json:"created_by"`
+	HouseholdID        uint           `json:"household_id"`
+	Household          Household      `gorm:"foreignKey:HouseholdID" json:"household"`
+	Payers             []ExpensePayer `gorm:"foreignKey:ExpenseID" json:"payers"`
+	RecurringExpenseID *uint          `gorm:"uniqueIndex:idx_expense_occurrence" json:"recurring_expense_id"`
+	OccurrenceOn       *time.Time     `gorm:"uniqueIndex:idx_expense_occurrence" json:"occurrence_on"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ImportedExpense is an expense read from an import file, already split into
+// its shares.
+type ImportedExpense struct {
+	Expense Expense
+	Shares  []ExpenseShare
+}
+
+// ImportFields are the expense fields that columns of an import file can be
+// mapped to. Name and amount must be mapped; the others have defaults.
+var ImportFields = []string{"name", "amount", "date", "category", "payer"}
+
+// ImportRow is a row of an import file as previewed: the values of its mapped
+// columns by field, and what is wrong with them.
+type ImportRow struct {
+	Line   int
+	Values map[string]string
+	Errors []string
+}
+
+// ExpensePayer is a member who paid for an expense, and how much of it. The
+// amounts of the payers of an expense add up to its Amount.
+type ExpensePayer struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	ExpenseID uint        `gorm:"index" json:"expense_id"`
+	UserID    uint        `json:"user_id"`
+	User      User        `gorm:"foreignKey:UserID" json:"user"`
+	Amount    money.Money `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+}
+
+// IsPayer reports whether userID paid part of the expense. It needs Payers
+// loaded.
+func (e Expense) IsPayer(userID uint) bool {
+	for _, p := range e.Payers {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// OwedTo splits amount, outstanding on one of shares, between the payers of
+// the expense. Each payer is owed in proportion to what they paid beyond their
+// own share, so the parts are in the order of Payers and a payer whose payment
+// only covered their own share gets nothing. shares must be the shares of e.
+func (e Expense) OwedTo(amount money.Money, shares []ExpenseShare) []money.Money {
+	covered := make(map[uint]int64, len(shares))
+	for _, s := range shares {
+		covered[s.UserID] += s.Covered.Minor
+	}
+
+	weights := make([]int64, len(e.Payers))
+	var total int64
+	for i, p := range e.Payers {
+		weights[i] = max(p.Amount.Minor-covered[p.UserID], 0)
+		total += weights[i]
+	}
+
+	if total == 0 {
+		for i, p := range e.Payers {
+			weights[i] = p.Amount.Minor
+		}
+	}
+
+	return amount.Allocate(weights)
+}
+
+type Frequency string
+
+const (
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyYearly  Frequency = "yearly"
+	FrequencyCron    Frequency = "cron"
+)
+
+// RecurringExpense is a template that the scheduler turns into an expense
+// every time it falls due. Amount is kept in the currency it was entered in
+// and converted into the household currency on the day of each occurrence.
+//
+// Day is the day of the month for monthly and yearly schedules and the
+// weekday, 0 being Sunday, for weekly ones. Month is only used by yearly
+// schedules and Cron only by cron ones. NextRunOn is the first occurrence
+// that has not been materialized yet.
+type RecurringExpense struct {
+	ID          uint                    `gorm:"primaryKey" json:"id"`
+	Name        string                  `json:"name"`
+	Amount      money.Money             `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	CategoryID  uint                    `json:"category_id"`
+	Category    Category                `gorm:"foreignKey:CategoryID" json:"category"`
+	SplitMode   SplitMode               `json:"split_mode"`
+	Splits      []RecurringExpenseSplit `gorm:"foreignKey:RecurringExpenseID" json:"splits"`
+	Frequency   Frequency               `json:"frequency"`
+	Day         int                     `json:"day"`
+	Month       int                     `json:"month"`
+	Cron        string                  `json:"cron"`
+	NextRunOn   time.Time               `gorm:"index" json:"next_run_on"`
+	PaidByID    uint                    `json:"paid_by_id"`
+	PaidBy      User                    `gorm:"foreignKey:PaidByID" json:"paid_by"`
+	HouseholdID uint                    `gorm:"index" json:"household_id"`
+	Household   Household               `gorm:"foreignKey:HouseholdID" json:"household"`
+	CreatedByID uint                    `json:"created_by_id"`
+	CreatedBy   User                    `gorm:"foreignKey:CreatedByID" json:"created_by"`
+	DeletedAt   gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// RecurringExpenseSplit is the split value of one member as entered for the
+// split mode, e.g. a percentage. Equal splits have none and are divided
+// between whoever is a member when the expense falls due.
+type RecurringExpenseSplit struct {
+	ID                 uint   `gorm:"primaryKey" json:"id"`
+	RecurringExpenseID uint   `gorm:"index" json:"recurring_expense_id"`
+	UserID             uint   `json:"user_id"`
+	Value              string `json:"value"`
+}
+
+// Schedule returns the rule the recurring expense falls due by.
+func (r RecurringExpense) Schedule() (schedule.Schedule, error) {
+	switch r.Frequency {
+	case FrequencyWeekly:
+		return schedule.Weekly(time.Weekday(r.Day))
+	case FrequencyMonthly:
+		return schedule.Monthly(r.Day)
+	case FrequencyYearly:
+		return schedule.Yearly(time.Month(r.Month), r.Day)
+	case FrequencyCron:
+		return schedule.Cron(r.Cron)
+	}
+	return nil, schedule.ErrInvalid
+}
+
+type BudgetPeriod string
+
+const (
+	BudgetMonthly BudgetPeriod = "monthly"
+	BudgetCustom  BudgetPeriod = "custom"
+)
+
+func (p BudgetPeriod) IsValid() bool {
+	switch p {
+	case BudgetMonthly, BudgetCustom:
+		return true
+	}
+	return false
+}
+
+// Budget caps what a household spends on one category. A monthly budget
+// starts over every calendar month; a custom one covers StartsOn through
+// EndsOn, both inclusive. Limit is in the household currency.
+type Budget struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	HouseholdID uint           `gorm:"index" json:"household_id"`
+	Household   Household      `gorm:"foreignKey:HouseholdID" json:"household"`
+	CategoryID  uint           `json:"category_id"`
+	Category    Category       `gorm:"foreignKey:CategoryID" json:"category"`
+	Limit       money.Money    `gorm:"embedded;embeddedPrefix:limit_" json:"limit"`
+	Period      BudgetPeriod   `json:"period"`
+	StartsOn    time.Time      `json:"starts_on"`
+	EndsOn      time.Time      `json:"ends_on"`
+	CreatedByID uint           `json:"created_by_id"`
+	CreatedBy   User           `gorm:"foreignKey:CreatedByID" json:"created_by"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// PeriodOn returns the period of the budget that on falls in, or would fall
+// in for a custom budget, as the half-open range [from, to).
+func (b Budget) PeriodOn(on time.Time) (from, to time.Time) {
+	if b.Period == BudgetCustom {
+		return b.StartsOn, b.EndsOn.AddDate(0, 0, 1)
+	}
+	from = time.Date(on.Year(), on.Month(), 1, 0, 0, 0, 0, on.Location())
+	return from, from.AddDate(0, 1, 0)
+}
+
+// IsActive reports whether on falls within a period of the budget. Monthly
+// budgets are always active.
+func (b Budget) IsActive(on time.Time) bool {
+	from, to := b.PeriodOn(on)
+	return !on.Before(from) && on.Before(to)
+}
+
+// Budget usage thresholds, in percent of the limit.
+const (
+	BudgetWarningPercent  = 80
+	BudgetExceededPercent = 100
+)
+
+// BudgetUsage is what was spent against a budget in one of its periods.
+// Usages are not stored.
+type BudgetUsage struct {
+	Budget Budget      `json:"budget"`
+	Spent  money.Money `json:"spent"`
+}
+
+// Percent is the share of the limit spent, rounded down.
+func (u BudgetUsage) Percent() int64 {
+	if u.Budget.Limit.Minor <= 0 {
+		return 0
+	}
+	return u.Spent.Minor * 100 / u.Budget.Limit.Minor
+}
+
+// IsWarning reports whether the usage reached BudgetWarningPercent without
+// exceeding the budget.
+func (u BudgetUsage) IsWarning() bool {
+	p := u.Percent()
+	return p >= BudgetWarningPercent && p < BudgetExceededPercent
+}
+
+// IsExceeded reports whether the whole limit was used up.
+func (u BudgetUsage) IsExceeded() bool {
+	return u.Percent() >= BudgetExceededPercent
+}
+
+type ExpenseAuditAction string
+
+const (
+	AuditCreated ExpenseAuditAction = "created"
+	AuditUpdated ExpenseAuditAction = "updated"
+	AuditDeleted ExpenseAuditAction = "deleted"
+)
+
+// ExpenseAudit records who created, changed or deleted an expense. The name is
+// copied so the entry stays readable after the expense is deleted.
+type ExpenseAudit struct {
+	ID          uint               `gorm:"primaryKey" json:"id"`
+	ExpenseID   uint               `gorm:"index" json:"expense_id"`
+	ExpenseName string             `json:"expense_name"`
+	HouseholdID uint               `gorm:"index" json:"household_id"`
+	UserID      uint               `json:"user_id"`
+	User        User               `gorm:"foreignKey:UserID" json:"user"`
+	Action      ExpenseAuditAction `json:"action"`
+	Details     string             `json:"details"`
+	CreatedOn   time.Time          `json:"created_on"`
+}
+
+// ErrExpenseHasPayments is returned when deleting an expense that payments
+// were already recorded against.
+var ErrExpenseHasPayments = errors.New("expense has recorded payments")
+
+// ExpenseShare is the part of an expense a member owes. Covered is the part of
+// Amount the member paid themselves as one of the payers of the expense, so it
+// is settled from the start.
 type ExpenseShare struct {
-	ID        uint    `gorm:"primaryKey" json:"id"`
-	ExpenseID uint    `json:"expense_id"`
-	Expense   Expense `gorm:"foreignKey:ExpenseID" json:"expense"`
-	UserID    uint    `json:"user_id"`
-	User      User    `gorm:"foreignKey:UserID" json:"user"`
-	Amount    float64 `json:"amount"`
-	Paid      bool    `json:"paid"`
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ExpenseID uint           `json:"expense_id"`
+	Expense   Expense        `gorm:"foreignKey:ExpenseID" json:"expense"`
+	UserID    uint           `json:"user_id"`
+	User      User           `gorm:"foreignKey:UserID" json:"user"`
+	Amount    money.Money    `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	Covered   money.Money    `gorm:"embedded;embeddedPrefix:covered_" json:"covered"`
+	Paid      bool           `json:"paid"`
+	Payments  []Payment      `gorm:"foreignKey:ExpenseShareID" json:"payments"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// PaidAmount is the sum of the payments loaded into Payments.
+func (s ExpenseShare) PaidAmount() money.Money {
+	return money.Sum(s.Amount.Currency, paymentAmounts(s.Payments))
 }
 
+// Outstanding is the part of the share not yet covered by what its user paid
+// for the expense or by payments, never less than zero.
+func (s ExpenseShare) Outstanding() money.Money {
+	left := s.Amount.Sub(s.Covered).Sub(s.PaidAmount())
+	if left.Minor < 0 {
+		return money.New(0, s.Amount.Currency)
+	}
+	return left
+}
+
+// Locked reports whether payments were recorded against the share. A locked
+// share keeps its amount when the expense is edited.
+func (s ExpenseShare) Locked() bool {
+	return len(s.Payments) > 0
+}
+
+// ErrOverpayment is returned when payments would take a share beyond its
+// amount.
+var ErrOverpayment = errors.New("payment exceeds the share amount")
+
+// Payment is money handed from the member who owes a share to a member who
+// paid the expense. A share may be settled by several partial payments; Paid
+// on the share is set once they and Covered cover its amount.
+type Payment struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	ExpenseShareID uint          `gorm:"index" json:"expense_share_id"`
+	ExpenseShare   ExpenseShare  `gorm:"foreignKey:ExpenseShareID" json:"-"`
+	PayerID        uint          `json:"payer_id"`
+	Payer          User          `gorm:"foreignKey:PayerID" json:"payer"`
+	PayeeID        uint          `json:"payee_id"`
+	Payee          User          `gorm:"foreignKey:PayeeID" json:"payee"`
+	Amount         money.Money   `gorm:"embedded;embeddedPrefix:amount_" json:"amount"`
+	PaidOn         time.Time     `json:"paid_on"`
+	Method         PaymentMethod `json:"method"`
+	Note           string        `json:"note"`
+}
+
+func paymentAmounts(payments []Payment) []money.Money {
+	amounts := make([]money.Money, len(payments))
+	for i, p := range payments {
+		amounts[i] = p.Amount
+	}
+	return amounts
+}
+
+// Report is a generated file of expense shares in a period. A personal report
+// covers the shares of UserID; a household report, with HouseholdID set,
+// covers every share in that household and UserID is who generated it.
+// Reports are generated in the background: a report is recorded as queued
+// and its PDF and total only exist once it is done.
 type Report struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	UserID         uint      `json:"user_id"`
-	User           User      `gorm:"foreignKey:UserID" json:"user"`
-	PeriodStart    time.Time `json:"period_start"`
-	PeriodEnd      time.Time `json:"period_end"`
-	TotalExpenses  float64   `json:"total_expenses"`
-	PaymentStatus  string    `json:"payment_status"`
-	GenerationDate time.Time `json:"generation_date"`
-	FileName       string    `json:"file_name"`
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	UserID         uint         `json:"user_id"`
+	User           User         `gorm:"foreignKey:UserID" json:"user"`
+	HouseholdID    *uint        `gorm:"index" json:"household_id"`
+	Household      *Household   `gorm:"foreignKey:HouseholdID" json:"household"`
+	PeriodStart    time.Time    `json:"period_start"`
+	PeriodEnd      time.Time    `json:"period_end"`
+	TotalExpenses  money.Money  `gorm:"embedded;embeddedPrefix:total_expenses_" json:"total_expenses"`
+	PaymentStatus  string       `json:"payment_status"`
+	Filter         ReportFilter `gorm:"embedded;embeddedPrefix:filter_" json:"filter"`
+	GenerationDate time.Time    `json:"generation_date"`
+	FinishedAt     *time.Time   `gorm:"index" json:"finished_at"`
+	FileName       string       `json:"file_name"`
+	Format         ReportFormat `json:"format"`
+	ScheduleID     *uint        `gorm:"index" json:"schedule_id"`
+	Status         ReportStatus `gorm:"index" json:"status"`
+	Attempts       int          `json:"attempts"`
+	RunAfter       time.Time    `json:"run_after"`
+	Error          string       `json:"error"`
+}
+
+// IsAutomatic reports whether the report was generated by a report schedule
+// rather than requested by hand.
+func (r Report) IsAutomatic() bool {
+	return r.ScheduleID != nil
+}
+
+// NewFileName returns a name for a file of the report written at now. A
+// household report is named after its household, a personal report after
+// its user.
+func (r Report) NewFileName(now time.Time) string {
+	if r.HouseholdID != nil {
+		return fmt.Sprintf("household_report_%d_%d.%s", *r.HouseholdID, now.UnixNano(), r.Format)
+	}
+	return fmt.Sprintf("report_%d_%d.%s", r.UserID, now.UnixNano(), r.Format)
+}
+
+// ReportSchedule generates a personal report of UserID automatically every
+// month on Day, covering the previous calendar month with its payment status,
+// filter and format. Days past the end of a short month fall on its last day.
+type ReportSchedule struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	UserID        uint           `gorm:"index" json:"user_id"`
+	User          User           `gorm:"foreignKey:UserID" json:"user"`
+	Day           int            `json:"day"`
+	PaymentStatus string         `json:"payment_status"`
+	Filter        ReportFilter   `gorm:"embedded;embeddedPrefix:filter_" json:"filter"`
+	Format        ReportFormat   `json:"format"`
+	NextRunOn     time.Time      `gorm:"index" json:"next_run_on"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Schedule returns the rule the report schedule runs by.
+func (s ReportSchedule) Schedule() (schedule.Schedule, error) {
+	return schedule.Monthly(s.Day)
+}
+
+// Report returns the report the schedule generates when it runs on runOn:
+// the calendar month before runOn, up to its last instant.
+func (s ReportSchedule) Report(runOn time.Time) Report {
+	runOn = runOn.UTC()
+	start := time.Date(runOn.Year(), runOn.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+
+	return Report{
+		UserID:        s.UserID,
+		PeriodStart:   start,
+		PeriodEnd:     start.AddDate(0, 1, 0).Add(-time.Nanosecond),
+		PaymentStatus: s.PaymentStatus,
+		Filter:        s.Filter,
+		Format:        s.Format,
+		ScheduleID:    &s.ID,
+	}
+}
+
+// ReportFormat is the file format a report is generated in.
+type ReportFormat string
+
+const (
+	ReportPDF  ReportFormat = "pdf"
+	ReportCSV  ReportFormat = "csv"
+	ReportXLSX ReportFormat = "xlsx"
+	ReportHTML ReportFormat = "html"
+)
+
+var ReportFormats = []ReportFormat{ReportPDF, ReportCSV, ReportXLSX, ReportHTML}
+
+func (f ReportFormat) IsValid() bool {
+	return slices.Contains(ReportFormats, f)
+}
+
+// ReportStatus is how far the generation of a report has got. A queued report
+// waits for a worker, from its RunAfter on; a failed attempt puts it back in
+// the queue until it runs out of attempts and fails for good.
+type ReportStatus string
+
+const (
+	ReportQueued  ReportStatus = "queued"
+	ReportRunning ReportStatus = "running"
+	ReportDone    ReportStatus = "done"
+	ReportFailed  ReportStatus = "failed"
+)
+
+// IsPending reports whether the report is still waiting for or being
+// generated.
+func (s ReportStatus) IsPending() bool {
+	return s == ReportQueued || s == ReportRunning
+}
+
+// ReportFilter narrows the shares a report covers, on top of its period and
+// payment status. An empty list or amount does not narrow anything. Amounts
+// are decimals in major units, compared with each share in its own currency.
+type ReportFilter struct {
+	HouseholdIDs []uint `gorm:"type:text;serializer:json" json:"household_ids"`
+	CategoryIDs  []uint `gorm:"type:text;serializer:json" json:"category_ids"`
+	MinAmount    string `json:"min_amount"`
+	MaxAmount    string `json:"max_amount"`
+}
+
+// IsEmpty reports whether the filter lets every share through.
+func (f ReportFilter) IsEmpty() bool {
+	return len(f.HouseholdIDs) == 0 && len(f.CategoryIDs) == 0 && f.MinAmount == "" && f.MaxAmount == ""
+}
+
+// Matches reports whether share passes the filter. A category matches its
+// subcategories too. It needs the expense of share with its category loaded.
+func (f ReportFilter) Matches(share ExpenseShare) bool {
+	if len(f.HouseholdIDs) > 0 && !slices.Contains(f.HouseholdIDs, share.Expense.HouseholdID) {
+		return false
+	}
+
+	if len(f.CategoryIDs) > 0 {
+		c := share.Expense.Category
+		inParent := c.ParentID != nil && slices.Contains(f.CategoryIDs, *c.ParentID)
+		if !slices.Contains(f.CategoryIDs, c.ID) && !inParent {
+			return false
+		}
+	}
+
+	// Bounds are compared in hundredths so that they mean the same in
+	// currencies without minor units.
+	amount := share.Amount.Minor
+	for range 2 - money.Exponent(share.Amount.Currency) {
+		amount *= 10
+	}
+
+	if lower, err := money.ParseDecimal(f.MinAmount, 2); f.MinAmount != "" && err == nil && amount < lower {
+		return false
+	}
+
+	if upper, err := money.ParseDecimal(f.MaxAmount, 2); f.MaxAmount != "" && err == nil && amount > upper {
+		return false
+	}
+
+	return true
+}
+
+// ExchangeRate is the price of one unit of FromCurrency in ToCurrency on
+// ValidOn, scaled by money.RateScale. A rate stays in effect until a newer one
+// for the same pair is recorded.
+type ExchangeRate struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	FromCurrency string    `gorm:"size:3;uniqueIndex:idx_exchange_rate_pair_date" json:"from_currency"`
+	ToCurrency   string    `gorm:"size:3;uniqueIndex:idx_exchange_rate_pair_date" json:"to_currency"`
+	ValidOn      time.Time `gorm:"uniqueIndex:idx_exchange_rate_pair_date" json:"valid_on"`
+	Rate         int64     `json:"rate"`
+}
+
+var ErrNoExchangeRate = errors.New("no exchange rate")
+
+// Balance is a member's net position in a household, computed from unpaid
+// shares: positive when others owe the member money, negative when the member
+// owes others. Balances are not stored.
+type Balance struct {
+	User User        `json:"user"`
+	Net  money.Money `json:"net"`
+}
+
+// Transfer is a single payment that helps settle a household.
+type Transfer struct {
+	From   User        `json:"from"`
+	To     User        `json:"to"`
+	Amount money.Money `json:"amount"`
 }
 
 type UserStore interface {
@@ -112,7 +825,8 @@ type SessionStore interface {
 }
 
 type HouseholdStore interface {
-	CreateHousehold(name string, description string, createdByID uint) (uint, error)
+	CreateHousehold(name string, description string, baseCurrency string, createdByID uint) (uint, error)
+	GetHouseholdByID(householdID uint) (Household, error)
 	GetHouseholdsByUserID(userID uint) ([]Household, error)
 	GetOwnedHouseholdsByUserID(userID uint) ([]Household, error)
 	NameExists(name string) (bool, error)
@@ -121,23 +835,115 @@ type HouseholdStore interface {
 type MembershipStore interface {
 	CreateMembership(userID uint, householdID uint, role string) error
 	GetMembersByHouseholdID(householdID uint) ([]Membership, error)
+	GetMembership(householdID uint, userID uint) (Membership, error)
+	UpdateMembershipRole(householdID uint, userID uint, role string) error
+	DeleteMembership(householdID uint, userID uint) error
+	TransferOwnership(householdID uint, fromUserID uint, toUserID uint) error
+}
+
+type InvitationStore interface {
+	CreateInvitation(householdID uint, invitedByID uint, email string, expiresOn time.Time) (Invitation, error)
+	GetInvitationByToken(token string) (Invitation, error)
+	GetOpenInvitationsByEmail(email string) ([]Invitation, error)
+	GetOpenInvitationsByHouseholdID(householdID uint) ([]Invitation, error)
+	AcceptInvitation(token string, userID uint) error
+	DeclineInvitation(token string) error
 }
 
 type ExpenseStore interface {
-	CreateExpense(name string, amount float64, category ExpenseCategory, createdOn time.Time, householdID, createdByID uint) (uint, error)
+	CreateExpense(name string, amount money.Money, originalAmount money.Money, categoryID uint, splitMode SplitMode, createdOn time.Time, householdID, createdByID uint, payers []ExpensePayer) (uint, error)
 	NameExists(name string) (bool, error)
+	GetExpenseByID(expenseID uint) (Expense, error)
 	GetExpensesByHouseholdID(householdID uint) ([]Expense, error)
+	UpdateExpense(expense Expense, shares []ExpenseShare, editedByID uint, details string) error
+	DeleteExpense(expenseID uint, deletedByID uint) error
+	GetExpenseAuditsByHouseholdID(householdID uint) ([]ExpenseAudit, error)
+	ImportExpenses(expenses []ImportedExpense) error
 }
 
 type ExpenseShareStore interface {
-	CreateExpenseShare(expenseID uint, userID uint, amount float64) error
+	CreateExpenseShare(expenseID uint, userID uint, amount money.Money, covered money.Money) error
 	GetExpenseShare(expenseID uint, userID uint) (ExpenseShare, error)
+	GetSharesByExpenseID(expenseID uint) ([]ExpenseShare, error)
 	GetExpensesByUserID(userID uint) ([]ExpenseShare, error)
+	GetSharesByHouseholdID(householdID uint) ([]ExpenseShare, error)
+	GetSharesByUserIDInPeriod(userID uint, from, to time.Time, paymentStatus string) ([]ExpenseShare, error)
+	GetSharesByHouseholdIDInPeriod(householdID uint, from, to time.Time, paymentStatus string) ([]ExpenseShare, error)
 	UpdateExpenseShare(share ExpenseShare) error
 }
 
+type RecurringExpenseStore interface {
+	CreateRecurringExpense(recurring RecurringExpense) (uint, error)
+	GetRecurringExpenseByID(recurringExpenseID uint) (RecurringExpense, error)
+	GetRecurringExpensesByHouseholdID(householdID uint) ([]RecurringExpense, error)
+	GetDueRecurringExpenses(now time.Time) ([]RecurringExpense, error)
+	DeleteRecurringExpense(recurringExpenseID uint) error
+	MaterializeRecurringExpense(expense Expense, shares []ExpenseShare, nextRunOn time.Time) error
+}
+
+type CategoryStore interface {
+	CreateCategory(category Category) (uint, error)
+	GetCategoryByID(categoryID uint) (Category, error)
+	GetCategoriesByHouseholdID(householdID uint) ([]Category, error)
+	UpdateCategory(category Category) error
+}
+
+type BudgetStore interface {
+	CreateBudget(budget Budget) (uint, error)
+	GetBudgetByID(budgetID uint) (Budget, error)
+	GetBudgetsByHouseholdID(householdID uint) ([]Budget, error)
+	GetBudgetsByUserID(userID uint) ([]Budget, error)
+	DeleteBudget(budgetID uint) error
+}
+
+type PaymentStore interface {
+	CreatePayments(payments []Payment) error
+	GetPaymentsByUserID(userID uint, from, to time.Time) ([]Payment, error)
+}
+
+type ExchangeRateStore interface {
+	SaveExchangeRates(rates []ExchangeRate) error
+	GetExchangeRates() ([]ExchangeRate, error)
+	Convert(amount money.Money, currency string, on time.Time) (money.Money, error)
+}
+
 type ReportStore interface {
-	CreateReport(userID uint, from, to time.Time, paymentStatus string) (Report, error)
+	QueueReport(report Report) (Report, error)
+	QueueScheduledReport(report Report, nextRunOn time.Time) error
+	ClaimNextReport(now time.Time) (Report, error)
+	ReportTotal(shares []ExpenseShare, on time.Time) (money.Money, error)
+	CompleteReport(report Report) error
+	RetryReport(reportID uint, message string, runAfter time.Time) error
+	FailReport(reportID uint, message string) error
+	RequeueRunningReports() error
+	RequeueReport(reportID uint) error
+	DeleteReport(reportID uint) error
+	DeleteReportsBefore(status ReportStatus, before time.Time) ([]Report, error)
+	GetAllReports() ([]Report, error)
+	GetReportByID(reportID uint) (Report, error)
 	GetReportsByUser(userID uint) ([]Report, error)
+	GetReportsByHouseholdID(householdID uint) ([]Report, error)
 	GetReportByFileName(fileName string) (Report, error)
 }
+
+type ReportScheduleStore interface {
+	CreateReportSchedule(schedule ReportSchedule) (uint, error)
+	GetReportScheduleByID(scheduleID uint) (ReportSchedule, error)
+	GetReportSchedulesByUserID(userID uint) ([]ReportSchedule, error)
+	GetDueReportSchedules(now time.Time) ([]ReportSchedule, error)
+	DeleteReportSchedule(scheduleID uint) error
+}
+
+// FileStore keeps generated files, such as the PDFs of reports, by name. A
+// name is a single path element; Put replaces any file of the same name.
+type FileStore interface {
+	Put(name string, content io.Reader) error
+	Get(name string) (io.ReadCloser, error)
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+var (
+	ErrFileNotFound    = errors.New("file not found")
+	ErrInvalidFileName = errors.New("invalid file name")
+)
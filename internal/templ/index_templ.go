@@ -37,14 +37,14 @@ func Home(user *store.User, isHX bool) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div class=\"flex flex-col h-full w-full justify-center items-center\"><div class=\"flex flex-col h-full w-full items-center justify-center rounded-radius overflow-hidden border border-outline bg-surface-alt text-on-surface dark:border-outline-dark dark:bg-surface-dark-alt dark:text-on-surface-dark p-4 gap-3 text-center\"><div class=\"h-3/4 w-full flex items-center justify-center\"><img src=\"/static/img/home-image.png\" class=\"max-h-full max-w-full object-contain rounded-radius shadow-lg\" alt=\"Home illustration\"></div><div class=\"flex-1 w-full flex flex-col items-center justify-center gap-3\"><h3 class=\"text-xl sm:text-2xl font-bold text-on-surface-strong dark:text-on-surface-dark-strong\">Welcome ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div class=\"flex flex-col h-full w-full justify-center items-center\"><div hx-get=\"/budgets/warnings\" hx-trigger=\"load\" hx-swap=\"outerHTML\"></div><div class=\"flex flex-col h-full w-full items-center justify-center rounded-radius overflow-hidden border border-outline bg-surface-alt text-on-surface dark:border-outline-dark dark:bg-surface-dark-alt dark:text-on-surface-dark p-4 gap-3 text-center\"><div class=\"h-3/4 w-full flex items-center justify-center\"><img src=\"/static/img/home-image.png\" class=\"max-h-full max-w-full object-contain rounded-radius shadow-lg\" alt=\"Home illustration\"></div><div class=\"flex-1 w-full flex flex-col items-center justify-center gap-3\"><h3 class=\"text-xl sm:text-2xl font-bold text-on-surface-strong dark:text-on-surface-dark-strong\">Welcome ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(user.Username)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/index.templ`, Line: 16, Col: 28}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/index.templ`, Line: 17, Col: 28}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -73,14 +73,14 @@ func sidebar(user *store.User) templ.Component {
 			templ_7745c5c3_Var3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<a class=\"sr-only\" href=\"#main-content\">skip to the main content</a><div x-cloak x-show=\"showSidebar\" class=\"fixed inset-0 z-10 bg-surface-dark/10 backdrop-blur-xs md:hidden\" aria-hidden=\"true\" x-on:click=\"showSidebar = false\" x-transition.opacity></div><nav x-cloak class=\"fixed left-0 z-20 flex h-svh w-56 shrink-0 flex-col border-r border-outline bg-surface-alt p-4 transition-transform duration-300 md:w-56 md:translate-x-0 md:relative dark:border-outline-dark dark:bg-surface-dark-alt\" x-bind:class=\"showSidebar ? 'translate-x-0' : '-translate-x-56'\" aria-label=\"sidebar navigation\"><a href=\"/\" class=\"p-2 w-fit text-2xl font-bold text-on-surface-strong dark:text-on-surface-dark-strong\"><span class=\"sr-only\">homepage</span> <svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 408 95\" class=\"w-full max-w-lg h-auto\" role=\"img\"><path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 487.310531 588.587738 C 483.900653 587.048676 483.799215 586.806488 480.046007 571.533051 C 478.21232 564.048676 477.75975 563.275238 472.648833 558.790863 C 461.935416 549.392426 455.061039 535.337738 455.022024 522.751801 C 455.006418 517.345551 454.694301 516.001801 453.461439 516.001801 C 452.61092 516.001801 450.152998 515.103363 447.999391 514.001801 C 445.845783 512.900238 442.599766 511.978363 440.789487 511.947113 C 438.979208 511.915863 435.928264 511.353363 434.000942 510.697113 C 431.254312 509.759613 430.497428 508.962738 430.497428 507.001801 C 430.497428 504.978363 430.973406 504.517426 433.002167 504.587738 C 434.375482 504.642426 436.177958 504.861176 436.997265 505.087738 C 437.972631 505.353363 438.331566 504.970551 438.019449 504.001801 C 437.754149 503.173676 437.527864 500.392426 437.527864 497.822113 C 437.512258 492.626801 440.251085 488.658051 444.605118 487.564301 C 447.460989 486.845551 452.501679 489.204926 453.968629 491.947113 C 456.325113 496.345551 454.522637 504.439301 450.55875 507.251801 C 449.856487 507.751801 450.004743 508.329926 451.003517 508.986176 C 454.062264 510.993988 455.521411 510.025238 457.386311 504.751801 C 462.029051 491.634613 473.772455 479.611176 489.49535 471.900238 C 494.98861 469.204926 500.278994 467.001801 501.246557 467.001801 C 503.977581 467.001801 503.322135 465.361176 499.670366 463.103363 C 494.80134 460.095551 490.993512 452.509613 491.024724 445.861176 C 491.102753 425.876801 513.832676 416.736176 527.792111 431.079926 C 535.134664 438.634613 535.345343 452.126801 528.24468 460.228363 C 526.332963 462.408051 526.925986 462.626801 536.999563 463.439301 C 541.127311 463.775238 547.252608 464.556488 550.623472 465.173676 C 556.514681 466.259613 556.873615 466.197113 560.174253 463.681488 C 568.226872 457.540863 584.230673 452.829926 585.533762 456.220551 C 585.845879 457.040863 585.18263 461.236176 584.051206 465.540863 C 582.927585 469.853363 581.999036 474.228363 581.999036 475.267426 C 581.999036 476.298676 585.081192 480.150238 588.850005 483.822113 C 592.634424 487.517426 597.214742 493.267426 599.095247 496.697113 C 599.095247 496.697113 602.497323 502.900238 602.497323 502.900238 C 602.497323 502.900238 609.270262 504.564301 609.270262 504.564301 C 618.485518 506.822113 619.016117 507.751801 618.94589 521.470551 C 618.914679 527.540863 618.352868 534.072113 617.697422 536.001801 C 616.589407 539.243988 615.855932 539.720551 607.818918 542.501801 C 599.937963 545.220551 598.767524 545.978363 595.131361 550.642426 C 591.596635 555.181488 586.532536 559.970551 579.213392 565.712738 C 577.668413 566.923676 576.170251 570.243988 574.570651 576.001801 C 571.270013 587.853363 571.270013 587.845551 566.650681 589.103363 C 562.655583 590.181488 549.913405 589.681488 547.767601 588.361176 C 547.229199 588.025238 545.980731 585.939301 544.989759 583.728363 C 544.989759 583.728363 543.187283 579.704926 543.187283 579.704926 C 543.187283 579.704926 529.84428 579.486176 529.84428 579.486176 C 522.501727 579.361176 515.68197 579.087738 514.675392 578.884613 C 513.317683 578.595551 512.513982 579.486176 511.499601 582.400238 C 509.299176 588.689301 507.941467 589.470551 498.718409 589.759613 C 493.217346 589.939301 489.448533 589.548676 487.310531 588.587738 M 506.552546 577.751801 C 506.552546 577.751801 508.58911 572.501801 508.58911 572.501801 C 508.58911 572.501801 513.543968 572.728363 513.543968 572.728363 C 521.370303 573.079926 541.244355 573.126801 543.998788 572.798676 C 546.144592 572.540863 546.862461 573.259613 549.000463 577.775238 C 549.000463 577.775238 551.497399 583.056488 551.497399 583.056488 C 551.497399 583.056488 558.910179 582.775238 558.910179 582.775238 C 558.910179 582.775238 566.315155 582.501801 566.315155 582.501801 C 566.315155 582.501801 568.921333 572.611176 568.921333 572.611176 C 568.921333 572.611176 571.52751 562.728363 571.52751 562.728363 C 571.52751 562.728363 577.012967 558.962738 577.012967 558.962738 C 583.052431 554.814301 588.366224 549.603363 592.720257 543.564301 C 595.154769 540.197113 596.723158 539.251801 603.535112 537.064301 C 603.535112 537.064301 611.501899 534.501801 611.501899 534.501801 C 611.501899 534.501801 611.782804 523.228363 611.782804 523.228363 C 611.993483 514.736176 611.751593 511.868988 610.78403 511.572113 C 596.762172 507.353363 597.753144 507.986176 594.085769 501.001801 C 591.908752 496.845551 587.843428 491.704926 582.857358 486.775238 C 574.242928 478.259613 574.718907 479.853363 577.090996 467.603363 C 577.613792 464.908051 577.871289 462.540863 577.66061 462.329926 C 576.88812 461.556488 567.524609 466.181488 562.733613 469.689301 C 562.733613 469.689301 557.802163 473.314301 557.802163 473.314301 C 557.802163 473.314301 550.147493 471.439301 550.147493 471.439301 C 539.60574 468.845551 518.29595 468.806488 508.503278 471.353363 C 489.113007 476.400238 472.313308 488.525238 465.454536 502.400238 C 456.863514 519.775238 460.882021 538.087738 476.66734 553.517426 C 476.66734 553.517426 483.846032 560.540863 483.846032 560.540863 C 483.846032 560.540863 486.491224 570.978363 486.491224 570.978363 C 487.950371 576.720551 489.362701 581.775238 489.628 582.212738 C 489.901103 582.642426 493.357799 583.001801 497.313882 583.001801 C 497.313882 583.001801 504.515983 583.001801 504.515983 583.001801 C 504.515983 583.001801 506.552546 577.751801 506.552546 577.751801 M 448.998165 497.970551 C 448.998165 496.470551 448.280296 494.642426 447.390762 493.908051 C 446.02525 492.775238 445.572681 492.861176 444.394439 494.478363 C 442.732416 496.751801 442.662189 497.993988 444.027701 501.564301 C 445.018673 504.181488 445.112308 504.212738 447.024025 502.478363 C 448.108632 501.493988 448.998165 499.462738 448.998165 497.970551 M 519.7629 459.001801 C 530.53874 452.431488 529.227849 435.923676 517.570278 431.470551 C 509.790761 428.501801 501.558674 432.017426 498.367277 439.673676 C 492.686747 453.251801 507.395262 466.540863 519.7629 459.001801 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 119.199701 553.798676 C 117.483057 552.079926 117.483057 485.915863 119.199701 484.197113 C 121.111418 482.290863 124.747581 482.806488 126.440816 485.220551 C 127.665875 486.962738 128.001401 490.322113 128.001401 500.720551 C 128.001401 500.720551 128.001401 514.001801 128.001401 514.001801 C 128.001401 514.001801 147.976891 514.001801 147.976891 514.001801 C 147.976891 514.001801 167.944579 514.001801 167.944579 514.001801 C 167.944579 514.001801 168.225484 499.353363 168.225484 499.353363 C 168.459572 486.954926 168.756083 484.556488 170.168413 483.665863 C 171.36226 482.915863 172.58732 482.947113 174.421007 483.783051 C 174.421007 483.783051 177.003776 484.954926 177.003776 484.954926 C 177.003776 484.954926 177.003776 518.775238 177.003776 518.775238 C 177.003776 554.431488 176.925746 555.001801 172.423458 555.001801 C 168.623433 555.001801 168.069426 552.962738 167.780717 537.931488 C 167.780717 537.931488 167.499812 523.501801 167.499812 523.501801 C 167.499812 523.501801 147.750607 523.228363 147.750607 523.228363 C 147.750607 523.228363 128.001401 522.954926 128.001401 522.954926 C 128.001401 522.954926 128.001401 537.408051 128.001401 537.408051 C 128.001401 549.212738 127.712693 552.142426 126.42521 553.431488 C 124.560311 555.290863 120.885133 555.486176 119.199701 553.798676 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 210.002349 554.048676 C 203.401074 551.783051 196.050718 544.439301 194.201424 538.283051 C 189.488457 522.556488 197.236762 506.603363 211.625358 502.423676 C 228.737174 497.447113 245.138924 508.454926 246.730721 525.986176 C 248.244489 542.626801 236.03291 556.095551 219.568736 555.970551 C 217.329296 555.947113 213.022081 555.087738 210.002349 554.048676 M 228.003699 545.462738 C 234.105587 542.353363 237.000473 536.868988 237.000473 528.392426 C 237.000473 515.572113 227.582341 507.978363 214.676302 510.400238 C 208.223282 511.611176 202.246241 519.962738 202.082379 528.001801 C 201.785868 542.603363 215.480003 551.822113 228.003699 545.462738 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 370.173008 554.158051 C 348.582312 546.447113 346.030756 515.947113 366.029655 504.603363 C 370.84406 501.868988 372.412448 501.501801 379.122964 501.525238 C 385.817875 501.548676 387.331642 501.915863 391.623251 504.564301 C 399.62125 509.493988 405.528065 521.775238 403.600743 529.470551 C 403.600743 529.470551 402.968706 531.962738 402.968706 531.962738 C 402.968706 531.962738 382.751325 532.228363 382.751325 532.228363 C 360.200869 532.533051 359.912161 532.634613 364.211573 538.954926 C 368.09743 544.665863 371.320038 546.392426 378.865468 546.822113 C 384.663042 547.150238 386.410897 546.822113 390.796141 544.603363 C 396.726365 541.595551 398.271344 541.415863 400.027003 543.533051 C 403.702181 547.954926 393.183837 554.892426 381.635506 555.650238 C 377.289277 555.939301 373.949624 555.501801 370.173008 554.158051 M 395.001918 523.814301 C 395.001918 523.165863 394.065567 520.704926 392.92634 518.353363 C 389.571082 511.423676 382.220726 508.072113 374.714311 510.056488 C 370.157402 511.267426 365.655114 514.908051 363.407871 519.197113 C 360.395942 524.978363 360.458365 525.001801 378.49873 525.001801 C 390.546448 525.001801 395.001918 524.681488 395.001918 523.814301 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 745.501545 555.064301 C 740.663731 553.884613 736.520377 551.353363 733.149513 547.517426 C 728.335108 542.040863 726.719902 537.447113 726.602858 528.931488 C 726.517026 522.368988 726.89937 520.579926 729.51335 515.478363 C 736.910524 500.993988 754.34226 496.751801 765.867181 506.618988 C 765.867181 506.618988 770.002732 510.158051 770.002732 510.158051 C 770.002732 510.158051 770.002732 507.275238 770.002732 507.275238 C 770.002732 503.876801 771.594529 502.001801 774.497217 502.001801 C 778.79663 502.001801 778.999506 503.189301 778.999506 528.579926 C 778.999506 554.517426 778.79663 555.556488 773.904195 554.829926 C 771.742785 554.509613 770.68939 553.259613 769.581374 549.697113 C 769.074184 548.072113 768.645023 548.181488 765.258553 550.861176 C 759.562417 555.361176 752.711448 556.822113 745.501545 555.064301 M 762.012536 544.751801 C 767.396555 541.087738 769.323878 537.673676 769.807659 530.978363 C 770.673784 518.978363 764.907421 511.072113 754.646574 510.220551 C 747.951664 509.665863 743.11385 511.673676 739.384051 516.564301 C 736.754465 520.009613 736.496968 521.064301 736.496968 528.423676 C 736.496968 535.337738 736.840297 536.978363 738.892467 539.845551 C 742.513024 544.931488 746.617363 546.993988 753.101595 546.993988 C 757.268357 547.001801 759.546811 546.423676 762.012536 544.751801 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 797.99963 554.025238 C 796.751162 553.236176 796.446848 549.220551 796.197154 530.236176 C 795.861628 504.783051 796.314198 502.001801 800.777472 502.001801 C 804.257577 502.001801 805.997629 503.626801 808.861303 506.626801 C 811.810809 503.806488 819.332829 500.993988 823.905344 501.009613 C 832.293489 501.025238 840.822087 507.095551 842.88206 514.525238 C 843.490688 516.712738 843.990075 526.173676 843.997878 535.548676 C 843.997878 553.439301 843.638943 555.001801 839.503393 555.001801 C 835.430265 555.001801 835.001104 553.337738 834.993302 537.548676 C 834.985499 520.283051 834.142783 516.142426 829.890188 512.564301 C 825.957513 509.251801 817.233842 508.954926 812.302393 511.962738 C 806.731104 515.353363 805.997629 518.079926 805.997629 535.517426 C 805.997629 544.368988 805.552862 551.970551 804.967643 553.064301 C 803.86743 555.126801 800.48096 555.587738 797.99963 554.025238 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 660.246777 553.517426 C 660.246777 553.517426 657.999534 551.884613 657.999534 551.884613 C 657.999534 551.884613 657.999534 519.142426 657.999534 519.142426 C 657.999534 495.009613 658.319454 486.087738 659.201185 485.197113 C 660.051704 484.353363 665.833672 484.009613 678.95039 484.025238 C 698.668384 484.048676 703.006811 484.908051 707.212588 489.642426 C 709.654903 492.392426 712.003584 498.423676 712.003584 501.947113 C 712.003584 506.501801 709.116502 512.759613 705.847076 515.322113 C 705.847076 515.322113 702.920978 517.611176 702.920978 517.611176 C 702.920978 517.611176 706.674186 520.056488 706.674186 520.056488 C 714.859455 525.376801 717.473435 535.228363 713.025767 543.954926 C 708.734158 552.361176 703.233096 554.181488 680.503172 554.720551 C 665.014365 555.079926 662.189705 554.915863 660.246777 553.517426 M 700.681539 543.408051 C 702.234321 542.259613 704.130432 539.540863 704.895119 537.368988 C 706.135784 533.853363 706.104572 533.017426 704.567396 529.790863 C 701.828569 524.009613 698.481114 523.001801 682.102772 523.001801 C 682.102772 523.001801 668.002885 523.001801 668.002885 523.001801 C 668.002885 523.001801 668.002885 534.572113 668.002885 534.572113 C 668.002885 534.572113 668.002885 546.142426 668.002885 546.142426 C 668.002885 546.142426 682.929882 545.822113 682.929882 545.822113 C 696.218265 545.533051 698.168997 545.267426 700.681539 543.408051 M 695.99198 512.509613 C 699.955867 510.454926 702.000233 507.220551 702.000233 503.001801 C 702.000233 494.595551 696.811288 492.001801 680.034997 492.001801 C 680.034997 492.001801 668.002885 492.001801 668.002885 492.001801 C 668.002885 492.001801 668.002885 503.001801 668.002885 503.001801 C 668.002885 503.001801 668.002885 514.001801 668.002885 514.001801 C 668.002885 514.001801 680.54999 514.001801 680.54999 514.001801 C 689.726231 514.001801 693.877387 513.595551 695.99198 512.509613 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 576.201463 507.798676 C 572.80719 504.408051 576.794485 498.751801 581.000262 501.001801 C 584.527184 502.892426 583.005614 509.001801 579.002713 509.001801 C 578.120982 509.001801 576.856908 508.462738 576.201463 507.798676 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 262.032259 553.064301 C 260.495082 550.189301 260.643338 504.751801 262.19612 503.197113 C 262.859369 502.540863 264.677451 502.001801 266.245839 502.001801 C 268.477475 502.001801 269.374812 502.665863 270.381389 505.056488 C 271.559631 507.861176 271.778113 507.970551 273.081202 506.392426 C 275.203598 503.829926 282.015552 501.048676 286.229132 501.025238 C 291.433683 500.993988 297.613601 503.689301 300.344625 507.189301 C 300.344625 507.189301 302.662094 510.142426 302.662094 510.142426 C 302.662094 510.142426 306.126593 506.673676 306.126593 506.673676 C 314.826855 497.970551 329.722641 499.564301 335.762105 509.845551 C 338.430706 514.376801 338.508735 514.970551 338.844261 532.642426 C 339.218801 552.275238 338.688202 555.001801 334.52144 555.001801 C 329.777261 555.001801 328.996968 552.064301 328.996968 534.212738 C 328.996968 517.298676 328.544399 514.939301 324.635133 511.775238 C 321.545174 509.267426 313.984139 509.439301 310.613275 512.087738 C 305.6116 516.017426 305.002971 518.587738 305.002971 535.517426 C 305.002971 544.368988 304.550402 551.970551 303.965182 553.064301 C 302.708911 555.408051 298.745025 555.603363 296.568009 553.431488 C 295.272723 552.126801 294.99962 549.025238 294.99962 535.392426 C 294.99962 517.595551 294.078875 513.181488 289.896507 510.947113 C 286.736322 509.251801 281.016777 509.423676 277.349402 511.322113 C 272.004398 514.087738 270.997821 517.861176 270.997821 535.181488 C 270.997821 547.478363 270.685704 551.001801 269.445038 552.775238 C 267.517716 555.533051 263.436785 555.689301 262.032259 553.064301 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 501.199739 484.798676 C 497.797664 481.392426 501.44163 479.189301 515.010918 476.439301 C 521.081594 475.204926 526.122284 474.884613 533.090297 475.267426 C 542.945393 475.806488 547.002914 477.017426 547.002914 479.415863 C 547.002914 482.322113 544.529386 483.033051 538.40409 481.884613 C 531.092748 480.517426 519.731688 481.368988 510.219921 484.009613 C 501.714732 486.361176 502.674492 486.275238 501.199739 484.798676 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 862.568842 553.431488 C 861.203329 552.064301 861.000453 547.392426 861.000453 517.431488 C 861.000453 484.337738 861.078483 482.923676 868.998452 481.001801 C 870.878958 482.876801 871.003804 484.329926 871.003804 504.501801 C 871.003804 518.689301 871.354936 526.001801 872.041594 526.001801 C 873.024762 526.001801 896.285284 504.962738 897.533752 502.947113 C 898.360862 501.611176 902.371566 501.767426 903.799502 503.197113 C 904.46275 503.861176 905.001152 505.212738 905.001152 506.197113 C 905.001152 508.134613 904.852897 508.290863 894.662276 517.743988 C 894.662276 517.743988 887.936153 523.993988 887.936153 523.993988 C 887.936153 523.993988 896.99535 536.001801 896.99535 536.001801 C 901.98142 542.603363 906.312044 548.642426 906.608555 549.423676 C 907.591724 551.993988 905.133802 555.001801 902.051646 555.001801 C 899.640542 555.001801 898.40768 553.915863 894.404779 548.251801 C 891.782996 544.540863 887.655248 538.900238 885.236341 535.720551 C 885.236341 535.720551 880.843294 529.947113 880.843294 529.947113 C 880.843294 529.947113 875.919648 534.064301 875.919648 534.064301 C 875.919648 534.064301 871.003804 538.189301 871.003804 538.189301 C 871.003804 538.189301 871.003804 544.658051 871.003804 544.658051 C 871.003804 548.220551 870.535629 552.001801 869.966015 553.064301 C 868.709744 555.408051 864.745858 555.603363 862.568842 553.431488 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path></svg></a><div class=\"my-4\"><span class=\"block w-full h-px bg-outline dark:bg-outline-dark\"></span></div><div x-data @htmx:pushed-url.window=\"$store.nav.path = $event.detail.path\" class=\"flex flex-col gap-2 overflow-y-auto pb-6\"><a href=\"/home\" hx-get=\"/home\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/home'\" :class=\"$store.nav.path === '/home'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M9.293 2.293a1 1 0 0 1 1.414 0l7 7A1 1 0 0 1 17 11h-1v6a1 1 0 0 1-1 1h-2a1 1 0 0 1-1-1v-3a1 1 0 0 0-1-1H9a1 1 0 0 0-1 1v3a1 1 0 0 1-1 1H5a1 1 0 0 1-1-1v-6H3a1 1 0 0 1-.707-1.707l7-7Z\" clip-rule=\"evenodd\"></path></svg> <span>Home</span></a> <a href=\"/households\" hx-get=\"/households\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/households'\" :class=\"$store.nav.path === '/households'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path d=\"M10 9a3 3 0 1 0 0-6 3 3 0 0 0 0 6ZM6 8a2 2 0 1 1-4 0 2 2 0 0 1 4 0ZM1.49 15.326a.78.78 0 0 1-.358-.442 3 3 0 0 1 4.308-3.516 6.484 6.484 0 0 0-1.905 3.959c-.023.222-.014.442.025.654a4.97 4.97 0 0 1-2.07-.655ZM16.44 15.98a4.97 4.97 0 0 0 2.07-.654.78.78 0 0 0 .357-.442 3 3 0 0 0-4.308-3.517 6.484 6.484 0 0 1 1.907 3.96 2.32 2.32 0 0 1-.026.654ZM18 8a2 2 0 1 1-4 0 2 2 0 0 1 4 0ZM5.304 16.19a.844.844 0 0 1-.277-.71 5 5 0 0 1 9.947 0 .843.843 0 0 1-.277.71A6.975 6.975 0 0 1 10 18a6.974 6.974 0 0 1-4.696-1.81Z\"></path></svg> <span>Households</span></a> <a href=\"/expenses\" hx-get=\"/expenses\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/expenses'\" :class=\"$store.nav.path === '/expenses'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M1 4a1 1 0 0 1 1-1h16a1 1 0 0 1 1 1v8a1 1 0 0 1-1 1H2a1 1 0 0 1-1-1V4Zm12 4a3 3 0 1 1-6 0 3 3 0 0 1 6 0ZM4 9a1 1 0 1 0 0-2 1 1 0 0 0 0 2Zm13-1a1 1 0 1 1-2 0 1 1 0 0 1 2 0ZM1.75 14.5a.75.75 0 0 0 0 1.5c4.417 0 8.693.603 12.749 1.73 1.111.309 2.251-.512 2.251-1.696v-.784a.75.75 0 0 0-1.5 0v.784a.272.272 0 0 1-.35.25A49.043 49.043 0 0 0 1.75 14.5Z\" clip-rule=\"evenodd\"></path></svg> <span>Expenses</span></a> <a href=\"/reports\" hx-get=\"/reports\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/reports'\" :class=\"$store.nav.path === '/reports'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M1 2.75A.75.75 0 0 1 1.75 2h16.5a.75.75 0 0 1 0 1.5H18v8.75A2.75 2.75 0 0 1 15.25 15h-1.072l.798 3.06a.75.75 0 0 1-1.452.38L13.41 18H6.59l-.114.44a.75.75 0 0 1-1.452-.38L5.823 15H4.75A2.75 2.75 0 0 1 2 12.25V3.5h-.25A.75.75 0 0 1 1 2.75ZM7.373 15l-.391 1.5h6.037l-.392-1.5H7.373Zm7.49-8.931a.75.75 0 0 1-.175 1.046 19.326 19.326 0 0 0-3.398 3.098.75.75 0 0 1-1.097.04L8.5 8.561l-2.22 2.22A.75.75 0 1 1 5.22 9.72l2.75-2.75a.75.75 0 0 1 1.06 0l1.664 1.663a20.786 20.786 0 0 1 3.122-2.74.75.75 0 0 1 1.046.176Z\" clip-rule=\"evenodd\"></path></svg> <span>Reports</span></a></div><div x-data=\"{ menuIsOpen: false }\" class=\"mt-auto\" x-on:keydown.esc.window=\"menuIsOpen = false\"><button type=\"button\" class=\"flex w-full items-center rounded-radius gap-2 p-2 text-left text-on-surface hover:bg-primary/5 hover:text-on-surface-strong focus-visible:outline-2 focus-visible:outline-offset-2 focus-visible:outline-primary dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong dark:focus-visible:outline-primary-dark\" x-bind:class=\"menuIsOpen ? 'bg-primary/10 dark:bg-primary-dark/10' : ''\" aria-haspopup=\"true\" x-on:click=\"menuIsOpen = ! menuIsOpen\" x-bind:aria-expanded=\"menuIsOpen\"><img src=\"/static/img/user-avatar.png\" class=\"size-8 object-cover rounded-radius\" alt=\"avatar\" aria-hidden=\"true\"><div class=\"flex flex-col\"><span class=\"text-sm font-bold text-on-surface-strong dark:text-on-surface-dark-strong\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<a class=\"sr-only\" href=\"#main-content\">skip to the main content</a><div x-cloak x-show=\"showSidebar\" class=\"fixed inset-0 z-10 bg-surface-dark/10 backdrop-blur-xs md:hidden\" aria-hidden=\"true\" x-on:click=\"showSidebar = false\" x-transition.opacity></div><nav x-cloak class=\"fixed left-0 z-20 flex h-svh w-56 shrink-0 flex-col border-r border-outline bg-surface-alt p-4 transition-transform duration-300 md:w-56 md:translate-x-0 md:relative dark:border-outline-dark dark:bg-surface-dark-alt\" x-bind:class=\"showSidebar ? 'translate-x-0' : '-translate-x-56'\" aria-label=\"sidebar navigation\"><a href=\"/\" class=\"p-2 w-fit text-2xl font-bold text-on-surface-strong dark:text-on-surface-dark-strong\"><span class=\"sr-only\">homepage</span> <svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 408 95\" class=\"w-full max-w-lg h-auto\" role=\"img\"><path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 487.310531 588.587738 C 483.900653 587.048676 483.799215 586.806488 480.046007 571.533051 C 478.21232 564.048676 477.75975 563.275238 472.648833 558.790863 C 461.935416 549.392426 455.061039 535.337738 455.022024 522.751801 C 455.006418 517.345551 454.694301 516.001801 453.461439 516.001801 C 452.61092 516.001801 450.152998 515.103363 447.999391 514.001801 C 445.845783 512.900238 442.599766 511.978363 440.789487 511.947113 C 438.979208 511.915863 435.928264 511.353363 434.000942 510.697113 C 431.254312 509.759613 430.497428 508.962738 430.497428 507.001801 C 430.497428 504.978363 430.973406 504.517426 433.002167 504.587738 C 434.375482 504.642426 436.177958 504.861176 436.997265 505.087738 C 437.972631 505.353363 438.331566 504.970551 438.019449 504.001801 C 437.754149 503.173676 437.527864 500.392426 437.527864 497.822113 C 437.512258 492.626801 440.251085 488.658051 444.605118 487.564301 C 447.460989 486.845551 452.501679 489.204926 453.968629 491.947113 C 456.325113 496.345551 454.522637 504.439301 450.55875 507.251801 C 449.856487 507.751801 450.004743 508.329926 451.003517 508.986176 C 454.062264 510.993988 455.521411 510.025238 457.386311 504.751801 C 462.029051 491.634613 473.772455 479.611176 489.49535 471.900238 C 494.98861 469.204926 500.278994 467.001801 501.246557 467.001801 C 503.977581 467.001801 503.322135 465.361176 499.670366 463.103363 C 494.80134 460.095551 490.993512 452.509613 491.024724 445.861176 C 491.102753 425.876801 513.832676 416.736176 527.792111 431.079926 C 535.134664 438.634613 535.345343 452.126801 528.24468 460.228363 C 526.332963 462.408051 526.925986 462.626801 536.999563 463.439301 C 541.127311 463.775238 547.252608 464.556488 550.623472 465.173676 C 556.514681 466.259613 556.873615 466.197113 560.174253 463.681488 C 568.226872 457.540863 584.230673 452.829926 585.533762 456.220551 C 585.845879 457.040863 585.18263 461.236176 584.051206 465.540863 C 582.927585 469.853363 581.999036 474.228363 581.999036 475.267426 C 581.999036 476.298676 585.081192 480.150238 588.850005 483.822113 C 592.634424 487.517426 597.214742 493.267426 599.095247 496.697113 C 599.095247 496.697113 602.497323 502.900238 602.497323 502.900238 C 602.497323 502.900238 609.270262 504.564301 609.270262 504.564301 C 618.485518 506.822113 619.016117 507.751801 618.94589 521.470551 C 618.914679 527.540863 618.352868 534.072113 617.697422 536.001801 C 616.589407 539.243988 615.855932 539.720551 607.818918 542.501801 C 599.937963 545.220551 598.767524 545.978363 595.131361 550.642426 C 591.596635 555.181488 586.532536 559.970551 579.213392 565.712738 C 577.668413 566.923676 576.170251 570.243988 574.570651 576.001801 C 571.270013 587.853363 571.270013 587.845551 566.650681 589.103363 C 562.655583 590.181488 549.913405 589.681488 547.767601 588.361176 C 547.229199 588.025238 545.980731 585.939301 544.989759 583.728363 C 544.989759 583.728363 543.187283 579.704926 543.187283 579.704926 C 543.187283 579.704926 529.84428 579.486176 529.84428 579.486176 C 522.501727 579.361176 515.68197 579.087738 514.675392 578.884613 C 513.317683 578.595551 512.513982 579.486176 511.499601 582.400238 C 509.299176 588.689301 507.941467 589.470551 498.718409 589.759613 C 493.217346 589.939301 489.448533 589.548676 487.310531 588.587738 M 506.552546 577.751801 C 506.552546 577.751801 508.58911 572.501801 508.58911 572.501801 C 508.58911 572.501801 513.543968 572.728363 513.543968 572.728363 C 521.370303 573.079926 541.244355 573.126801 543.998788 572.798676 C 546.144592 572.540863 546.862461 573.259613 549.000463 577.775238 C 549.000463 577.775238 551.497399 583.056488 551.497399 583.056488 C 551.497399 583.056488 558.910179 582.775238 558.910179 582.775238 C 558.910179 582.775238 566.315155 582.501801 566.315155 582.501801 C 566.315155 582.501801 568.921333 572.611176 568.921333 572.611176 C 568.921333 572.611176 571.52751 562.728363 571.52751 562.728363 C 571.52751 562.728363 577.012967 558.962738 577.012967 558.962738 C 583.052431 554.814301 588.366224 549.603363 592.720257 543.564301 C 595.154769 540.197113 596.723158 539.251801 603.535112 537.064301 C 603.535112 537.064301 611.501899 534.501801 611.501899 534.501801 C 611.501899 534.501801 611.782804 523.228363 611.782804 523.228363 C 611.993483 514.736176 611.751593 511.868988 610.78403 511.572113 C 596.762172 507.353363 597.753144 507.986176 594.085769 501.001801 C 591.908752 496.845551 587.843428 491.704926 582.857358 486.775238 C 574.242928 478.259613 574.718907 479.853363 577.090996 467.603363 C 577.613792 464.908051 577.871289 462.540863 577.66061 462.329926 C 576.88812 461.556488 567.524609 466.181488 562.733613 469.689301 C 562.733613 469.689301 557.802163 473.314301 557.802163 473.314301 C 557.802163 473.314301 550.147493 471.439301 550.147493 471.439301 C 539.60574 468.845551 518.29595 468.806488 508.503278 471.353363 C 489.113007 476.400238 472.313308 488.525238 465.454536 502.400238 C 456.863514 519.775238 460.882021 538.087738 476.66734 553.517426 C 476.66734 553.517426 483.846032 560.540863 483.846032 560.540863 C 483.846032 560.540863 486.491224 570.978363 486.491224 570.978363 C 487.950371 576.720551 489.362701 581.775238 489.628 582.212738 C 489.901103 582.642426 493.357799 583.001801 497.313882 583.001801 C 497.313882 583.001801 504.515983 583.001801 504.515983 583.001801 C 504.515983 583.001801 506.552546 577.751801 506.552546 577.751801 M 448.998165 497.970551 C 448.998165 496.470551 448.280296 494.642426 447.390762 493.908051 C 446.02525 492.775238 445.572681 492.861176 444.394439 494.478363 C 442.732416 496.751801 442.662189 497.993988 444.027701 501.564301 C 445.018673 504.181488 445.112308 504.212738 447.024025 502.478363 C 448.108632 501.493988 448.998165 499.462738 448.998165 497.970551 M 519.7629 459.001801 C 530.53874 452.431488 529.227849 435.923676 517.570278 431.470551 C 509.790761 428.501801 501.558674 432.017426 498.367277 439.673676 C 492.686747 453.251801 507.395262 466.540863 519.7629 459.001801 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 119.199701 553.798676 C 117.483057 552.079926 117.483057 485.915863 119.199701 484.197113 C 121.111418 482.290863 124.747581 482.806488 126.440816 485.220551 C 127.665875 486.962738 128.001401 490.322113 128.001401 500.720551 C 128.001401 500.720551 128.001401 514.001801 128.001401 514.001801 C 128.001401 514.001801 147.976891 514.001801 147.976891 514.001801 C 147.976891 514.001801 167.944579 514.001801 167.944579 514.001801 C 167.944579 514.001801 168.225484 499.353363 168.225484 499.353363 C 168.459572 486.954926 168.756083 484.556488 170.168413 483.665863 C 171.36226 482.915863 172.58732 482.947113 174.421007 483.783051 C 174.421007 483.783051 177.003776 484.954926 177.003776 484.954926 C 177.003776 484.954926 177.003776 518.775238 177.003776 518.775238 C 177.003776 554.431488 176.925746 555.001801 172.423458 555.001801 C 168.623433 555.001801 168.069426 552.962738 167.780717 537.931488 C 167.780717 537.931488 167.499812 523.501801 167.499812 523.501801 C 167.499812 523.501801 147.750607 523.228363 147.750607 523.228363 C 147.750607 523.228363 128.001401 522.954926 128.001401 522.954926 C 128.001401 522.954926 128.001401 537.408051 128.001401 537.408051 C 128.001401 549.212738 127.712693 552.142426 126.42521 553.431488 C 124.560311 555.290863 120.885133 555.486176 119.199701 553.798676 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 210.002349 554.048676 C 203.401074 551.783051 196.050718 544.439301 194.201424 538.283051 C 189.488457 522.556488 197.236762 506.603363 211.625358 502.423676 C 228.737174 497.447113 245.138924 508.454926 246.730721 525.986176 C 248.244489 542.626801 236.03291 556.095551 219.568736 555.970551 C 217.329296 555.947113 213.022081 555.087738 210.002349 554.048676 M 228.003699 545.462738 C 234.105587 542.353363 237.000473 536.868988 237.000473 528.392426 C 237.000473 515.572113 227.582341 507.978363 214.676302 510.400238 C 208.223282 511.611176 202.246241 519.962738 202.082379 528.001801 C 201.785868 542.603363 215.480003 551.822113 228.003699 545.462738 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 370.173008 554.158051 C 348.582312 546.447113 346.030756 515.947113 366.029655 504.603363 C 370.84406 501.868988 372.412448 501.501801 379.122964 501.525238 C 385.817875 501.548676 387.331642 501.915863 391.623251 504.564301 C 399.62125 509.493988 405.528065 521.775238 403.600743 529.470551 C 403.600743 529.470551 402.968706 531.962738 402.968706 531.962738 C 402.968706 531.962738 382.751325 532.228363 382.751325 532.228363 C 360.200869 532.533051 359.912161 532.634613 364.211573 538.954926 C 368.09743 544.665863 371.320038 546.392426 378.865468 546.822113 C 384.663042 547.150238 386.410897 546.822113 390.796141 544.603363 C 396.726365 541.595551 398.271344 541.415863 400.027003 543.533051 C 403.702181 547.954926 393.183837 554.892426 381.635506 555.650238 C 377.289277 555.939301 373.949624 555.501801 370.173008 554.158051 M 395.001918 523.814301 C 395.001918 523.165863 394.065567 520.704926 392.92634 518.353363 C 389.571082 511.423676 382.220726 508.072113 374.714311 510.056488 C 370.157402 511.267426 365.655114 514.908051 363.407871 519.197113 C 360.395942 524.978363 360.458365 525.001801 378.49873 525.001801 C 390.546448 525.001801 395.001918 524.681488 395.001918 523.814301 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 745.501545 555.064301 C 740.663731 553.884613 736.520377 551.353363 733.149513 547.517426 C 728.335108 542.040863 726.719902 537.447113 726.602858 528.931488 C 726.517026 522.368988 726.89937 520.579926 729.51335 515.478363 C 736.910524 500.993988 754.34226 496.751801 765.867181 506.618988 C 765.867181 506.618988 770.002732 510.158051 770.002732 510.158051 C 770.002732 510.158051 770.002732 507.275238 770.002732 507.275238 C 770.002732 503.876801 771.594529 502.001801 774.497217 502.001801 C 778.79663 502.001801 778.999506 503.189301 778.999506 528.579926 C 778.999506 554.517426 778.79663 555.556488 773.904195 554.829926 C 771.742785 554.509613 770.68939 553.259613 769.581374 549.697113 C 769.074184 548.072113 768.645023 548.181488 765.258553 550.861176 C 759.562417 555.361176 752.711448 556.822113 745.501545 555.064301 M 762.012536 544.751801 C 767.396555 541.087738 769.323878 537.673676 769.807659 530.978363 C 770.673784 518.978363 764.907421 511.072113 754.646574 510.220551 C 747.951664 509.665863 743.11385 511.673676 739.384051 516.564301 C 736.754465 520.009613 736.496968 521.064301 736.496968 528.423676 C 736.496968 535.337738 736.840297 536.978363 738.892467 539.845551 C 742.513024 544.931488 746.617363 546.993988 753.101595 546.993988 C 757.268357 547.001801 759.546811 546.423676 762.012536 544.751801 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 797.99963 554.025238 C 796.751162 553.236176 796.446848 549.220551 796.197154 530.236176 C 795.861628 504.783051 796.314198 502.001801 800.777472 502.001801 C 804.257577 502.001801 805.997629 503.626801 808.861303 506.626801 C 811.810809 503.806488 819.332829 500.993988 823.905344 501.009613 C 832.293489 501.025238 840.822087 507.095551 842.88206 514.525238 C 843.490688 516.712738 843.990075 526.173676 843.997878 535.548676 C 843.997878 553.439301 843.638943 555.001801 839.503393 555.001801 C 835.430265 555.001801 835.001104 553.337738 834.993302 537.548676 C 834.985499 520.283051 834.142783 516.142426 829.890188 512.564301 C 825.957513 509.251801 817.233842 508.954926 812.302393 511.962738 C 806.731104 515.353363 805.997629 518.079926 805.997629 535.517426 C 805.997629 544.368988 805.552862 551.970551 804.967643 553.064301 C 803.86743 555.126801 800.48096 555.587738 797.99963 554.025238 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 660.246777 553.517426 C 660.246777 553.517426 657.999534 551.884613 657.999534 551.884613 C 657.999534 551.884613 657.999534 519.142426 657.999534 519.142426 C 657.999534 495.009613 658.319454 486.087738 659.201185 485.197113 C 660.051704 484.353363 665.833672 484.009613 678.95039 484.025238 C 698.668384 484.048676 703.006811 484.908051 707.212588 489.642426 C 709.654903 492.392426 712.003584 498.423676 712.003584 501.947113 C 712.003584 506.501801 709.116502 512.759613 705.847076 515.322113 C 705.847076 515.322113 702.920978 517.611176 702.920978 517.611176 C 702.920978 517.611176 706.674186 520.056488 706.674186 520.056488 C 714.859455 525.376801 717.473435 535.228363 713.025767 543.954926 C 708.734158 552.361176 703.233096 554.181488 680.503172 554.720551 C 665.014365 555.079926 662.189705 554.915863 660.246777 553.517426 M 700.681539 543.408051 C 702.234321 542.259613 704.130432 539.540863 704.895119 537.368988 C 706.135784 533.853363 706.104572 533.017426 704.567396 529.790863 C 701.828569 524.009613 698.481114 523.001801 682.102772 523.001801 C 682.102772 523.001801 668.002885 523.001801 668.002885 523.001801 C 668.002885 523.001801 668.002885 534.572113 668.002885 534.572113 C 668.002885 534.572113 668.002885 546.142426 668.002885 546.142426 C 668.002885 546.142426 682.929882 545.822113 682.929882 545.822113 C 696.218265 545.533051 698.168997 545.267426 700.681539 543.408051 M 695.99198 512.509613 C 699.955867 510.454926 702.000233 507.220551 702.000233 503.001801 C 702.000233 494.595551 696.811288 492.001801 680.034997 492.001801 C 680.034997 492.001801 668.002885 492.001801 668.002885 492.001801 C 668.002885 492.001801 668.002885 503.001801 668.002885 503.001801 C 668.002885 503.001801 668.002885 514.001801 668.002885 514.001801 C 668.002885 514.001801 680.54999 514.001801 680.54999 514.001801 C 689.726231 514.001801 693.877387 513.595551 695.99198 512.509613 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 576.201463 507.798676 C 572.80719 504.408051 576.794485 498.751801 581.000262 501.001801 C 584.527184 502.892426 583.005614 509.001801 579.002713 509.001801 C 578.120982 509.001801 576.856908 508.462738 576.201463 507.798676 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 262.032259 553.064301 C 260.495082 550.189301 260.643338 504.751801 262.19612 503.197113 C 262.859369 502.540863 264.677451 502.001801 266.245839 502.001801 C 268.477475 502.001801 269.374812 502.665863 270.381389 505.056488 C 271.559631 507.861176 271.778113 507.970551 273.081202 506.392426 C 275.203598 503.829926 282.015552 501.048676 286.229132 501.025238 C 291.433683 500.993988 297.613601 503.689301 300.344625 507.189301 C 300.344625 507.189301 302.662094 510.142426 302.662094 510.142426 C 302.662094 510.142426 306.126593 506.673676 306.126593 506.673676 C 314.826855 497.970551 329.722641 499.564301 335.762105 509.845551 C 338.430706 514.376801 338.508735 514.970551 338.844261 532.642426 C 339.218801 552.275238 338.688202 555.001801 334.52144 555.001801 C 329.777261 555.001801 328.996968 552.064301 328.996968 534.212738 C 328.996968 517.298676 328.544399 514.939301 324.635133 511.775238 C 321.545174 509.267426 313.984139 509.439301 310.613275 512.087738 C 305.6116 516.017426 305.002971 518.587738 305.002971 535.517426 C 305.002971 544.368988 304.550402 551.970551 303.965182 553.064301 C 302.708911 555.408051 298.745025 555.603363 296.568009 553.431488 C 295.272723 552.126801 294.99962 549.025238 294.99962 535.392426 C 294.99962 517.595551 294.078875 513.181488 289.896507 510.947113 C 286.736322 509.251801 281.016777 509.423676 277.349402 511.322113 C 272.004398 514.087738 270.997821 517.861176 270.997821 535.181488 C 270.997821 547.478363 270.685704 551.001801 269.445038 552.775238 C 267.517716 555.533051 263.436785 555.689301 262.032259 553.064301 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 501.199739 484.798676 C 497.797664 481.392426 501.44163 479.189301 515.010918 476.439301 C 521.081594 475.204926 526.122284 474.884613 533.090297 475.267426 C 542.945393 475.806488 547.002914 477.017426 547.002914 479.415863 C 547.002914 482.322113 544.529386 483.033051 538.40409 481.884613 C 531.092748 480.517426 519.731688 481.368988 510.219921 484.009613 C 501.714732 486.361176 502.674492 486.275238 501.199739 484.798676 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path> <path stroke=\"currentColor\" fill=\"currentColor\" d=\"M 862.568842 553.431488 C 861.203329 552.064301 861.000453 547.392426 861.000453 517.431488 C 861.000453 484.337738 861.078483 482.923676 868.998452 481.001801 C 870.878958 482.876801 871.003804 484.329926 871.003804 504.501801 C 871.003804 518.689301 871.354936 526.001801 872.041594 526.001801 C 873.024762 526.001801 896.285284 504.962738 897.533752 502.947113 C 898.360862 501.611176 902.371566 501.767426 903.799502 503.197113 C 904.46275 503.861176 905.001152 505.212738 905.001152 506.197113 C 905.001152 508.134613 904.852897 508.290863 894.662276 517.743988 C 894.662276 517.743988 887.936153 523.993988 887.936153 523.993988 C 887.936153 523.993988 896.99535 536.001801 896.99535 536.001801 C 901.98142 542.603363 906.312044 548.642426 906.608555 549.423676 C 907.591724 551.993988 905.133802 555.001801 902.051646 555.001801 C 899.640542 555.001801 898.40768 553.915863 894.404779 548.251801 C 891.782996 544.540863 887.655248 538.900238 885.236341 535.720551 C 885.236341 535.720551 880.843294 529.947113 880.843294 529.947113 C 880.843294 529.947113 875.919648 534.064301 875.919648 534.064301 C 875.919648 534.064301 871.003804 538.189301 871.003804 538.189301 C 871.003804 538.189301 871.003804 544.658051 871.003804 544.658051 C 871.003804 548.220551 870.535629 552.001801 869.966015 553.064301 C 868.709744 555.408051 864.745858 555.603363 862.568842 553.431488 \" transform=\"matrix(0.500613,0,0,0.5,-51.262823,-207.871994)\"></path></svg></a><div class=\"my-4\"><span class=\"block w-full h-px bg-outline dark:bg-outline-dark\"></span></div><div x-data @htmx:pushed-url.window=\"$store.nav.path = $event.detail.path\" class=\"flex flex-col gap-2 overflow-y-auto pb-6\"><a href=\"/home\" hx-get=\"/home\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/home'\" :class=\"$store.nav.path === '/home'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M9.293 2.293a1 1 0 0 1 1.414 0l7 7A1 1 0 0 1 17 11h-1v6a1 1 0 0 1-1 1h-2a1 1 0 0 1-1-1v-3a1 1 0 0 0-1-1H9a1 1 0 0 0-1 1v3a1 1 0 0 1-1 1H5a1 1 0 0 1-1-1v-6H3a1 1 0 0 1-.707-1.707l7-7Z\" clip-rule=\"evenodd\"></path></svg> <span>Home</span></a> <a href=\"/households\" hx-get=\"/households\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/households'\" :class=\"$store.nav.path === '/households'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path d=\"M10 9a3 3 0 1 0 0-6 3 3 0 0 0 0 6ZM6 8a2 2 0 1 1-4 0 2 2 0 0 1 4 0ZM1.49 15.326a.78.78 0 0 1-.358-.442 3 3 0 0 1 4.308-3.516 6.484 6.484 0 0 0-1.905 3.959c-.023.222-.014.442.025.654a4.97 4.97 0 0 1-2.07-.655ZM16.44 15.98a4.97 4.97 0 0 0 2.07-.654.78.78 0 0 0 .357-.442 3 3 0 0 0-4.308-3.517 6.484 6.484 0 0 1 1.907 3.96 2.32 2.32 0 0 1-.026.654ZM18 8a2 2 0 1 1-4 0 2 2 0 0 1 4 0ZM5.304 16.19a.844.844 0 0 1-.277-.71 5 5 0 0 1 9.947 0 .843.843 0 0 1-.277.71A6.975 6.975 0 0 1 10 18a6.974 6.974 0 0 1-4.696-1.81Z\"></path></svg> <span>Households</span></a> <a href=\"/expenses\" hx-get=\"/expenses\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/expenses'\" :class=\"$store.nav.path === '/expenses'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M1 4a1 1 0 0 1 1-1h16a1 1 0 0 1 1 1v8a1 1 0 0 1-1 1H2a1 1 0 0 1-1-1V4Zm12 4a3 3 0 1 1-6 0 3 3 0 0 1 6 0ZM4 9a1 1 0 1 0 0-2 1 1 0 0 0 0 2Zm13-1a1 1 0 1 1-2 0 1 1 0 0 1 2 0ZM1.75 14.5a.75.75 0 0 0 0 1.5c4.417 0 8.693.603 12.749 1.73 1.111.309 2.251-.512 2.251-1.696v-.784a.75.75 0 0 0-1.5 0v.784a.272.272 0 0 1-.35.25A49.043 49.043 0 0 0 1.75 14.5Z\" clip-rule=\"evenodd\"></path></svg> <span>Expenses</span></a> <a href=\"/reports\" hx-get=\"/reports\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"$store.nav.path = '/reports'\" :class=\"$store.nav.path === '/reports'\n                  ? 'bg-primary/10 text-on-surface-strong focus-visible:underline dark:bg-primary-dark/10 dark:text-on-surface-dark-strong'\n                  : 'text-on-surface hover:bg-primary/5 hover:text-on-surface-strong dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong'\" class=\"flex flex-col justify-between items-center rounded-radius gap-2 p-4 text-lg font-semibold focus:outline-hidden w-full\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"w-10 h-10\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M1 2.75A.75.75 0 0 1 1.75 2h16.5a.75.75 0 0 1 0 1.5H18v8.75A2.75 2.75 0 0 1 15.25 15h-1.072l.798 3.06a.75.75 0 0 1-1.452.38L13.41 18H6.59l-.114.44a.75.75 0 0 1-1.452-.38L5.823 15H4.75A2.75 2.75 0 0 1 2 12.25V3.5h-.25A.75.75 0 0 1 1 2.75ZM7.373 15l-.391 1.5h6.037l-.392-1.5H7.373Zm7.49-8.931a.75.75 0 0 1-.175 1.046 19.326 19.326 0 0 0-3.398 3.098.75.75 0 0 1-1.097.04L8.5 8.561l-2.22 2.22A.75.75 0 1 1 5.22 9.72l2.75-2.75a.75.75 0 0 1 1.06 0l1.664 1.663a20.786 20.786 0 0 1 3.122-2.74.75.75 0 0 1 1.046.176Z\" clip-rule=\"evenodd\"></path></svg> <span>Reports</span></a></div><div hx-get=\"/invitations\" hx-trigger=\"load\" hx-swap=\"innerHTML\" class=\"mt-auto mb-2\"></div><div x-data=\"{ menuIsOpen: false }\" x-on:keydown.esc.window=\"menuIsOpen = false\"><button type=\"button\" class=\"flex w-full items-center rounded-radius gap-2 p-2 text-left text-on-surface hover:bg-primary/5 hover:text-on-surface-strong focus-visible:outline-2 focus-visible:outline-offset-2 focus-visible:outline-primary dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong dark:focus-visible:outline-primary-dark\" x-bind:class=\"menuIsOpen ? 'bg-primary/10 dark:bg-primary-dark/10' : ''\" aria-haspopup=\"true\" x-on:click=\"menuIsOpen = ! menuIsOpen\" x-bind:aria-expanded=\"menuIsOpen\"><img src=\"/static/img/user-avatar.png\" class=\"size-8 object-cover rounded-radius\" alt=\"avatar\" aria-hidden=\"true\"><div class=\"flex flex-col\"><span class=\"text-sm font-bold text-on-surface-strong dark:text-on-surface-dark-strong\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var4 string
 		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(user.Username)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/layout.templ`, Line: 124, Col: 108}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/layout.templ`, Line: 125, Col: 108}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
@@ -93,13 +93,13 @@ func sidebar(user *store.User) templ.Component {
 		var templ_7745c5c3_Var5 string
 		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(user.Email)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/layout.templ`, Line: 126, Col: 18}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templ/layout.templ`, Line: 127, Col: 18}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</span> <span class=\"sr-only\">profile settings</span></div><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 24 24\" stroke=\"currentColor\" fill=\"none\" stroke-width=\"2\" class=\"ml-auto size-4 shrink-0 -rotate-90 md:rotate-0\" aria-hidden=\"true\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" d=\"m8.25 4.5 7.5 7.5-7.5 7.5\"></path></svg></button><div x-cloak x-show=\"menuIsOpen\" class=\"absolute bottom-20 right-6 z-20 -mr-1 w-48 border divide-y divide-outline border-outline bg-surface dark:divide-outline-dark dark:border-outline-dark dark:bg-surface-dark rounded-radius md:-right-44 md:bottom-4\" role=\"menu\" x-on:click.outside=\"menuIsOpen = false\" x-on:keydown.down.prevent=\"$focus.wrap().next()\" x-on:keydown.up.prevent=\"$focus.wrap().previous()\" x-transition=\"\" x-trap=\"menuIsOpen\"><div class=\"flex flex-col py-1.5\"><a href=\"#\" hx-post=\"/logout\" hx-trigger=\"click\" hx-swap=\"none\" class=\"flex items-center gap-2 px-2 py-1.5 text-sm font-medium text-on-surface underline-offset-2 hover:bg-primary/5 hover:text-on-surface-strong focus-visible:underline focus:outline-hidden dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong\" role=\"menuitem\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"size-5 shrink-0\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M3 4.25A2.25 2.25 0 0 1 5.25 2h5.5A2.25 2.25 0 0 1 13 4.25v2a.75.75 0 0 1-1.5 0v-2a.75.75 0 0 0-.75-.75h-5.5a.75.75 0 0 0-.75.75v11.5c0 .414.336.75.75.75h5.5a.75.75 0 0 0 .75-.75v-2a.75.75 0 0 1 1.5 0v2A2.25 2.25 0 0 1 10.75 18h-5.5A2.25 2.25 0 0 1 3 15.75V4.25Z\" clip-rule=\"evenodd\"></path> <path fill-rule=\"evenodd\" d=\"M6 10a.75.75 0 0 1 .75-.75h9.546l-1.048-.943a.75.75 0 1 1 1.004-1.114l2.5 2.25a.75.75 0 0 1 0 1.114l-2.5 2.25a.75.75 0 1 1-1.004-1.114l1.048-.943H6.75A.75.75 0 0 1 6 10Z\" clip-rule=\"evenodd\"></path></svg> <span>Sign Out</span></a></div></div></div></nav>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</span> <span class=\"sr-only\">profile settings</span></div><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 24 24\" stroke=\"currentColor\" fill=\"none\" stroke-width=\"2\" class=\"ml-auto size-4 shrink-0 -rotate-90 md:rotate-0\" aria-hidden=\"true\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" d=\"m8.25 4.5 7.5 7.5-7.5 7.5\"></path></svg></button><div x-cloak x-show=\"menuIsOpen\" class=\"absolute bottom-20 right-6 z-20 -mr-1 w-48 border divide-y divide-outline border-outline bg-surface dark:divide-outline-dark dark:border-outline-dark dark:bg-surface-dark rounded-radius md:-right-44 md:bottom-4\" role=\"menu\" x-on:click.outside=\"menuIsOpen = false\" x-on:keydown.down.prevent=\"$focus.wrap().next()\" x-on:keydown.up.prevent=\"$focus.wrap().previous()\" x-transition=\"\" x-trap=\"menuIsOpen\"><div class=\"flex flex-col py-1.5\"><a href=\"/exchange-rates\" hx-get=\"/exchange-rates\" hx-target=\"#swap-content\" hx-swap=\"innerHTML\" hx-push-url=\"true\" @click=\"menuIsOpen = false; $store.nav.path = '/exchange-rates'\" class=\"flex items-center gap-2 px-2 py-1.5 text-sm font-medium text-on-surface underline-offset-2 hover:bg-primary/5 hover:text-on-surface-strong focus-visible:underline focus:outline-hidden dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong\" role=\"menuitem\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"size-5 shrink-0\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M13.2 2.24a.75.75 0 0 0 .04 1.06l2.1 1.95H6.75a.75.75 0 0 0 0 1.5h8.59l-2.1 1.95a.75.75 0 1 0 1.02 1.1l3.5-3.25a.75.75 0 0 0 0-1.1l-3.5-3.25a.75.75 0 0 0-1.06.04Zm-6.4 8a.75.75 0 0 0-1.06-.04l-3.5 3.25a.75.75 0 0 0 0 1.1l3.5 3.25a.75.75 0 1 0 1.02-1.1l-2.1-1.95h8.59a.75.75 0 0 0 0-1.5H4.66l2.1-1.95a.75.75 0 0 0 .04-1.06Z\" clip-rule=\"evenodd\"></path></svg> <span>Exchange rates</span></a> <a href=\"#\" hx-post=\"/logout\" hx-trigger=\"click\" hx-swap=\"none\" class=\"flex items-center gap-2 px-2 py-1.5 text-sm font-medium text-on-surface underline-offset-2 hover:bg-primary/5 hover:text-on-surface-strong focus-visible:underline focus:outline-hidden dark:text-on-surface-dark dark:hover:bg-primary-dark/5 dark:hover:text-on-surface-dark-strong\" role=\"menuitem\"><svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 20 20\" fill=\"currentColor\" class=\"size-5 shrink-0\" aria-hidden=\"true\"><path fill-rule=\"evenodd\" d=\"M3 4.25A2.25 2.25 0 0 1 5.25 2h5.5A2.25 2.25 0 0 1 13 4.25v2a.75.75 0 0 1-1.5 0v-2a.75.75 0 0 0-.75-.75h-5.5a.75.75 0 0 0-.75.75v11.5c0 .414.336.75.75.75h5.5a.75.75 0 0 0 .75-.75v-2a.75.75 0 0 1 1.5 0v2A2.25 2.25 0 0 1 10.75 18h-5.5A2.25 2.25 0 0 1 3 15.75V4.25Z\" clip-rule=\"evenodd\"></path> <path fill-rule=\"evenodd\" d=\"M6 10a.75.75 0 0 1 .75-.75h9.546l-1.048-.943a.75.75 0 1 1 1.004-1.114l2.5 2.25a.75.75 0 0 1 0 1.114l-2.5 2.25a.75.75 0 1 1-1.004-1.114l1.048-.943H6.75A.75.75 0 0 1 6 10Z\" clip-rule=\"evenodd\"></path></svg> <span>Sign Out</span></a></div></div></div></nav>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}